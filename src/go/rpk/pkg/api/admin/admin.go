@@ -65,11 +65,8 @@ type AdminAPI struct {
 }
 
 func getBasicCredentials(cfg *config.Config) BasicCredentials {
-	if cfg.Rpk.KafkaAPI.SASL != nil {
-		return BasicCredentials{Username: cfg.Rpk.KafkaAPI.SASL.User, Password: cfg.Rpk.KafkaAPI.SASL.Password}
-	} else {
-		return BasicCredentials{Username: "", Password: ""}
-	}
+	user, pass := cfg.Rpk.KafkaAPI.SASL.BasicCredentials()
+	return BasicCredentials{Username: user, Password: pass}
 }
 
 // NewClient returns an AdminAPI client that talks to each of the addresses in
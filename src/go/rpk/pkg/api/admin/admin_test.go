@@ -70,6 +70,28 @@ func TestAdminAPI(t *testing.T) {
 			any:    []string{"/v1/partitions/redpanda/controller/0"},
 			leader: []string{"/v1/security/users"},
 		},
+		{
+			name:     "update user in 3 node cluster",
+			nNodes:   3,
+			leaderID: 2,
+			action: func(t *testing.T, a *AdminAPI) error {
+				return a.UpdateUser(context.Background(), "Joss", "momorocks2", ScramSha512)
+			},
+			all:    []string{"/v1/node_config"},
+			any:    []string{"/v1/partitions/redpanda/controller/0"},
+			leader: []string{"/v1/security/users/Joss"},
+		},
+		{
+			name:     "update partition replicas in 3 node cluster",
+			nNodes:   3,
+			leaderID: 2,
+			action: func(t *testing.T, a *AdminAPI) error {
+				return a.UpdatePartitionReplicas(context.Background(), "kafka", "foo", 0, []Replica{{NodeID: 0, Core: 1}})
+			},
+			all:    []string{"/v1/node_config"},
+			any:    []string{"/v1/partitions/redpanda/controller/0"},
+			leader: []string{"/v1/partitions/kafka/foo/0/replicas"},
+		},
 		{
 			name:     "list users in 3 node cluster",
 			nNodes:   3,
@@ -32,6 +32,23 @@ type Partition struct {
 	Replicas    []Replica `json:"replicas"`
 }
 
+// PartitionStatusDone is the status of a partition that is not being
+// reconfigured.
+const PartitionStatusDone = "done"
+
+// ReconfigurationsResponse is the information of an ongoing partition
+// reconfiguration, i.e. a partition movement.
+type ReconfigurationsResponse struct {
+	Namespace        string    `json:"ns"`
+	Topic            string    `json:"topic"`
+	PartitionID      int       `json:"partition"`
+	PreviousReplicas []Replica `json:"previous_replicas"`
+	NewReplicas      []Replica `json:"current_replicas"`
+	PartitionSize    int64     `json:"partition_size"`
+	BytesMoved       int64     `json:"bytes_moved"`
+	BytesLeft        int64     `json:"bytes_left_to_move"`
+}
+
 // GetPartition returns detailed partition information.
 func (a *AdminAPI) GetPartition(
 	ctx context.Context, namespace, topic string, partition int,
@@ -44,3 +61,38 @@ func (a *AdminAPI) GetPartition(
 		nil,
 		&pa)
 }
+
+// UpdatePartitionReplicas requests that the replicas of a partition be moved
+// to the given node and core assignments. The move happens asynchronously;
+// GetPartition or Reconfigurations can be used to monitor its progress.
+func (a *AdminAPI) UpdatePartitionReplicas(
+	ctx context.Context, namespace, topic string, partition int, replicas []Replica,
+) error {
+	return a.sendToLeader(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("/v1/partitions/%s/%s/%d/replicas", namespace, topic, partition),
+		replicas,
+		nil)
+}
+
+// Reconfigurations returns the partition reconfigurations that are in
+// progress in the cluster.
+func (a *AdminAPI) Reconfigurations(ctx context.Context) ([]ReconfigurationsResponse, error) {
+	var rr []ReconfigurationsResponse
+	return rr, a.sendAny(ctx, http.MethodGet, "/v1/partitions/reconfigurations", nil, &rr)
+}
+
+// GetTopicPartitions returns detailed information of all partitions of a
+// topic.
+func (a *AdminAPI) GetTopicPartitions(
+	ctx context.Context, namespace, topic string,
+) ([]Partition, error) {
+	var pas []Partition
+	return pas, a.sendAny(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("/v1/partitions/%s/%s", namespace, topic),
+		nil,
+		&pas)
+}
@@ -60,6 +60,23 @@ func (a *AdminAPI) DeleteUser(ctx context.Context, username string) error {
 	return a.sendToLeader(ctx, http.MethodDelete, path, nil, nil)
 }
 
+// UpdateUser updates the password and mechanism of the given username.
+func (a *AdminAPI) UpdateUser(ctx context.Context, username, password, mechanism string) error {
+	if username == "" {
+		return errors.New("invalid empty username")
+	}
+	if password == "" {
+		return errors.New("invalid empty password")
+	}
+	u := newUser{
+		User:      username,
+		Password:  password,
+		Algorithm: mechanism,
+	}
+	path := usersEndpoint + "/" + url.PathEscape(username)
+	return a.sendToLeader(ctx, http.MethodPut, path, u, nil)
+}
+
 // ListUsers returns the current users.
 func (a *AdminAPI) ListUsers(ctx context.Context) ([]string, error) {
 	var users []string
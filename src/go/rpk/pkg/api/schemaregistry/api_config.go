@@ -0,0 +1,126 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CompatibilityLevels are the compatibility levels supported by the schema
+// registry.
+var CompatibilityLevels = []string{
+	"NONE",
+	"BACKWARD",
+	"BACKWARD_TRANSITIVE",
+	"FORWARD",
+	"FORWARD_TRANSITIVE",
+	"FULL",
+	"FULL_TRANSITIVE",
+}
+
+// Modes are the modes supported by the schema registry.
+var Modes = []string{
+	"READWRITE",
+	"READONLY",
+	"IMPORT",
+}
+
+// normalizeEnum uppercases s and ensures it is one of the allowed values.
+func normalizeEnum(what, s string, allowed []string) (string, error) {
+	upper := strings.ToUpper(s)
+	for _, a := range allowed {
+		if a == upper {
+			return upper, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s %q, allowed: %s", what, s, strings.Join(allowed, ", "))
+}
+
+// If subject is empty, the global path is used.
+func configPath(base, subject string) string {
+	if subject == "" {
+		return base
+	}
+	return base + "/" + url.PathEscape(subject)
+}
+
+// Compatibility returns the compatibility level for the subject, or the global
+// compatibility level if subject is empty. If the subject has no level set,
+// this returns the global level.
+func (c *Client) Compatibility(ctx context.Context, subject string) (string, error) {
+	var resp struct {
+		Level string `json:"compatibilityLevel"`
+	}
+	path := configPath("/config", subject)
+	if subject != "" {
+		path += "?defaultToGlobal=true"
+	}
+	return resp.Level, c.send(ctx, http.MethodGet, path, nil, &resp)
+}
+
+// SetCompatibility sets the compatibility level for the subject, or the global
+// compatibility level if subject is empty.
+func (c *Client) SetCompatibility(ctx context.Context, subject, level string) (string, error) {
+	level, err := normalizeEnum("compatibility level", level, CompatibilityLevels)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Level string `json:"compatibility"`
+	}
+	body := struct {
+		Level string `json:"compatibility"`
+	}{level}
+	return resp.Level, c.send(ctx, http.MethodPut, configPath("/config", subject), body, &resp)
+}
+
+// CheckCompatibility returns whether the schema is compatible with the schema
+// registered under subject at the given version, per the subject's
+// compatibility level.
+func (c *Client) CheckCompatibility(
+	ctx context.Context, subject, version string, s Schema,
+) (bool, error) {
+	if err := ValidateVersion(version); err != nil {
+		return false, err
+	}
+	var resp struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	return resp.IsCompatible, c.send(ctx, http.MethodPost, "/compatibility"+versionPath(subject, version), s, &resp)
+}
+
+// Mode returns the mode for the subject, or the global mode if subject is
+// empty.
+func (c *Client) Mode(ctx context.Context, subject string) (string, error) {
+	var resp struct {
+		Mode string `json:"mode"`
+	}
+	return resp.Mode, c.send(ctx, http.MethodGet, configPath("/mode", subject), nil, &resp)
+}
+
+// SetMode sets the mode for the subject, or the global mode if subject is
+// empty.
+func (c *Client) SetMode(ctx context.Context, subject, mode string) (string, error) {
+	mode, err := normalizeEnum("mode", mode, Modes)
+	if err != nil {
+		return "", err
+	}
+	body := struct {
+		Mode string `json:"mode"`
+	}{mode}
+	var resp struct {
+		Mode string `json:"mode"`
+	}
+	return resp.Mode, c.send(ctx, http.MethodPut, configPath("/mode", subject), body, &resp)
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// TypeAvro is the Avro schema type, the default if no type is
+	// specified when creating a schema.
+	TypeAvro = "AVRO"
+	// TypeProtobuf is the Protobuf schema type.
+	TypeProtobuf = "PROTOBUF"
+	// TypeJSON is the JSON Schema schema type.
+	TypeJSON = "JSON"
+)
+
+// VersionLatest can be used in place of a numeric version to refer to the
+// latest version of a subject.
+const VersionLatest = "latest"
+
+// SchemaReference is a reference from one schema to a schema registered under
+// another subject.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema is a schema, its type, and any schemas it references.
+type Schema struct {
+	Schema     string            `json:"schema"`
+	Type       string            `json:"schemaType,omitempty"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// SubjectSchema is a schema registered under a subject at a version.
+type SubjectSchema struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+	ID      int    `json:"id"`
+	Schema
+}
+
+// SchemaType returns the type of the schema, defaulting to Avro.
+func (s *Schema) SchemaType() string {
+	if s.Type == "" {
+		return TypeAvro
+	}
+	return s.Type
+}
+
+func subjectPath(subject string) string {
+	return "/subjects/" + url.PathEscape(subject)
+}
+
+func versionPath(subject, version string) string {
+	return subjectPath(subject) + "/versions/" + url.PathEscape(version)
+}
+
+// ValidateVersion returns an error if version is not "latest", -1, or a
+// positive number.
+func ValidateVersion(version string) error {
+	if version == VersionLatest || version == "-1" {
+		return nil
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || v <= 0 {
+		return fmt.Errorf("invalid version %q: must be %q or a positive number", version, VersionLatest)
+	}
+	return nil
+}
+
+func withParam(path, key string, value bool) string {
+	if !value {
+		return path
+	}
+	return path + "?" + key + "=true"
+}
+
+// Subjects returns all subjects, optionally including soft-deleted subjects.
+func (c *Client) Subjects(ctx context.Context, deleted bool) ([]string, error) {
+	var subjects []string
+	return subjects, c.send(ctx, http.MethodGet, withParam("/subjects", "deleted", deleted), nil, &subjects)
+}
+
+// SubjectVersions returns all versions of a subject, optionally including
+// soft-deleted versions.
+func (c *Client) SubjectVersions(
+	ctx context.Context, subject string, deleted bool,
+) ([]int, error) {
+	var versions []int
+	return versions, c.send(ctx, http.MethodGet, withParam(subjectPath(subject)+"/versions", "deleted", deleted), nil, &versions)
+}
+
+// DeleteSubject deletes a subject and returns the versions that were deleted.
+// A subject must be soft deleted before it can be permanently deleted.
+func (c *Client) DeleteSubject(
+	ctx context.Context, subject string, permanent bool,
+) ([]int, error) {
+	if subject == "" {
+		return nil, errors.New("invalid empty subject")
+	}
+	var versions []int
+	return versions, c.send(ctx, http.MethodDelete, withParam(subjectPath(subject), "permanent", permanent), nil, &versions)
+}
+
+// DeleteSubjectVersion deletes a single version of a subject and returns the
+// deleted version.
+func (c *Client) DeleteSubjectVersion(
+	ctx context.Context, subject, version string, permanent bool,
+) (int, error) {
+	if err := ValidateVersion(version); err != nil {
+		return 0, err
+	}
+	var deleted int
+	return deleted, c.send(ctx, http.MethodDelete, withParam(versionPath(subject, version), "permanent", permanent), nil, &deleted)
+}
+
+// CreateSchema registers a schema under the given subject and returns the
+// schema's ID. If the schema already exists under the subject, this returns
+// the ID of the existing schema.
+func (c *Client) CreateSchema(
+	ctx context.Context, subject string, s Schema,
+) (int, error) {
+	if subject == "" {
+		return 0, errors.New("invalid empty subject")
+	}
+	var resp struct {
+		ID int `json:"id"`
+	}
+	return resp.ID, c.send(ctx, http.MethodPost, subjectPath(subject)+"/versions", s, &resp)
+}
+
+// SchemaByID returns the schema with the given ID.
+func (c *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	var s Schema
+	return s, c.send(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &s)
+}
+
+// SchemaByVersion returns the schema registered under subject at the given
+// version, which can be "latest".
+func (c *Client) SchemaByVersion(
+	ctx context.Context, subject, version string,
+) (SubjectSchema, error) {
+	var s SubjectSchema
+	if err := ValidateVersion(version); err != nil {
+		return s, err
+	}
+	return s, c.send(ctx, http.MethodGet, versionPath(subject, version), nil, &s)
+}
+
+// LookupSchema checks whether the schema is registered under the subject,
+// returning the registered subject, version, and ID if so.
+func (c *Client) LookupSchema(
+	ctx context.Context, subject string, s Schema,
+) (SubjectSchema, error) {
+	var ss SubjectSchema
+	return ss, c.send(ctx, http.MethodPost, subjectPath(subject), s, &ss)
+}
+
+// SchemaReferencedBy returns the IDs of all schemas that reference the schema
+// registered under subject at the given version.
+func (c *Client) SchemaReferencedBy(
+	ctx context.Context, subject, version string,
+) ([]int, error) {
+	if err := ValidateVersion(version); err != nil {
+		return nil, err
+	}
+	var ids []int
+	return ids, c.send(ctx, http.MethodGet, versionPath(subject, version)+"/referencedby", nil, &ids)
+}
@@ -0,0 +1,235 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package schemaregistry provides a client to interact with Redpanda's schema
+// registry.
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	rpknet "github.com/redpanda-data/redpanda/src/go/rpk/pkg/net"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// DefaultPort is the port the schema registry listens on by default.
+const DefaultPort = 8081
+
+// The schema registry speaks its own versioned json content type; plain
+// application/json is accepted as well, but we prefer to be explicit.
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// HTTPResponseError is returned for any non-2xx response from the schema
+// registry.
+type HTTPResponseError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+// ErrorBody is the JSON decodable body that the schema registry returns on
+// errors.
+type ErrorBody struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// DecodeErrorBody decodes the response body as an ErrorBody.
+func (he *HTTPResponseError) DecodeErrorBody() (ErrorBody, error) {
+	var resp ErrorBody
+	err := json.Unmarshal(he.Body, &resp)
+	return resp, err
+}
+
+func (he *HTTPResponseError) Error() string {
+	if body, err := he.DecodeErrorBody(); err == nil && body.Message != "" {
+		return fmt.Sprintf("request %s %s failed: %s (error code %d)", he.Method, he.URL, body.Message, body.ErrorCode)
+	}
+	return fmt.Sprintf("request %s %s failed: %s, body: %q",
+		he.Method, he.URL, http.StatusText(he.StatusCode), he.Body)
+}
+
+// Client is a client to interact with the schema registry.
+type Client struct {
+	urls     []string
+	cl       *http.Client
+	user     string
+	password string
+}
+
+// NewClient returns a Client that talks to each of the addresses in the
+// rpk.schema_registry_api section of the config. If no addresses are
+// configured, this falls back to the first schema_registry_api listener in
+// the schema_registry section of the config, and then to localhost.
+func NewClient(fs afero.Fs, cfg *config.Config) (*Client, error) {
+	sr := &cfg.Rpk.SchemaRegistryAPI
+	addrs := sr.Addresses
+	if len(addrs) == 0 {
+		addrs = []string{defaultAddress(cfg)}
+	}
+	tc, err := sr.TLS.Config(fs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create schema registry tls config: %v", err)
+	}
+	user, pass := cfg.Rpk.KafkaAPI.SASL.BasicCredentials()
+	return NewSchemaRegistry(addrs, user, pass, tc)
+}
+
+// If the local config has a schema registry listener, we default to it (with
+// unspecified addresses mapped to localhost), otherwise we default to
+// localhost with the default port.
+func defaultAddress(cfg *config.Config) string {
+	host, port := "127.0.0.1", DefaultPort
+	if sr := cfg.SchemaRegistry; sr != nil && len(sr.SchemaRegistryAPI) > 0 {
+		l0 := sr.SchemaRegistryAPI[0]
+		if l0.Address != "" && l0.Address != "0.0.0.0" {
+			host = l0.Address
+		}
+		if l0.Port != 0 {
+			port = l0.Port
+		}
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// NewSchemaRegistry returns a Client for the given urls, optionally using
+// basic authentication if user is non-empty.
+func NewSchemaRegistry(
+	urls []string, user, password string, tlsConfig *tls.Config,
+) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one url is required for the schema registry")
+	}
+	c := &Client{
+		urls:     make([]string, len(urls)),
+		cl:       &http.Client{Timeout: 10 * time.Second},
+		user:     user,
+		password: password,
+	}
+	if tlsConfig != nil {
+		c.cl.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	for i, u := range urls {
+		scheme, host, err := rpknet.ParseHostMaybeScheme(u)
+		if err != nil {
+			return nil, err
+		}
+		switch scheme {
+		case "", "http":
+			scheme = "http"
+			if tlsConfig != nil {
+				scheme = "https"
+			}
+		case "https":
+		default:
+			return nil, fmt.Errorf("unrecognized scheme %q in host %q", scheme, u)
+		}
+		c.urls[i] = fmt.Sprintf("%s://%s", scheme, host)
+	}
+	return c, nil
+}
+
+// send issues a request to one of the client's urls and unmarshals the
+// response body into into, if into is non-nil.
+//
+// Every schema registry instance can serve every request (writes are
+// forwarded internally), so on connection errors and 5xx responses we try the
+// next url. Client errors (4xx) are returned immediately.
+func (c *Client) send(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	shuffled := make([]string, len(c.urls))
+	copy(shuffled, c.urls)
+	rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	var err error
+	for _, u := range shuffled {
+		if err != nil {
+			log.Debugf("Request error, trying another node: %v", err)
+		}
+		var res []byte
+		res, err = c.sendAndReceive(ctx, method, u+path, body)
+		if err == nil {
+			if into == nil {
+				return nil
+			}
+			if err := json.Unmarshal(res, into); err != nil {
+				return fmt.Errorf("unable to decode %s %s response body: %w", method, u+path, err)
+			}
+			return nil
+		}
+		var he *HTTPResponseError
+		if errors.As(err, &he) && he.StatusCode/100 == 4 {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) sendAndReceive(
+	ctx context.Context, method, url string, body interface{},
+) ([]byte, error) {
+	var r io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode request body for %s %s: %w", method, url, err) // should not happen
+		}
+		r = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+
+	res, err := c.cl.Do(req)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s to server %s expected a tls connection: %w", method, url, err)
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s %s response body: %w", method, url, err)
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, &HTTPResponseError{
+			Method:     method,
+			URL:        url,
+			StatusCode: res.StatusCode,
+			Body:       resBody,
+		}
+	}
+	return resBody, nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		handlers map[string]http.HandlerFunc // keyed by "METHOD path"
+		action   func(*testing.T, *Client) error
+		expErr   bool
+	}{
+		{
+			name: "list subjects",
+			handlers: map[string]http.HandlerFunc{
+				"GET /subjects": func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, "true", r.URL.Query().Get("deleted"))
+					w.Write([]byte(`["foo","bar"]`))
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				subjects, err := c.Subjects(context.Background(), true)
+				require.Equal(t, []string{"foo", "bar"}, subjects)
+				return err
+			},
+		},
+		{
+			name: "create schema with escaped subject",
+			handlers: map[string]http.HandlerFunc{
+				"POST /subjects/foo%2Fbar/versions": func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					var s Schema
+					require.NoError(t, json.Unmarshal(body, &s))
+					require.Equal(t, Schema{Schema: `"string"`, Type: TypeProtobuf}, s)
+					w.Write([]byte(`{"id":3}`))
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				id, err := c.CreateSchema(context.Background(), "foo/bar", Schema{Schema: `"string"`, Type: TypeProtobuf})
+				require.Equal(t, 3, id)
+				return err
+			},
+		},
+		{
+			name: "get schema by version",
+			handlers: map[string]http.HandlerFunc{
+				"GET /subjects/foo/versions/latest": func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"subject":"foo","version":2,"id":7,"schema":"\"int\""}`))
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				ss, err := c.SchemaByVersion(context.Background(), "foo", VersionLatest)
+				require.Equal(t, SubjectSchema{Subject: "foo", Version: 2, ID: 7, Schema: Schema{Schema: `"int"`}}, ss)
+				require.Equal(t, TypeAvro, ss.SchemaType())
+				return err
+			},
+		},
+		{
+			name: "invalid version is rejected before sending",
+			action: func(t *testing.T, c *Client) error {
+				_, err := c.SchemaByVersion(context.Background(), "foo", "0")
+				return err
+			},
+			expErr: true,
+		},
+		{
+			name: "set global compatibility",
+			handlers: map[string]http.HandlerFunc{
+				"PUT /config": func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					require.JSONEq(t, `{"compatibility":"FULL_TRANSITIVE"}`, string(body))
+					w.Write(body)
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				level, err := c.SetCompatibility(context.Background(), "", "full_transitive")
+				require.Equal(t, "FULL_TRANSITIVE", level)
+				return err
+			},
+		},
+		{
+			name: "invalid compatibility level",
+			action: func(t *testing.T, c *Client) error {
+				_, err := c.SetCompatibility(context.Background(), "", "sideways")
+				return err
+			},
+			expErr: true,
+		},
+		{
+			name: "check compatibility",
+			handlers: map[string]http.HandlerFunc{
+				"POST /compatibility/subjects/foo/versions/1": func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"is_compatible":true}`))
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				ok, err := c.CheckCompatibility(context.Background(), "foo", "1", Schema{Schema: `"int"`})
+				require.True(t, ok)
+				return err
+			},
+		},
+		{
+			name: "error body is decoded",
+			handlers: map[string]http.HandlerFunc{
+				"GET /mode/foo": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"error_code":40401,"message":"Subject 'foo' not found."}`))
+				},
+			},
+			action: func(t *testing.T, c *Client) error {
+				_, err := c.Mode(context.Background(), "foo")
+				var he *HTTPResponseError
+				require.True(t, errors.As(err, &he))
+				body, derr := he.DecodeErrorBody()
+				require.NoError(t, derr)
+				require.Equal(t, 40401, body.ErrorCode)
+				return err
+			},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, contentType, r.Header.Get("Accept"))
+				user, pass, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "user", user)
+				require.Equal(t, "pass", pass)
+
+				h, ok := tt.handlers[r.Method+" "+r.URL.EscapedPath()]
+				if !ok {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				h(w, r)
+			}))
+			defer ts.Close()
+
+			cl, err := NewSchemaRegistry([]string{ts.URL}, "user", "pass", nil)
+			require.NoError(t, err)
+			err = tt.action(t, cl)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientRetriesOtherURLs(t *testing.T) {
+	var calls int
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Write([]byte(`{"mode":"READWRITE"}`))
+	}))
+	defer good.Close()
+
+	cl, err := NewSchemaRegistry([]string{bad.URL, good.URL}, "", "", nil)
+	require.NoError(t, err)
+	mode, err := cl.Mode(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "READWRITE", mode)
+	require.LessOrEqual(t, calls, 2)
+}
+
+func TestNewClientBasicAuth(t *testing.T) {
+	for _, test := range []struct {
+		mechanism string
+		expAuth   bool
+	}{
+		{config.SASLMechanismScramSha256, true},
+		{config.SASLMechanismPlain, true},
+		{config.SASLMechanismOAuthBearer, false},
+		{"aws_msk_iam", false},
+	} {
+		t.Run(test.mechanism, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, pass, ok := r.BasicAuth()
+				require.Equal(t, test.expAuth, ok)
+				if test.expAuth {
+					require.Equal(t, "user", user)
+					require.Equal(t, "pass", pass)
+				} else {
+					require.Empty(t, r.Header.Get("Authorization"))
+				}
+				w.Write([]byte(`{"mode":"READWRITE"}`))
+			}))
+			defer ts.Close()
+
+			cfg := config.Default()
+			cfg.Rpk.SchemaRegistryAPI.Addresses = []string{ts.URL}
+			cfg.Rpk.KafkaAPI.SASL = &config.SASL{
+				User:      "user",
+				Password:  "pass",
+				Mechanism: test.mechanism,
+			}
+			cl, err := NewClient(afero.NewMemMapFs(), cfg)
+			require.NoError(t, err)
+			_, err = cl.Mode(context.Background(), "")
+			require.NoError(t, err)
+		})
+	}
+}
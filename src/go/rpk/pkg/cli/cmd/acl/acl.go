@@ -71,8 +71,10 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 		&adminAPITruststoreFile,
 	)
 
+	command.AddCommand(newApplyCommand(fs))
 	command.AddCommand(newCreateCommand(fs))
 	command.AddCommand(newDeleteCommand(fs))
+	command.AddCommand(newExportCommand(fs))
 	command.AddCommand(newListCommand(fs))
 	command.AddCommand(newUserCommand(fs))
 	return command
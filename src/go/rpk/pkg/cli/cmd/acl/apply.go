@@ -0,0 +1,246 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"gopkg.in/yaml.v3"
+)
+
+func newApplyCommand(fs afero.Fs) *cobra.Command {
+	var (
+		dry       bool
+		noConfirm bool
+	)
+	cmd := &cobra.Command{
+		Use:   "apply [FILE]",
+		Short: "Create and delete ACLs to match a YAML or JSON file",
+		Long: `Create and delete ACLs to match a YAML or JSON file.
+
+This command reads a file written by 'rpk acl export' and makes the cluster's
+ACLs exactly match it: ACLs in the file that do not exist are created, and ACLs
+that exist but are not in the file are deleted. ACLs that are in both are not
+touched. A file without any ACLs is rejected rather than deleting every ACL;
+use 'rpk acl delete' to delete ACLs.
+
+In the file, principals without a type are User principals, the host defaults
+to '*', and the resource pattern type defaults to literal. Names of resource
+types, pattern types, operations, and permissions are case insensitive.
+
+The ACLs to create and delete are printed, and the command prompts for a
+confirmation before applying them, unless --no-confirm is used. With --dry,
+the changes are printed and nothing is applied.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			raw, err := afero.ReadFile(fs, args[0])
+			out.MaybeDie(err, "unable to read %q: %v", args[0], err)
+			want, err := parseACLFile(raw)
+			out.MaybeDie(err, "unable to parse %q: %v", args[0], err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			current, err := describeAllACLs(cmd.Context(), adm)
+			out.MaybeDieErr(err)
+
+			creations, deletions := planACLs(want, current)
+			if len(creations) == 0 && len(deletions) == 0 {
+				out.Exit("All ACLs are up to date.")
+			}
+			printPlannedACLs(creations, deletions)
+			if dry {
+				fmt.Println("Dry run, exiting.")
+				return
+			}
+			if !noConfirm {
+				confirmed, err := out.Confirm("Confirm creating %d and deleting %d ACLs?", len(creations), len(deletions))
+				out.MaybeDie(err, "unable to confirm: %v", err)
+				if !confirmed {
+					out.Exit("Apply canceled.")
+				}
+				fmt.Println()
+			}
+
+			createErrs := createBindings(cmd.Context(), adm, creations)
+			deleteErrs := deleteBindings(cmd.Context(), adm, deletions)
+			var failed bool
+			if len(creations) > 0 {
+				out.Section("created")
+				failed = printBindings(creations, createErrs) || failed
+			}
+			if len(deletions) > 0 {
+				if len(creations) > 0 {
+					fmt.Println()
+				}
+				out.Section("deleted")
+				failed = printBindings(deletions, deleteErrs) || failed
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().BoolVarP(&dry, "dry", "d", false, "Dry run: print the ACLs that would be created and deleted")
+	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "Disable confirmation prompt")
+	return cmd
+}
+
+// parseACLFile parses and normalizes the ACLs of a YAML or JSON ACL file.
+func parseACLFile(raw []byte) ([]aclBinding, error) {
+	var f aclFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, err
+	}
+	if f.Version != 0 && f.Version != 1 {
+		return nil, fmt.Errorf("unsupported version %d", f.Version)
+	}
+	// An empty file would delete every ACL in the cluster, which is far
+	// more likely a mistake than intended; 'rpk acl delete' removes ACLs.
+	if len(f.ACLs) == 0 {
+		return nil, errors.New("no acls")
+	}
+	seen := make(map[aclBinding]bool)
+	bindings := make([]aclBinding, 0, len(f.ACLs))
+	for i, b := range f.ACLs {
+		n, err := b.normalize()
+		if err != nil {
+			return nil, fmt.Errorf("acl %d: %v", i, err)
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		bindings = append(bindings, n)
+	}
+	sortBindings(bindings)
+	return bindings, nil
+}
+
+// planACLs returns the ACLs to create and to delete such that the current
+// ACLs match the wanted ACLs.
+func planACLs(want, current []aclBinding) (creations, deletions []aclBinding) {
+	has := make(map[aclBinding]bool, len(current))
+	for _, b := range current {
+		has[b] = true
+	}
+	wanted := make(map[aclBinding]bool, len(want))
+	for _, b := range want {
+		wanted[b] = true
+		if !has[b] {
+			creations = append(creations, b)
+		}
+	}
+	for _, b := range current {
+		if !wanted[b] {
+			deletions = append(deletions, b)
+		}
+	}
+	return creations, deletions
+}
+
+func printPlannedACLs(creations, deletions []aclBinding) {
+	if len(creations) > 0 {
+		out.Section("to create")
+		printBindings(creations, nil)
+		fmt.Println()
+	}
+	if len(deletions) > 0 {
+		out.Section("to delete")
+		printBindings(deletions, nil)
+		fmt.Println()
+	}
+}
+
+// printBindings prints the bindings with their errors, if any, returning
+// whether any binding has an error.
+func printBindings(bindings []aclBinding, errs []error) bool {
+	var failed bool
+	tw := out.NewTable(headersWithError...)
+	defer tw.Flush()
+	for i, b := range bindings {
+		var msg string
+		if errs != nil {
+			msg = kafka.ErrMessage(errs[i])
+			failed = failed || errs[i] != nil
+		}
+		tw.Print(b.Principal, b.Host, b.ResourceType, b.ResourceName, b.ResourcePatternType, b.Operation, b.Permission, msg)
+	}
+	return failed
+}
+
+// createBindings creates each binding, returning the error of each.
+func createBindings(ctx context.Context, adm *kadm.Client, bindings []aclBinding) []error {
+	errs := make([]error, len(bindings))
+	for i, binding := range bindings {
+		a := binding.acls()
+		b, err := a.createCreations()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		results, err := adm.CreateACLs(ctx, b)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				errs[i] = r.Err
+			}
+		}
+	}
+	return errs
+}
+
+// deleteBindings deletes each binding with an exact filter, returning the
+// error of each.
+func deleteBindings(ctx context.Context, adm *kadm.Client, bindings []aclBinding) []error {
+	errs := make([]error, len(bindings))
+	for i, binding := range bindings {
+		a := binding.acls()
+		b, err := a.createDeletionsAndDescribes(false)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		results, err := adm.DeleteACLs(ctx, b)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				errs[i] = r.Err
+			}
+			for _, d := range r.Deleted {
+				if d.Err != nil {
+					errs[i] = d.Err
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,99 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseACLFile(t *testing.T) {
+	exp := []aclBinding{
+		{"User:alice", "*", "CLUSTER", "kafka-cluster", "LITERAL", "DESCRIBE", "ALLOW"},
+		{"User:alice", "*", "TOPIC", "orders", "LITERAL", "READ", "ALLOW"},
+		{"User:bob", "10.0.0.1", "TRANSACTIONAL_ID", "txn-", "PREFIXED", "WRITE", "DENY"},
+	}
+
+	got, err := parseACLFile([]byte(`
+acls:
+  - principal: alice
+    resource_type: topic
+    resource_name: orders
+    operation: read
+    permission: allow
+  - principal: User:bob
+    host: 10.0.0.1
+    resource_type: transactional-id
+    resource_name: txn-
+    resource_pattern_type: prefixed
+    operation: WRITE
+    permission: deny
+  - principal: alice
+    resource_type: cluster
+    operation: describe
+    permission: allow
+  - principal: User:alice
+    host: '*'
+    resource_type: TOPIC
+    resource_name: orders
+    resource_pattern_type: LITERAL
+    operation: READ
+    permission: ALLOW
+`))
+	require.NoError(t, err)
+	require.Equal(t, exp, got)
+
+	got, err = parseACLFile([]byte(`{"version":1,"acls":[
+		{"principal":"User:bob","host":"10.0.0.1","resource_type":"TRANSACTIONAL_ID","resource_name":"txn-","resource_pattern_type":"PREFIXED","operation":"WRITE","permission":"DENY"}
+	]}`))
+	require.NoError(t, err)
+	require.Equal(t, exp[2:], got)
+
+	for _, bad := range []string{
+		``,
+		`{}`,
+		`acls: []`,
+		`{"version":1,"acls":[]}`,
+		`version: 2`,
+		`acls: [{resource_type: topic, resource_name: foo, operation: read, permission: allow}]`,
+		`acls: [{principal: a, resource_type: topic, operation: read, permission: allow}]`,
+		`acls: [{principal: a, resource_type: cluster, resource_name: foo, operation: read, permission: allow}]`,
+		`acls: [{principal: a, resource_type: user, resource_name: foo, operation: read, permission: allow}]`,
+		`acls: [{principal: a, resource_type: topic, resource_name: foo, resource_pattern_type: match, operation: read, permission: allow}]`,
+		`acls: [{principal: a, resource_type: topic, resource_name: foo, operation: any, permission: allow}]`,
+		`acls: [{principal: a, resource_type: topic, resource_name: foo, operation: read, permission: any}]`,
+	} {
+		_, err := parseACLFile([]byte(bad))
+		require.Error(t, err, "file %s", bad)
+	}
+}
+
+func TestPlanACLs(t *testing.T) {
+	var (
+		a = aclBinding{"User:a", "*", "TOPIC", "foo", "LITERAL", "READ", "ALLOW"}
+		b = aclBinding{"User:b", "*", "TOPIC", "foo", "LITERAL", "READ", "ALLOW"}
+		c = aclBinding{"User:c", "*", "GROUP", "g", "PREFIXED", "ALL", "DENY"}
+	)
+	creations, deletions := planACLs([]aclBinding{a, b}, []aclBinding{b, c})
+	require.Equal(t, []aclBinding{a}, creations)
+	require.Equal(t, []aclBinding{c}, deletions)
+
+	creations, deletions = planACLs([]aclBinding{a}, []aclBinding{a})
+	require.Empty(t, creations)
+	require.Empty(t, deletions)
+}
+
+func TestBindingACLs(t *testing.T) {
+	for _, binding := range []aclBinding{
+		{"User:a", "*", "TOPIC", "foo", "LITERAL", "READ", "ALLOW"},
+		{"User:c", "10.0.0.1", "GROUP", "g", "PREFIXED", "ALL", "DENY"},
+		{"User:d", "*", "CLUSTER", "kafka-cluster", "LITERAL", "ALTER", "ALLOW"},
+		{"User:e", "*", "TRANSACTIONAL_ID", "t", "LITERAL", "WRITE", "ALLOW"},
+	} {
+		a := binding.acls()
+		_, err := a.createCreations()
+		require.NoError(t, err, "binding %v", binding)
+		a = binding.acls()
+		_, err = a.createDeletionsAndDescribes(false)
+		require.NoError(t, err, "binding %v", binding)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// aclFile is the file written by export and read by apply.
+type aclFile struct {
+	Version int          `json:"version" yaml:"version"`
+	ACLs    []aclBinding `json:"acls" yaml:"acls"`
+}
+
+// aclBinding is a single ACL in an ACL file. Once normalized, every field
+// holds the canonical name of its value, such that bindings can be compared.
+type aclBinding struct {
+	Principal           string `json:"principal" yaml:"principal"`
+	Host                string `json:"host" yaml:"host"`
+	ResourceType        string `json:"resource_type" yaml:"resource_type"`
+	ResourceName        string `json:"resource_name" yaml:"resource_name"`
+	ResourcePatternType string `json:"resource_pattern_type" yaml:"resource_pattern_type"`
+	Operation           string `json:"operation" yaml:"operation"`
+	Permission          string `json:"permission" yaml:"permission"`
+}
+
+func newExportCommand(fs afero.Fs) *cobra.Command {
+	var (
+		output string
+		format out.Format
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all ACLs to a YAML or JSON file",
+		Long: `Export all ACLs to a YAML or JSON file.
+
+This command writes every ACL in the cluster to a file that can be kept in
+version control and applied with 'rpk acl apply'. ACLs are written as YAML by
+default, or as JSON with --format json.
+
+The file looks like:
+
+    version: 1
+    acls:
+      - principal: User:alice
+        host: '*'
+        resource_type: TOPIC
+        resource_name: orders
+        resource_pattern_type: LITERAL
+        operation: READ
+        permission: ALLOW
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			bindings, err := describeAllACLs(cmd.Context(), adm)
+			out.MaybeDieErr(err)
+
+			if format.IsText() {
+				format = out.FormatYAML
+			}
+			b, err := format.Marshal(aclFile{Version: 1, ACLs: bindings})
+			out.MaybeDie(err, "unable to encode ACLs: %v", err)
+			if output == "" {
+				os.Stdout.Write(b)
+				return
+			}
+			err = afero.WriteFile(fs, output, b, 0o644)
+			out.MaybeDie(err, "unable to write %q: %v", output, err)
+			fmt.Printf("Exported %d ACL(s) to %s.\n", len(bindings), output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the ACLs to (default stdout)")
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+// describeAllACLs returns every ACL in the cluster, sorted.
+func describeAllACLs(ctx context.Context, adm *kadm.Client) ([]aclBinding, error) {
+	// An empty filter matches everything.
+	return describeBindings(ctx, adm, acls{resourcePatternType: "any"})
+}
+
+// describeBindings returns the ACLs matching the filter, sorted.
+func describeBindings(ctx context.Context, adm *kadm.Client, a acls) ([]aclBinding, error) {
+	b, err := a.createDeletionsAndDescribes(false)
+	if err != nil {
+		return nil, err
+	}
+	results, err := adm.DescribeACLs(ctx, b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list ACLs: %v", err)
+	}
+	bindings := []aclBinding{}
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, fmt.Errorf("unable to list ACLs: %v", r.Err)
+		}
+		for _, d := range r.Described {
+			bindings = append(bindings, aclBinding{
+				Principal:           d.Principal,
+				Host:                d.Host,
+				ResourceType:        d.Type.String(),
+				ResourceName:        d.Name,
+				ResourcePatternType: d.Pattern.String(),
+				Operation:           d.Operation.String(),
+				Permission:          d.Permission.String(),
+			})
+		}
+	}
+	sortBindings(bindings)
+	return bindings, nil
+}
+
+func sortBindings(bindings []aclBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		l, r := bindings[i], bindings[j]
+		for _, cmp := range [][2]string{
+			{l.Principal, r.Principal},
+			{l.ResourceType, r.ResourceType},
+			{l.ResourceName, r.ResourceName},
+			{l.ResourcePatternType, r.ResourcePatternType},
+			{l.Operation, r.Operation},
+			{l.Permission, r.Permission},
+			{l.Host, r.Host},
+		} {
+			if cmp[0] != cmp[1] {
+				return cmp[0] < cmp[1]
+			}
+		}
+		return false
+	})
+}
+
+// normalize validates the binding and returns it with canonical values. The
+// principal defaults to the User: type, the host defaults to '*', and the
+// pattern type defaults to literal, as when creating ACLs.
+func (b aclBinding) normalize() (aclBinding, error) {
+	if b.Principal == "" {
+		return b, fmt.Errorf("missing principal")
+	}
+	if !strings.Contains(b.Principal, ":") {
+		b.Principal = "User:" + b.Principal
+	}
+	if b.Host == "" {
+		b.Host = "*"
+	}
+
+	typ, err := kmsg.ParseACLResourceType(b.ResourceType)
+	if err != nil {
+		return b, fmt.Errorf("invalid resource type %q", b.ResourceType)
+	}
+	switch typ {
+	case kmsg.ACLResourceTypeTopic, kmsg.ACLResourceTypeGroup, kmsg.ACLResourceTypeTransactionalId:
+		if b.ResourceName == "" {
+			return b, fmt.Errorf("missing resource name")
+		}
+	case kmsg.ACLResourceTypeCluster:
+		if b.ResourceName == "" {
+			b.ResourceName = kafkaCluster
+		}
+		if b.ResourceName != kafkaCluster {
+			return b, fmt.Errorf("invalid cluster resource name %q, must be %q", b.ResourceName, kafkaCluster)
+		}
+	default:
+		return b, fmt.Errorf("unsupported resource type %q", b.ResourceType)
+	}
+	b.ResourceType = typ.String()
+
+	if b.ResourcePatternType == "" {
+		b.ResourcePatternType = "literal"
+	}
+	pattern, err := kmsg.ParseACLResourcePatternType(b.ResourcePatternType)
+	if err != nil || pattern != kmsg.ACLResourcePatternTypeLiteral && pattern != kmsg.ACLResourcePatternTypePrefixed {
+		return b, fmt.Errorf("invalid resource pattern type %q, must be literal or prefixed", b.ResourcePatternType)
+	}
+	b.ResourcePatternType = pattern.String()
+
+	op, err := kmsg.ParseACLOperation(b.Operation)
+	if err != nil || op == kmsg.ACLOperationAny || op == kmsg.ACLOperationUnknown {
+		return b, fmt.Errorf("invalid operation %q", b.Operation)
+	}
+	b.Operation = op.String()
+
+	perm, err := kmsg.ParseACLPermissionType(b.Permission)
+	if err != nil || perm != kmsg.ACLPermissionTypeAllow && perm != kmsg.ACLPermissionTypeDeny {
+		return b, fmt.Errorf("invalid permission %q, must be allow or deny", b.Permission)
+	}
+	b.Permission = perm.String()
+	return b, nil
+}
+
+// acls returns the flags that create (or, as a filter, match exactly) the
+// binding, which must be normalized.
+func (b aclBinding) acls() acls {
+	a := acls{
+		resourcePatternType: b.ResourcePatternType,
+		operations:          []string{b.Operation},
+	}
+	switch b.ResourceType {
+	case kmsg.ACLResourceTypeTopic.String():
+		a.topics = []string{b.ResourceName}
+	case kmsg.ACLResourceTypeGroup.String():
+		a.groups = []string{b.ResourceName}
+	case kmsg.ACLResourceTypeTransactionalId.String():
+		a.txnIDs = []string{b.ResourceName}
+	case kmsg.ACLResourceTypeCluster.String():
+		a.cluster = true
+	}
+	if b.Permission == kmsg.ACLPermissionTypeAllow.String() {
+		a.allowPrincipals = []string{b.Principal}
+		a.allowHosts = []string{b.Host}
+	} else {
+		a.denyPrincipals = []string{b.Principal}
+		a.denyHosts = []string{b.Host}
+	}
+	return a
+}
@@ -24,7 +24,10 @@ import (
 
 func newListCommand(fs afero.Fs) *cobra.Command {
 	var a acls
-	var printAllFilters bool
+	var (
+		printAllFilters bool
+		format          out.Format
+	)
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls", "describe"},
@@ -47,6 +50,10 @@ resource names:
   * "match" returns wildcard matches, prefix patterns that match your input, and literal matches
   * "prefix" returns prefix patterns that match your input (prefix "fo" matches "foo")
   * "literal" returns exact name matches
+
+With --format json or yaml, the matching ACLs are printed under "matches". The
+requested filters are printed under "filters" if --print-filters is used or
+if any filter failed.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, _ []string) {
@@ -60,14 +67,75 @@ resource names:
 
 			b, err := a.createDeletionsAndDescribes(true)
 			out.MaybeDieErr(err)
-			describeReqResp(adm, printAllFilters, false, b)
+			if format.IsText() {
+				describeReqResp(adm, printAllFilters, false, b)
+				return
+			}
+			results, err := adm.DescribeACLs(context.Background(), b)
+			out.MaybeDie(err, "unable to list ACLs: %v", err)
+			types.Sort(results)
+			format.Print(newDescribedACLs(results, printAllFilters), nil)
 		},
 	}
 	a.addListFlags(cmd)
 	cmd.Flags().BoolVarP(&printAllFilters, "print-filters", "f", false, "Print the filters that were requested (failed filters are always printed)")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
 
+// describedACLs is the structured output of the list command.
+type describedACLs struct {
+	Filters []aclOutput `json:"filters,omitempty"`
+	Matches []aclOutput `json:"matches"`
+}
+
+// aclOutput is an ACL or ACL filter in structured output.
+type aclOutput struct {
+	Principal           string `json:"principal"`
+	Host                string `json:"host"`
+	ResourceType        string `json:"resource_type"`
+	ResourceName        string `json:"resource_name"`
+	ResourcePatternType string `json:"resource_pattern_type"`
+	Operation           string `json:"operation"`
+	Permission          string `json:"permission"`
+	Error               string `json:"error,omitempty"`
+}
+
+func newDescribedACLs(results kadm.DescribeACLsResults, printAllFilters bool) describedACLs {
+	d := describedACLs{Matches: []aclOutput{}}
+	var failed bool
+	for _, f := range results {
+		failed = failed || f.Err != nil
+	}
+	for _, f := range results {
+		if printAllFilters || failed {
+			d.Filters = append(d.Filters, aclOutput{
+				unptr(f.Principal),
+				unptr(f.Host),
+				f.Type.String(),
+				unptr(f.Name),
+				f.Pattern.String(),
+				f.Operation.String(),
+				f.Permission.String(),
+				kafka.ErrMessage(f.Err),
+			})
+		}
+		for _, a := range f.Described {
+			d.Matches = append(d.Matches, aclOutput{
+				a.Principal,
+				a.Host,
+				a.Type.String(),
+				a.Name,
+				a.Pattern.String(),
+				a.Operation.String(),
+				a.Permission.String(),
+				"",
+			})
+		}
+	}
+	return d
+}
+
 func (a *acls) addListFlags(cmd *cobra.Command) {
 	a.addDeprecatedFlags(cmd)
 
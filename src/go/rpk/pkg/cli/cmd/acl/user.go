@@ -10,14 +10,20 @@
 package acl
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func newUserCommand(fs afero.Fs) *cobra.Command {
@@ -44,7 +50,9 @@ redpanda section of your redpanda.yaml.
 
 	cmd.AddCommand(newCreateUserCommand(fs))
 	cmd.AddCommand(newDeleteUserCommand(fs))
+	cmd.AddCommand(newDescribeUserCommand(fs))
 	cmd.AddCommand(newListUsersCommand(fs))
+	cmd.AddCommand(newUpdateUserCommand(fs))
 	return cmd
 }
 
@@ -104,14 +112,8 @@ acl help text for more info.
 				pass = passOld
 			}
 
-			switch strings.ToLower(mechanism) {
-			case "scram-sha-256":
-				mechanism = admin.ScramSha256
-			case "scram-sha-512":
-				mechanism = admin.ScramSha512
-			default:
-				out.Die("unsupported mechanism %q", mechanism)
-			}
+			mechanism, err = parseMechanism(mechanism)
+			out.MaybeDieErr(err)
 
 			err = cl.CreateUser(cmd.Context(), user, pass, mechanism)
 			out.MaybeDie(err, "unable to create user %q: %v", user, err)
@@ -136,6 +138,19 @@ acl help text for more info.
 	return cmd
 }
 
+// parseMechanism returns the admin API name of a case insensitive SASL
+// mechanism.
+func parseMechanism(mechanism string) (string, error) {
+	switch strings.ToLower(mechanism) {
+	case "scram-sha-256":
+		return admin.ScramSha256, nil
+	case "scram-sha-512":
+		return admin.ScramSha512, nil
+	default:
+		return "", fmt.Errorf("unsupported mechanism %q", mechanism)
+	}
+}
+
 func newDeleteUserCommand(fs afero.Fs) *cobra.Command {
 	var oldUser string
 	cmd := &cobra.Command{
@@ -180,7 +195,8 @@ delete any ACLs that may exist for this user.
 }
 
 func newListUsersCommand(fs afero.Fs) *cobra.Command {
-	return &cobra.Command{
+	var format out.Format
+	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List SASL users",
@@ -194,12 +210,198 @@ func newListUsersCommand(fs afero.Fs) *cobra.Command {
 
 			users, err := cl.ListUsers(cmd.Context())
 			out.MaybeDie(err, "unable to list users: %v", err)
+			if users == nil {
+				users = []string{}
+			}
+
+			format.Print(users, func() {
+				tw := out.NewTable("Username")
+				defer tw.Flush()
+				for _, u := range users {
+					tw.Print(u)
+				}
+			})
+		},
+	}
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
 
-			tw := out.NewTable("Username")
-			defer tw.Flush()
+func newUpdateUserCommand(fs afero.Fs) *cobra.Command {
+	var pass, mechanism string
+	cmd := &cobra.Command{
+		Use:   "update [USER] --new-password [PASS]",
+		Short: "Update a SASL user's password",
+		Long: `Update a SASL user's password.
+
+This command rotates the password of an existing SASL user. The user keeps its
+ACLs, but clients must reconnect with the new password.
+
+By default, the user keeps its current mechanism, which is looked up with the
+Kafka API. If the mechanism cannot be looked up, or to change it, use
+--mechanism (scram-sha-256 or scram-sha-512).
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			user := args[0]
+			if pass == "" {
+				out.Die("missing required --new-password")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			if mechanism == "" {
+				kcl, err := kafka.NewFranzClient(fs, p, cfg)
+				out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+				mechanisms, err := describeUserMechanisms(cmd.Context(), kcl, user)
+				kcl.Close()
+				if err != nil || len(mechanisms) != 1 {
+					out.Die("unable to determine the current mechanism of user %q, use --mechanism to specify it", user)
+				}
+				mechanism = mechanisms[0].Mechanism
+			}
+			mechanism, err = parseMechanism(mechanism)
+			out.MaybeDieErr(err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			err = cl.UpdateUser(cmd.Context(), user, pass, mechanism)
+			out.MaybeDie(err, "unable to update user %q: %v", user, err)
+			fmt.Printf("Updated user %q.\n", user)
+		},
+	}
+	cmd.Flags().StringVar(&pass, "new-password", "", "New password for the user")
+	cmd.Flags().StringVar(&mechanism, "mechanism", "", "SASL mechanism to use for the user (scram-sha-256, scram-sha-512, case insensitive; default the current mechanism)")
+	return cmd
+}
+
+// userMechanism is a SCRAM credential of a user.
+type userMechanism struct {
+	Mechanism  string `json:"mechanism"`
+	Iterations int32  `json:"iterations"`
+}
+
+// describedUser is the output of user describe. Mechanisms is empty if the
+// mechanism could not be described.
+type describedUser struct {
+	User       string          `json:"user"`
+	Mechanisms []userMechanism `json:"mechanisms"`
+	ACLs       []aclBinding    `json:"acls"`
+}
+
+func newDescribeUserCommand(fs afero.Fs) *cobra.Command {
+	var format out.Format
+	cmd := &cobra.Command{
+		Use:   "describe [USER]",
+		Short: "Describe a SASL user's mechanism and ACLs",
+		Long: `Describe a SASL user's mechanism and ACLs.
+
+This command prints the SASL mechanism of a user and every ACL whose principal
+is the user (User:<name>). The mechanism is described with the Kafka API; if
+the cluster does not support describing SCRAM credentials, the mechanism is
+printed as unknown.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			user := args[0]
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+			users, err := cl.ListUsers(cmd.Context())
+			out.MaybeDie(err, "unable to list users: %v", err)
+			var exists bool
 			for _, u := range users {
-				tw.Print(u)
+				exists = exists || u == user
 			}
+			if !exists {
+				out.Die("user %q does not exist", user)
+			}
+
+			kcl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer kcl.Close()
+			adm := kadm.NewClient(kcl)
+
+			d := describedUser{User: user, Mechanisms: []userMechanism{}}
+			if mechanisms, err := describeUserMechanisms(cmd.Context(), kcl, user); err == nil {
+				d.Mechanisms = mechanisms
+			}
+			d.ACLs, err = describeBindings(cmd.Context(), adm, acls{
+				resourcePatternType: "any",
+				allowPrincipals:     []string{"User:" + user},
+				denyPrincipals:      []string{"User:" + user},
+			})
+			out.MaybeDieErr(err)
+
+			format.Print(d, func() {
+				mechanisms := "unknown"
+				if len(d.Mechanisms) > 0 {
+					var ms []string
+					for _, m := range d.Mechanisms {
+						ms = append(ms, fmt.Sprintf("%s (%d iterations)", m.Mechanism, m.Iterations))
+					}
+					mechanisms = strings.Join(ms, ", ")
+				}
+				tw := out.NewTabWriter()
+				tw.Print("USER", d.User)
+				tw.Print("MECHANISM", mechanisms)
+				tw.Flush()
+				fmt.Println()
+
+				out.Section("acls")
+				printBindings(d.ACLs, nil)
+			})
 		},
 	}
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+// describeUserMechanisms returns the SCRAM credentials of the user.
+func describeUserMechanisms(ctx context.Context, cl *kgo.Client, user string) ([]userMechanism, error) {
+	req := kmsg.NewPtrDescribeUserSCRAMCredentialsRequest()
+	reqUser := kmsg.NewDescribeUserSCRAMCredentialsRequestUser()
+	reqUser.Name = user
+	req.Users = append(req.Users, reqUser)
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	return userMechanisms(resp, user)
+}
+
+// userMechanisms returns the credentials of the user in the response.
+func userMechanisms(resp *kmsg.DescribeUserSCRAMCredentialsResponse, user string) ([]userMechanism, error) {
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+	for _, r := range resp.Results {
+		if r.User != user {
+			continue
+		}
+		if err := kerr.ErrorForCode(r.ErrorCode); err != nil {
+			return nil, err
+		}
+		mechanisms := []userMechanism{}
+		for _, c := range r.CredentialInfos {
+			m := userMechanism{Iterations: c.Iterations}
+			switch c.Mechanism {
+			case 1:
+				m.Mechanism = admin.ScramSha256
+			case 2:
+				m.Mechanism = admin.ScramSha512
+			default:
+				m.Mechanism = fmt.Sprintf("unknown (%d)", c.Mechanism)
+			}
+			mechanisms = append(mechanisms, m)
+		}
+		return mechanisms, nil
+	}
+	return nil, fmt.Errorf("user %q missing from response", user)
 }
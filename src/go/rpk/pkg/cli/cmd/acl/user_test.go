@@ -0,0 +1,44 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestParseMechanism(t *testing.T) {
+	for in, exp := range map[string]string{
+		"scram-sha-256": "SCRAM-SHA-256",
+		"SCRAM-SHA-512": "SCRAM-SHA-512",
+	} {
+		got, err := parseMechanism(in)
+		require.NoError(t, err)
+		require.Equal(t, exp, got)
+	}
+	_, err := parseMechanism("plain")
+	require.Error(t, err)
+}
+
+func TestUserMechanisms(t *testing.T) {
+	resp := &kmsg.DescribeUserSCRAMCredentialsResponse{
+		Results: []kmsg.DescribeUserSCRAMCredentialsResponseResult{
+			{User: "bob", ErrorCode: 91}, // RESOURCE_NOT_FOUND
+			{User: "alice", CredentialInfos: []kmsg.DescribeUserSCRAMCredentialsResponseResultCredentialInfo{
+				{Mechanism: 2, Iterations: 4096},
+			}},
+		},
+	}
+	mechanisms, err := userMechanisms(resp, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []userMechanism{{Mechanism: "SCRAM-SHA-512", Iterations: 4096}}, mechanisms)
+
+	_, err = userMechanisms(resp, "bob")
+	require.Error(t, err)
+	_, err = userMechanisms(resp, "carol")
+	require.Error(t, err)
+
+	resp.ErrorCode = 35 // UNSUPPORTED_VERSION
+	_, err = userMechanisms(resp, "alice")
+	require.Error(t, err)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/license"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/maintenance"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/partitions"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/cluster/quotas"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/group"
 	"github.com/spf13/afero"
@@ -63,6 +64,7 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 		license.NewLicenseCommand(fs),
 		maintenance.NewMaintenanceCommand(fs),
 		partitions.NewPartitionsCommand(fs),
+		quotas.NewQuotasCommand(fs),
 		offsets,
 	)
 
@@ -38,7 +38,8 @@ the cluster.  These are separate to node properties, which are set with
 
 Use the 'edit' subcommand to interactively modify the cluster configuration, or
 'export' and 'import' to write configuration to a file that can be edited and
-read back later.
+read back later. The 'diff' subcommand shows how a file differs from the
+current configuration without changing anything.
 
 These commands take an optional '--all' flag to include all properties including
 low level tunables such as internal buffer sizes, that do not usually need
@@ -48,7 +49,10 @@ some expertize to set safely, so if in doubt, avoid using '--all'.
 Modified properties are propagated immediately to all nodes.  The 'status'
 subcommand can be used to verify that all nodes are up to date, and identify
 any settings which were rejected by a node, for example if a node is running a
-different redpanda version that does not recognize certain properties.`,
+different redpanda version that does not recognize certain properties.
+
+Commands that modify properties record the prior values in a local history,
+which can be listed with 'history' and restored with 'rollback'.`,
 	}
 
 	command.PersistentFlags().StringVar(
@@ -75,6 +79,9 @@ different redpanda version that does not recognize certain properties.`,
 		newImportCommand(fs, &all),
 		newExportCommand(fs, &all),
 		newEditCommand(fs, &all),
+		newDiffCommand(fs, &all),
+		newHistoryCommand(fs),
+		newRollbackCommand(fs),
 		newStatusCommand(fs),
 		newForceResetCommand(fs),
 		newLintCommand(fs),
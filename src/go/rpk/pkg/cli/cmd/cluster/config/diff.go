@@ -0,0 +1,254 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// diffExitCode is the exit status of diff and import with --exit-code if the
+// file differs from the cluster configuration. Errors exit with status 1.
+const diffExitCode = 2
+
+// The kinds of change of a property between the cluster and a file.
+const (
+	changeAdded   = "added"
+	changeChanged = "changed"
+	changeRemoved = "removed"
+)
+
+// propertyChange is a property that differs between the cluster's current
+// configuration and a configuration file.
+type propertyChange struct {
+	Property     string      `json:"property"`
+	Change       string      `json:"change"`
+	Prior        interface{} `json:"prior,omitempty"`
+	New          interface{} `json:"new,omitempty"`
+	NeedsRestart bool        `json:"needs_restart"`
+}
+
+// configDiff is the difference between the cluster's configuration and a
+// configuration file, and the PATCH that applies the file.
+type configDiff struct {
+	changes []propertyChange
+	upsert  map[string]interface{}
+	remove  []string
+}
+
+func (d configDiff) empty() bool { return len(d.upsert) == 0 && len(d.remove) == 0 }
+
+func (d configDiff) needsRestart() bool {
+	for _, c := range d.changes {
+		if c.NeedsRestart {
+			return true
+		}
+	}
+	return false
+}
+
+func newDiffCommand(fs afero.Fs, all *bool) *cobra.Command {
+	var (
+		exitCode bool
+		format   out.Format
+	)
+	cmd := &cobra.Command{
+		Use:   "diff [FILE]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Show how a configuration file differs from the cluster configuration",
+		Long: `Show how a configuration file differs from the cluster configuration.
+
+This command downloads the current cluster configuration and prints the
+properties that 'import' of the YAML file would add, change, or remove,
+without changing anything. A property that is removed from the file is reset
+to its default value on import. The RESTART column shows whether a changed
+property requires a restart of redpanda to take effect. Added and changed
+values are validated against the cluster's configuration schema, and this
+command fails if any are invalid.
+
+With --exit-code, this command exits with status 2 if there are differences,
+which is useful to detect drift in CI. Errors exit with status 1.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			client, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			schema, err := client.ClusterConfigSchema(cmd.Context())
+			out.MaybeDie(err, "unable to query config schema: %v", err)
+
+			currentConfig, err := client.Config(cmd.Context())
+			out.MaybeDie(err, "unable to query config values: %v", err)
+
+			fileConfig, err := readConfigFile(args[0])
+			out.MaybeDieErr(err)
+
+			d := diffConfig(currentConfig, fileConfig, schema, *all)
+			format.Print(d.changes, func() {
+				if d.empty() {
+					fmt.Println("No differences.")
+					return
+				}
+				printConfigDiff(d)
+			})
+			if errs := validateConfig(d.upsert, schema); errs != nil {
+				fmt.Fprint(os.Stderr, "\n"+formatValidationErrors(errs))
+				out.Die("The file would be rejected on import")
+			}
+			if exitCode && !d.empty() {
+				os.Exit(diffExitCode)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 2 if the file differs from the cluster configuration")
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+// readConfigFile reads a YAML configuration file, as written by export.
+func readConfigFile(filename string) (admin.Config, error) {
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", filename, err)
+	}
+	var c admin.Config
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("error parsing edited config: %v", err)
+	}
+	return c, nil
+}
+
+// diffConfig calculates the properties that differ between the cluster's
+// current configuration and a configuration file. Properties that are in the
+// cluster configuration but not in the file are removed (reset to their
+// defaults), unless they are tunables and all is false.
+func diffConfig(
+	oldConfig, fileConfig admin.Config, schema admin.ConfigSchema, all bool,
+) configDiff {
+	d := configDiff{
+		changes: []propertyChange{},
+		upsert:  make(map[string]interface{}),
+		remove:  make([]string, 0),
+	}
+	for k, v := range fileConfig {
+		// We exclude cluster_id from upsert here and remove below to
+		// avoid any accidental duplication of the ID from one cluster
+		// to another
+		if k == "cluster_id" {
+			continue
+		}
+
+		oldVal, haveOldVal := oldConfig[k]
+		meta, inSchema := schema[k]
+		if inSchema {
+			// For numeric types need special handling because
+			// yaml encoding will see '1' as an integer, even
+			// if it is given as the value for a floating point
+			// ('number') config property, and vice versa.
+			if meta.Type == "integer" {
+				if vFloat, ok := v.(float64); ok {
+					v = int(vFloat)
+				}
+
+				if oldVal != nil {
+					oldVal = int(oldVal.(float64))
+				}
+			} else if meta.Type == "number" {
+				if vInt, ok := v.(int); ok {
+					v = float64(vInt)
+				}
+			} else if meta.Type == "array" && meta.Items.Type == "string" {
+				switch vArray := v.(type) {
+				case []interface{}:
+					// Normal case: user input is a yaml array
+					v = loadStringArray(vArray)
+				default:
+					// Pass, let the server attempt validation
+				}
+				if oldVal != nil {
+					oldVal = loadStringArray(oldVal.([]interface{}))
+				}
+			}
+
+			// For types that aren't numeric or array, pass them through as-is
+		}
+
+		if haveOldVal {
+			// Since the admin endpoint will redact secret fields, ignore any
+			// such sentinel strings we've been given, to avoid accidentally
+			// setting configs to this value.
+			// TODO: why doesn't this work with DeepEqual?
+			if fmt.Sprintf("%v", oldVal) == "[secret]" && fmt.Sprintf("%v", v) == "[secret]" {
+				continue
+			}
+			// If value changed, add it to list of updates.
+			// DeepEqual because values can be slices.
+			if !reflect.DeepEqual(oldVal, v) {
+				d.changes = append(d.changes, propertyChange{k, changeChanged, oldVal, v, meta.NeedsRestart})
+				d.upsert[k] = v
+			}
+		} else {
+			// Present in input but not original config, insert
+			d.changes = append(d.changes, propertyChange{k, changeAdded, nil, v, meta.NeedsRestart})
+			d.upsert[k] = v
+		}
+	}
+
+	for k, oldVal := range oldConfig {
+		if _, found := fileConfig[k]; found || k == "cluster_id" {
+			continue
+		}
+
+		meta, inSchema := schema[k]
+		if !inSchema {
+			continue
+		}
+
+		if !all && meta.Visibility == "tunable" {
+			continue
+		}
+		d.changes = append(d.changes, propertyChange{k, changeRemoved, oldVal, nil, meta.NeedsRestart})
+		d.remove = append(d.remove, k)
+	}
+
+	sort.Slice(d.changes, func(i, j int) bool { return d.changes[i].Property < d.changes[j].Property })
+	sort.Strings(d.remove)
+	return d
+}
+
+func printConfigDiff(d configDiff) {
+	tw := out.NewTable("PROPERTY", "CHANGE", "PRIOR", "NEW", "RESTART")
+	for _, c := range d.changes {
+		var prior, next string
+		if c.Change != changeAdded {
+			prior = fmt.Sprintf("%v", c.Prior)
+		}
+		if c.Change != changeRemoved {
+			next = fmt.Sprintf("%v", c.New)
+		}
+		tw.Print(c.Property, c.Change, prior, next, c.NeedsRestart)
+	}
+	tw.Flush()
+
+	if d.needsRestart() {
+		fmt.Println("\nSome properties require a restart of redpanda to take effect.")
+	}
+}
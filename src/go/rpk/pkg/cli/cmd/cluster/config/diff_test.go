@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestDiffConfig(t *testing.T) {
+	schema := admin.ConfigSchema{
+		"cluster_id":              {Type: "string"},
+		"log_retention_ms":        {Type: "integer"},
+		"log_compression_ratio":   {Type: "number"},
+		"superusers":              {Type: "array", Items: admin.ConfigPropertyItems{Type: "string"}},
+		"enable_sasl":             {Type: "boolean"},
+		"kafka_api_tls":           {Type: "string", NeedsRestart: true},
+		"cloud_storage_secret":    {Type: "string"},
+		"raft_heartbeat_interval": {Type: "integer", Visibility: "tunable", NeedsRestart: true},
+		"auto_create_topics":      {Type: "boolean", NeedsRestart: true},
+	}
+	// The admin API returns json, where every number is a float64.
+	current := admin.Config{
+		"cluster_id":              "abc",
+		"log_retention_ms":        float64(1000),
+		"log_compression_ratio":   float64(2),
+		"superusers":              []interface{}{"admin"},
+		"enable_sasl":             false,
+		"cloud_storage_secret":    "[secret]",
+		"raft_heartbeat_interval": float64(150),
+		"auto_create_topics":      true,
+		"unknown_property":        "x",
+	}
+	var file admin.Config
+	err := yaml.Unmarshal([]byte(`
+cluster_id: other
+log_retention_ms: 1000
+log_compression_ratio: 2
+superusers: [admin, bob]
+enable_sasl: true
+kafka_api_tls: "on"
+cloud_storage_secret: "[secret]"
+`), &file)
+	require.NoError(t, err)
+
+	d := diffConfig(current, file, schema, false)
+	require.Equal(t, []propertyChange{
+		{Property: "auto_create_topics", Change: changeRemoved, Prior: true, NeedsRestart: true},
+		{Property: "enable_sasl", Change: changeChanged, Prior: false, New: true},
+		{Property: "kafka_api_tls", Change: changeAdded, New: "on", NeedsRestart: true},
+		{Property: "superusers", Change: changeChanged, Prior: []string{"admin"}, New: []string{"admin", "bob"}},
+	}, d.changes)
+	require.Equal(t, map[string]interface{}{
+		"enable_sasl":   true,
+		"kafka_api_tls": "on",
+		"superusers":    []string{"admin", "bob"},
+	}, d.upsert)
+	require.Equal(t, []string{"auto_create_topics"}, d.remove)
+	require.True(t, d.needsRestart())
+	require.False(t, d.empty())
+
+	// With all, tunables that are missing from the file are removed.
+	d = diffConfig(current, file, schema, true)
+	require.Equal(t, []string{"auto_create_topics", "raft_heartbeat_interval"}, d.remove)
+
+	d = diffConfig(current, admin.Config{
+		"log_retention_ms":        1000,
+		"log_compression_ratio":   2,
+		"superusers":              []interface{}{"admin"},
+		"enable_sasl":             false,
+		"cloud_storage_secret":    "[secret]",
+		"raft_heartbeat_interval": 150,
+		"auto_create_topics":      true,
+	}, schema, true)
+	require.True(t, d.empty())
+	require.Empty(t, d.changes)
+	require.False(t, d.needsRestart())
+}
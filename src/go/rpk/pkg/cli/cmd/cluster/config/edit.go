@@ -23,6 +23,7 @@ import (
 )
 
 func newEditCommand(fs afero.Fs, all *bool) *cobra.Command {
+	var noHistory bool
 	cmd := &cobra.Command{
 		Use:   "edit",
 		Short: "Edit cluster configuration properties",
@@ -40,6 +41,9 @@ values.
 
 By default, low level tunables are excluded: use the '--all' flag
 to edit all properties including these tunables.
+
+The prior values of changed properties are recorded in the config history,
+see 'rpk cluster config history'.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, _ []string) {
@@ -58,19 +62,22 @@ to edit all properties including these tunables.
 			currentConfig, err := client.Config(cmd.Context())
 			out.MaybeDie(err, "unable to get current config: %v", err)
 
-			err = executeEdit(cmd.Context(), client, schema, currentConfig, all)
+			err = executeEdit(cmd.Context(), fs, client, schema, currentConfig, all, noHistory)
 			out.MaybeDie(err, "unable to edit: %v", err)
 		},
 	}
+	addNoHistoryFlag(cmd, &noHistory)
 	return cmd
 }
 
 func executeEdit(
 	ctx context.Context,
+	fs afero.Fs,
 	client *admin.AdminAPI,
 	schema admin.ConfigSchema,
 	currentConfig admin.Config,
 	all *bool,
+	noHistory bool,
 ) error {
 	// Generate a yaml template for editing
 	file, err := os.CreateTemp("/tmp", "config_*.yaml")
@@ -116,7 +123,11 @@ func executeEdit(
 	}
 
 	// Read back template & parse
-	err = importConfig(ctx, client, filename, currentConfig, schema, *all)
+	_, err = importConfig(ctx, fs, client, filename, currentConfig, schema, applyOpts{
+		command:   "edit",
+		all:       *all,
+		noHistory: noHistory,
+	})
 	if err != nil {
 		return fmt.Errorf("error updating config: %v", err)
 	}
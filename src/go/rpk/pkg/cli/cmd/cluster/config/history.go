@@ -0,0 +1,369 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// historyEntry is a change to the cluster configuration, recorded with the
+// values that the changed properties had before the change.
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	// Version is the configuration version that the change produced. It
+	// is nil if the change was not made through the admin API
+	// (force-reset), or if rpk did not learn whether the change was
+	// applied.
+	Version   *int         `json:"version,omitempty"`
+	CacheFile string       `json:"cache_file,omitempty"`
+	Prior     []priorValue `json:"prior"`
+}
+
+// priorValue is the value of a property before a change. If the property
+// was not set, Default is true and rolling back resets the property to its
+// default.
+type priorValue struct {
+	Property string      `json:"property"`
+	Value    interface{} `json:"value"`
+	Default  bool        `json:"default,omitempty"`
+}
+
+// configSnapshot is a history entry that was written before a change, and
+// that is updated once the change is applied.
+type configSnapshot struct {
+	fs    afero.Fs
+	path  string
+	entry historyEntry
+}
+
+// historyFileTimeFormat is used to name history files such that they sort
+// by time.
+const historyFileTimeFormat = "20060102T150405.000000000Z"
+
+// configHistoryDir returns the directory that history of the cluster's
+// configuration is saved in.
+func configHistoryDir(clusterID string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the user config directory: %v", err)
+	}
+	if clusterID == "" {
+		clusterID = "unknown"
+	}
+	return filepath.Join(dir, "rpk", "cluster-config-history", url.PathEscape(clusterID)), nil
+}
+
+// clusterID returns the cluster_id property of a configuration, which keys
+// the configuration history of different clusters.
+func clusterID(c map[string]interface{}) string {
+	if id, ok := c["cluster_id"].(string); ok {
+		return id
+	}
+	return ""
+}
+
+// snapshotConfig writes a history entry with the current values of the
+// given properties, before a command changes them.
+func snapshotConfig(
+	fs afero.Fs, command string, current map[string]interface{}, properties []string,
+) (*configSnapshot, error) {
+	s, err := newConfigSnapshot(fs, command, current, properties)
+	if err != nil {
+		return nil, err
+	}
+	return s, s.write()
+}
+
+// newConfigSnapshot returns an unwritten history entry with the current
+// values of the given properties.
+func newConfigSnapshot(
+	fs afero.Fs, command string, current map[string]interface{}, properties []string,
+) (*configSnapshot, error) {
+	dir, err := configHistoryDir(clusterID(current))
+	if err != nil {
+		return nil, err
+	}
+	s := &configSnapshot{
+		fs: fs,
+		entry: historyEntry{
+			Time:    time.Now().UTC(),
+			Command: command,
+			Prior:   priorValues(current, properties),
+		},
+	}
+	s.path = filepath.Join(dir, s.entry.Time.Format(historyFileTimeFormat)+".json")
+	return s, nil
+}
+
+func priorValues(current map[string]interface{}, properties []string) []priorValue {
+	sorted := append([]string(nil), properties...)
+	sort.Strings(sorted)
+	prior := make([]priorValue, 0, len(sorted))
+	for _, p := range sorted {
+		v, ok := current[p]
+		prior = append(prior, priorValue{Property: p, Value: v, Default: !ok})
+	}
+	return prior
+}
+
+func (s *configSnapshot) write() error {
+	dir := filepath.Dir(s.path)
+	if err := s.fs.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create history directory %q: %v", dir, err)
+	}
+	b, err := json.MarshalIndent(s.entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode history entry: %v", err)
+	}
+	if err := afero.WriteFile(s.fs, s.path, b, 0o644); err != nil {
+		return fmt.Errorf("unable to write history entry %q: %v", s.path, err)
+	}
+	return nil
+}
+
+// applied records the configuration version that the change produced.
+func (s *configSnapshot) applied(version int) {
+	s.entry.Version = &version
+	if err := s.write(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to record config version %d in history: %v\n", version, err)
+	}
+}
+
+// failed removes the snapshot if the change was rejected. If it is unknown
+// whether the change was applied, the snapshot is kept without a version.
+func (s *configSnapshot) failed(err error) {
+	if he := (*admin.HTTPResponseError)(nil); !errors.As(err, &he) {
+		return
+	}
+	if err := s.fs.Remove(s.path); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to remove history entry %q: %v\n", s.path, err)
+	}
+}
+
+// readConfigHistory returns the history entries in dir, oldest first.
+func readConfigHistory(fs afero.Fs, dir string) ([]historyEntry, error) {
+	infos, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read history directory %q: %v", dir, err)
+	}
+	var names []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var entries []historyEntry
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		raw, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read history entry %q: %v", path, err)
+		}
+		var e historyEntry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("unable to parse history entry %q: %v", path, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// rollbackConfig returns the configuration that results from reverting every
+// change in the history that produced a version newer than version. Changes
+// that have no version are not reverted.
+func rollbackConfig(
+	current admin.Config, history []historyEntry, version int,
+) (admin.Config, error) {
+	var reverted bool
+	target := make(admin.Config, len(current))
+	for k, v := range current {
+		target[k] = v
+	}
+	// Reverting newest first leaves each property with its value
+	// before the oldest reverted change.
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if e.Version == nil || *e.Version <= version {
+			continue
+		}
+		reverted = true
+		for _, p := range e.Prior {
+			if p.Default {
+				delete(target, p.Property)
+			} else {
+				target[p.Property] = p.Value
+			}
+		}
+	}
+	if !reverted {
+		return nil, fmt.Errorf("no recorded changes after config version %d", version)
+	}
+	return target, nil
+}
+
+func newHistoryCommand(fs afero.Fs) *cobra.Command {
+	var format out.Format
+	cmd := &cobra.Command{
+		Use:   "history",
+		Args:  cobra.ExactArgs(0),
+		Short: "List recorded changes to the cluster configuration",
+		Long: `List recorded changes to the cluster configuration.
+
+Before the 'set', 'import', 'edit', 'rollback', and 'force-reset' commands
+change the cluster configuration, rpk saves the prior values of the properties
+that are changed into your user config directory. This command lists those
+changes, oldest first, with the config version that each change produced and
+the values it replaced. Use 'rollback' to restore the configuration as of an
+earlier version.
+
+Changes made with 'force-reset' only modify the configuration cache of a
+single node and have no version. Changes without a version cannot be rolled
+back, but their prior values can be restored with 'set'.
+
+History is only recorded for changes made by this rpk; saving history can be
+disabled with --no-history on each of the commands above.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			client, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			currentConfig, err := client.Config(cmd.Context())
+			out.MaybeDie(err, "unable to query config values: %v", err)
+
+			dir, err := configHistoryDir(clusterID(currentConfig))
+			out.MaybeDieErr(err)
+			history, err := readConfigHistory(fs, dir)
+			out.MaybeDieErr(err)
+
+			if history == nil {
+				history = []historyEntry{}
+			}
+			format.Print(history, func() {
+				if len(history) == 0 {
+					fmt.Println("No changes have been recorded.")
+					return
+				}
+				printConfigHistory(history)
+			})
+		},
+	}
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+func printConfigHistory(history []historyEntry) {
+	tw := out.NewTable("TIME", "VERSION", "COMMAND", "PROPERTY", "PRIOR")
+	defer tw.Flush()
+	for _, e := range history {
+		version := "-"
+		if e.Version != nil {
+			version = strconv.Itoa(*e.Version)
+		}
+		for _, p := range e.Prior {
+			prior := fmt.Sprintf("%v", p.Value)
+			if p.Default {
+				prior = "(default)"
+			}
+			tw.Print(e.Time.Local().Format(time.RFC3339), version, e.Command, p.Property, prior)
+		}
+	}
+}
+
+func newRollbackCommand(fs afero.Fs) *cobra.Command {
+	var (
+		dryRun    bool
+		noHistory bool
+	)
+	cmd := &cobra.Command{
+		Use:   "rollback [VERSION]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Restore the cluster configuration to an earlier version",
+		Long: `Restore the cluster configuration to an earlier version.
+
+This command reverts every change in 'rpk cluster config history' that
+produced a config version newer than VERSION, restoring each changed property
+to its value before the oldest reverted change. Properties that were not
+changed by a recorded change keep their current value, so changes made outside
+of this rpk are only overwritten if a recorded change also modified them.
+Secret properties are redacted by the admin API, so their prior values are not
+known and they are not restored.
+
+The rollback itself is recorded in the history and can be rolled back. Use
+--dry-run to print the changes without applying them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			version, err := strconv.Atoi(args[0])
+			out.MaybeDie(err, "invalid version %q: %v", args[0], err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			client, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			schema, err := client.ClusterConfigSchema(cmd.Context())
+			out.MaybeDie(err, "unable to query config schema: %v", err)
+
+			currentConfig, err := client.Config(cmd.Context())
+			out.MaybeDie(err, "unable to query config values: %v", err)
+
+			dir, err := configHistoryDir(clusterID(currentConfig))
+			out.MaybeDieErr(err)
+			history, err := readConfigHistory(fs, dir)
+			out.MaybeDieErr(err)
+
+			target, err := rollbackConfig(currentConfig, history, version)
+			out.MaybeDieErr(err)
+
+			_, err = applyConfig(cmd.Context(), fs, client, currentConfig, target, schema, applyOpts{
+				command:   "rollback",
+				all:       true,
+				dryRun:    dryRun,
+				noHistory: noHistory,
+			})
+			if fe := (*formattedError)(nil); errors.As(err, &fe) {
+				fmt.Fprint(os.Stderr, err)
+				out.Die("No changes were made")
+			}
+			out.MaybeDie(err, "error rolling back config: %v", err)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+	addNoHistoryFlag(cmd, &noHistory)
+	return cmd
+}
+
+func addNoHistoryFlag(cmd *cobra.Command, noHistory *bool) {
+	cmd.Flags().BoolVar(noHistory, "no-history", false, "Do not record the prior values of changed properties in the config history")
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/config")
+	t.Setenv("HOME", "/home")
+	fs := afero.NewMemMapFs()
+
+	current := admin.Config{
+		"cluster_id":       "abc",
+		"log_retention_ms": float64(1000),
+		"enable_sasl":      false,
+	}
+	dir, err := configHistoryDir(clusterID(current))
+	require.NoError(t, err)
+
+	history, err := readConfigHistory(fs, dir)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	s, err := snapshotConfig(fs, "import", current, []string{"log_retention_ms", "enable_sasl", "new_property"})
+	require.NoError(t, err)
+	s.applied(7)
+
+	// A change rejected by the server is not recorded, but a change
+	// that may have been applied is kept without a version.
+	s, err = snapshotConfig(fs, "set", current, []string{"enable_sasl"})
+	require.NoError(t, err)
+	s.failed(&admin.HTTPResponseError{Response: &http.Response{StatusCode: 400}})
+	s, err = snapshotConfig(fs, "set", current, []string{"enable_sasl"})
+	require.NoError(t, err)
+	s.failed(errors.New("connection reset"))
+
+	history, err = readConfigHistory(fs, dir)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	version := 7
+	require.Equal(t, "import", history[0].Command)
+	require.Equal(t, &version, history[0].Version)
+	require.Equal(t, []priorValue{
+		{Property: "enable_sasl", Value: false},
+		{Property: "log_retention_ms", Value: float64(1000)},
+		{Property: "new_property", Default: true},
+	}, history[0].Prior)
+	require.Equal(t, "set", history[1].Command)
+	require.Nil(t, history[1].Version)
+
+	// History is kept per cluster.
+	other, err := configHistoryDir("other")
+	require.NoError(t, err)
+	history, err = readConfigHistory(fs, other)
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
+func TestRollbackConfig(t *testing.T) {
+	intp := func(i int) *int { return &i }
+	history := []historyEntry{
+		{Version: intp(3), Prior: []priorValue{{Property: "a", Value: float64(1)}}},
+		{Version: intp(4), Prior: []priorValue{{Property: "a", Value: float64(2)}, {Property: "b", Default: true}}},
+		{Command: "force-reset", Prior: []priorValue{{Property: "c", Value: "x"}}},
+		{Version: intp(5), Prior: []priorValue{{Property: "b", Value: "y"}}},
+	}
+	current := admin.Config{"a": float64(3), "b": "z", "c": "w", "d": true}
+
+	target, err := rollbackConfig(current, history, 4)
+	require.NoError(t, err)
+	require.Equal(t, admin.Config{"a": float64(3), "b": "y", "c": "w", "d": true}, target)
+
+	target, err = rollbackConfig(current, history, 3)
+	require.NoError(t, err)
+	require.Equal(t, admin.Config{"a": float64(2), "c": "w", "d": true}, target)
+
+	target, err = rollbackConfig(current, history, 0)
+	require.NoError(t, err)
+	require.Equal(t, admin.Config{"a": float64(1), "c": "w", "d": true}, target)
+
+	_, err = rollbackConfig(current, history, 5)
+	require.Error(t, err)
+
+	// The current configuration is not modified.
+	require.Equal(t, admin.Config{"a": float64(3), "b": "z", "c": "w", "d": true}, current)
+}
@@ -15,7 +15,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"reflect"
 	"sort"
 	"strings"
 
@@ -24,7 +23,6 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
-	yaml "gopkg.in/yaml.v3"
 )
 
 type formattedError struct {
@@ -35,153 +33,104 @@ func (fe *formattedError) Error() string {
 	return fe.s
 }
 
+// applyOpts control how applyConfig changes the cluster configuration.
+type applyOpts struct {
+	command   string // the command making the change, recorded in the history
+	all       bool   // whether tunables that are missing are reset
+	dryRun    bool   // only print the changes
+	noHistory bool   // do not record the prior values in the history
+}
+
+// importConfig applies the configuration file to the cluster, printing the
+// properties that change. This returns whether the file differs from the
+// cluster configuration.
 func importConfig(
 	ctx context.Context,
+	fs afero.Fs,
 	client *admin.AdminAPI,
 	filename string,
 	oldConfig admin.Config,
 	schema admin.ConfigSchema,
-	all bool,
-) (err error) {
-	readbackBytes, err := os.ReadFile(filename)
-	if err != nil {
-		return fmt.Errorf("error reading file %s: %v", filename, err)
-	}
-	var readbackConfig admin.Config
-	err = yaml.Unmarshal(readbackBytes, &readbackConfig)
+	opts applyOpts,
+) (changed bool, err error) {
+	readbackConfig, err := readConfigFile(filename)
 	if err != nil {
-		return fmt.Errorf("error parsing edited config: %v", err)
+		return false, err
 	}
+	return applyConfig(ctx, fs, client, oldConfig, readbackConfig, schema, opts)
+}
 
-	type propertyDelta struct {
-		Property string
-		OldValue string
-		NewValue string
+// applyConfig changes the cluster configuration from oldConfig to newConfig,
+// printing the properties that change. Unless disabled, the prior values of
+// the changed properties are recorded in the history before the change.
+func applyConfig(
+	ctx context.Context,
+	fs afero.Fs,
+	client *admin.AdminAPI,
+	oldConfig admin.Config,
+	newConfig admin.Config,
+	schema admin.ConfigSchema,
+	opts applyOpts,
+) (changed bool, err error) {
+	d := diffConfig(oldConfig, newConfig, schema, opts.all)
+	if d.empty() {
+		fmt.Println("No changes were made.")
+		return false, nil
 	}
-	var propertyDeltas []propertyDelta
-
-	// Calculate deltas
-	upsert := make(map[string]interface{})
-	remove := make([]string, 0)
-	for k, v := range readbackConfig {
-		oldVal, haveOldVal := oldConfig[k]
-		if meta, ok := schema[k]; ok {
-			// For numeric types need special handling because
-			// yaml encoding will see '1' as an integer, even
-			// if it is given as the value for a floating point
-			// ('number') config property, and vice versa.
-			if meta.Type == "integer" {
-				if vFloat, ok := v.(float64); ok {
-					v = int(vFloat)
-				}
-
-				if oldVal != nil {
-					oldVal = int(oldVal.(float64))
-				}
-			} else if meta.Type == "number" {
-				if vInt, ok := v.(int); ok {
-					v = float64(vInt)
-				}
-			} else if meta.Type == "array" && meta.Items.Type == "string" {
-				switch vArray := v.(type) {
-				case []interface{}:
-					// Normal case: user input is a yaml array
-					v = loadStringArray(vArray)
-				default:
-					// Pass, let the server attempt validation
-				}
-				if oldVal != nil {
-					oldVal = loadStringArray(oldVal.([]interface{}))
-				}
-			}
-
-			// For types that aren't numeric or array, pass them through as-is
-		}
-
-		// We exclude cluster_id from upsert here and remove below to avoid any
-		// accidental duplication of the ID from one cluster to another
-		if k == "cluster_id" {
-			continue
-		}
-
-		if haveOldVal {
-			// Since the admin endpoint will redact secret fields, ignore any
-			// such sentinel strings we've been given, to avoid accidentally
-			// setting configs to this value.
-			// TODO: why doesn't this work with DeepEqual?
-			if fmt.Sprintf("%v", oldVal) == "[secret]" && fmt.Sprintf("%v", v) == "[secret]" {
-				continue
-			}
-			// If value changed, add it to list of updates.
-			// DeepEqual because values can be slices.
-			if !reflect.DeepEqual(oldVal, v) {
-				propertyDeltas = append(propertyDeltas, propertyDelta{k, fmt.Sprintf("%v", oldVal), fmt.Sprintf("%v", v)})
-				upsert[k] = v
-			}
-		} else {
-			// Present in input but not original config, insert
-			upsert[k] = v
-			propertyDeltas = append(propertyDeltas, propertyDelta{k, "", fmt.Sprintf("%v", v)})
-		}
+	if errs := validateConfig(d.upsert, schema); errs != nil {
+		return true, &formattedError{formatValidationErrors(errs)}
 	}
 
-	for k := range oldConfig {
-		if _, found := readbackConfig[k]; !found {
-			if k == "cluster_id" {
-				// see above
-				continue
-			}
+	printConfigDiff(d)
 
-			meta, inSchema := schema[k]
-			if !inSchema {
-				continue
-			}
-
-			if !all && meta.Visibility == "tunable" {
-				continue
-			}
-			oldValue := oldConfig[k]
-			propertyDeltas = append(propertyDeltas, propertyDelta{k, fmt.Sprintf("%v", oldValue), ""})
-			remove = append(remove, k)
-		}
-	}
+	// Newline between table and result of write
+	fmt.Printf("\n")
 
-	if len(upsert) == 0 && len(remove) == 0 {
-		fmt.Println("No changes were made.")
-		return nil
+	if opts.dryRun {
+		fmt.Println("Dry run, no changes were made.")
+		return true, nil
 	}
 
-	tw := out.NewTable("PROPERTY", "PRIOR", "NEW")
-	for _, pd := range propertyDeltas {
-		tw.PrintStructFields(pd)
+	var snapshot *configSnapshot
+	if !opts.noHistory {
+		changedProperties := append([]string(nil), d.remove...)
+		for k := range d.upsert {
+			changedProperties = append(changedProperties, k)
+		}
+		snapshot, err = snapshotConfig(fs, opts.command, oldConfig, changedProperties)
+		if err != nil {
+			return true, fmt.Errorf("unable to record config history (use --no-history to skip): %v", err)
+		}
 	}
-	tw.Flush()
-
-	// Newline between table and result of write
-	fmt.Printf("\n")
 
 	// PUT to admin API
-	result, err := client.PatchClusterConfig(ctx, upsert, remove)
+	result, err := client.PatchClusterConfig(ctx, d.upsert, d.remove)
+	if err != nil && snapshot != nil {
+		snapshot.failed(err)
+	}
 	if he := (*admin.HTTPResponseError)(nil); errors.As(err, &he) {
 		// Special case 400 (validation) errors with friendly output
 		// about which configuration properties were invalid.
 		if he.Response.StatusCode == 400 {
 			ve, err := formatValidationError(err, he)
 			if err != nil {
-				return fmt.Errorf("error setting config: %v", err)
+				return true, fmt.Errorf("error setting config: %v", err)
 			}
-			return &formattedError{ve}
+			return true, &formattedError{ve}
 		}
 	}
 
 	// If we didn't handle a structured 400 error, check for other errors.
 	if err != nil {
-		return fmt.Errorf("error setting config: %v", err)
+		return true, fmt.Errorf("error setting config: %v", err)
 	}
 
+	if snapshot != nil {
+		snapshot.applied(result.ConfigVersion)
+	}
 	fmt.Printf("Successfully updated configuration. New configuration version is %d.\n", result.ConfigVersion)
 
-	return nil
+	return true, nil
 }
 
 func formatValidationError(
@@ -194,7 +143,12 @@ func formatValidationError(
 	if bodyErr != nil {
 		return "", err
 	}
+	return formatValidationErrors(validationErrs), nil
+}
 
+// formatValidationErrors formats errors keyed by property, as returned by the
+// server or by validateConfig.
+func formatValidationErrors(validationErrs map[string]string) string {
 	type kv struct{ k, v string }
 	var sortedErrs []kv
 	for k, v := range validationErrs {
@@ -209,11 +163,16 @@ func formatValidationError(
 	}
 	fmt.Fprintf(&buf, "\n")
 
-	return buf.String(), nil
+	return buf.String()
 }
 
 func newImportCommand(fs afero.Fs, all *bool) *cobra.Command {
-	var filename string
+	var (
+		filename  string
+		dryRun    bool
+		exitCode  bool
+		noHistory bool
+	)
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import cluster configuration from a file",
@@ -223,7 +182,18 @@ Import configuration from a YAML file, usually generated with
 corresponding 'export' command.  This downloads the current cluster
 configuration, calculates the difference with the YAML file, and
 updates any properties that were changed.  If a property is removed
-from the YAML file, it is reset to its default value.  `,
+from the YAML file, it is reset to its default value.
+
+Before anything is changed, every added and changed value is validated against
+the cluster's configuration schema, and all invalid values are reported.
+
+With --dry-run, the changes are printed but not applied; the 'diff'
+subcommand prints the same changes without applying them. With --exit-code,
+this command exits with status 2 if the file differs from the cluster
+configuration, which is useful to detect drift in CI.
+
+The prior values of changed properties are recorded in the config history,
+see 'rpk cluster config history'.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
 			cfg, err := p.Load(fs)
@@ -241,12 +211,20 @@ from the YAML file, it is reset to its default value.  `,
 			out.MaybeDie(err, "unable to query config values: %v", err)
 
 			// Read back template & parse
-			err = importConfig(cmd.Context(), client, filename, currentConfig, schema, *all)
+			changed, err := importConfig(cmd.Context(), fs, client, filename, currentConfig, schema, applyOpts{
+				command:   "import",
+				all:       *all,
+				dryRun:    dryRun,
+				noHistory: noHistory,
+			})
 			if fe := (*formattedError)(nil); errors.As(err, &fe) {
 				fmt.Fprint(os.Stderr, err)
 				out.Die("No changes were made")
 			}
 			out.MaybeDie(err, "error updating config: %v", err)
+			if exitCode && changed {
+				os.Exit(diffExitCode)
+			}
 		},
 	}
 
@@ -257,6 +235,9 @@ from the YAML file, it is reset to its default value.  `,
 		"",
 		"full path to file to import, e.g. '/tmp/config.yml'",
 	)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 2 if the file differs from the cluster configuration")
+	addNoHistoryFlag(cmd, &noHistory)
 	return cmd
 }
 
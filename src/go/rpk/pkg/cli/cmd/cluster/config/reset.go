@@ -20,7 +20,10 @@ import (
 )
 
 func newForceResetCommand(fs afero.Fs) *cobra.Command {
-	var configCacheFile string
+	var (
+		configCacheFile string
+		noHistory       bool
+	)
 	cmd := &cobra.Command{
 		Use:   "force-reset [PROPERTY...]",
 		Short: "Forcibly clear a cluster configuration property on this node",
@@ -36,6 +39,11 @@ use the 'edit' command and delete the property's line.
 This command erases a named property from an internal cache of the cluster
 configuration on the local node, so that on next startup redpanda will treat
 the setting as if it was set to the default.
+
+The prior values of the properties are recorded in the config history, see
+'rpk cluster config history'. Because this command does not change the
+cluster's configuration version, these values cannot be restored with
+'rollback'; restore them with 'set' once the cluster is healthy.
 `,
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, propertyNames []string) {
@@ -60,6 +68,14 @@ the setting as if it was set to the default.
 			err = yaml.Unmarshal(f, content)
 			out.MaybeDie(err, "Couldn't parse %q: %v", configCacheFile, err)
 
+			if !noHistory {
+				snapshot, err := newConfigSnapshot(fs, "force-reset", content, propertyNames)
+				out.MaybeDie(err, "unable to record config history (use --no-history to skip): %v", err)
+				snapshot.entry.CacheFile = configCacheFile
+				err = snapshot.write()
+				out.MaybeDie(err, "unable to record config history (use --no-history to skip): %v", err)
+			}
+
 			// Snip out the value we are resetting
 			for _, pn := range propertyNames {
 				delete(content, pn)
@@ -81,6 +97,7 @@ the setting as if it was set to the default.
 		"",
 		"location of configuration cache file (defaults to redpanda data directory)",
 	)
+	addNoHistoryFlag(cmd, &noHistory)
 
 	return cmd
 }
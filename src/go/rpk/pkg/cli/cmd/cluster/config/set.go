@@ -12,6 +12,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -22,6 +23,7 @@ import (
 )
 
 func newSetCommand(fs afero.Fs) *cobra.Command {
+	var noHistory bool
 	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a single cluster configuration property",
@@ -30,7 +32,13 @@ func newSetCommand(fs afero.Fs) *cobra.Command {
 This command is provided for use in scripts.  For interactive editing, or bulk
 changes, use the 'edit' and 'import' commands respectively.
 
-If an empty string is given as the value, the property is reset to its default.`,
+If an empty string is given as the value, the property is reset to its default.
+
+The value is validated against the cluster's configuration schema before it is
+sent to the cluster.
+
+The prior value of the property is recorded in the config history, see
+'rpk cluster config history'.`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			key := args[0]
@@ -48,7 +56,8 @@ If an empty string is given as the value, the property is reset to its default.`
 
 			meta, ok := schema[key]
 			if !ok {
-				out.Die("Unknown property %q", key)
+				fmt.Fprint(os.Stderr, formatValidationErrors(validateConfig(map[string]interface{}{key: value}, schema)))
+				out.Die("No changes were made")
 			}
 
 			upsert := make(map[string]interface{})
@@ -78,7 +87,23 @@ If an empty string is given as the value, the property is reset to its default.`
 				upsert[key] = value
 			}
 
+			if errs := validateConfig(upsert, schema); errs != nil {
+				fmt.Fprint(os.Stderr, formatValidationErrors(errs))
+				out.Die("No changes were made")
+			}
+
+			var snapshot *configSnapshot
+			if !noHistory {
+				currentConfig, err := client.Config(cmd.Context())
+				out.MaybeDie(err, "unable to query config values: %v", err)
+				snapshot, err = snapshotConfig(fs, "set", currentConfig, []string{key})
+				out.MaybeDie(err, "unable to record config history (use --no-history to skip): %v", err)
+			}
+
 			result, err := client.PatchClusterConfig(cmd.Context(), upsert, remove)
+			if err != nil && snapshot != nil {
+				snapshot.failed(err)
+			}
 			if he := (*admin.HTTPResponseError)(nil); errors.As(err, &he) {
 				// Special case 400 (validation) errors with friendly output
 				// about which configuration properties were invalid.
@@ -90,9 +115,12 @@ If an empty string is given as the value, the property is reset to its default.`
 			}
 
 			out.MaybeDie(err, "error setting property: %v", err)
+			if snapshot != nil {
+				snapshot.applied(result.ConfigVersion)
+			}
 			fmt.Printf("Successfully updated configuration. New configuration version is %d.\n", result.ConfigVersion)
 		},
 	}
-
+	addNoHistoryFlag(cmd, &noHistory)
 	return cmd
 }
@@ -0,0 +1,232 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+)
+
+// validateConfig checks every upserted property against the schema, returning
+// all errors keyed by property, or nil if every property is valid. Values may
+// be typed (as decoded from YAML) or strings (as given to 'set'); the server
+// remains the authority, so these checks err on the side of passing values
+// through.
+func validateConfig(
+	upsert map[string]interface{}, schema admin.ConfigSchema,
+) map[string]string {
+	errs := make(map[string]string)
+	for k, v := range upsert {
+		meta, ok := schema[k]
+		if !ok {
+			msg := "unknown property"
+			if suggestion := suggestProperty(k, schema); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			errs[k] = msg
+			continue
+		}
+		if err := validateValue(v, meta); err != nil {
+			errs[k] = err.Error()
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateValue(v interface{}, meta admin.ConfigPropertyMetadata) error {
+	if v == nil {
+		if !meta.Nullable {
+			return fmt.Errorf("may not be null")
+		}
+		return nil
+	}
+	if meta.Type != "array" {
+		return validateScalar(v, meta.Type, meta.EnumValues)
+	}
+	a, ok := v.([]interface{})
+	if !ok {
+		if s, isString := v.([]string); isString {
+			for _, e := range s {
+				a = append(a, e)
+			}
+		} else {
+			return fmt.Errorf("expected a list, got %s", describeValue(v))
+		}
+	}
+	for i, e := range a {
+		if err := validateScalar(e, meta.Items.Type, meta.EnumValues); err != nil {
+			return fmt.Errorf("element %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateScalar checks a value against a swagger scalar type and, if there
+// are any, the permitted enum values.
+func validateScalar(v interface{}, typ string, enum []string) error {
+	s, isString := v.(string)
+	switch typ {
+	case "boolean":
+		if isString {
+			if _, err := strconv.ParseBool(s); err != nil {
+				return fmt.Errorf("expected a boolean, got %q", s)
+			}
+		} else if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %s", describeValue(v))
+		}
+	case "integer":
+		if err := validateInteger(v); err != nil {
+			return err
+		}
+	case "number":
+		var f float64
+		switch n := v.(type) {
+		case string:
+			var err error
+			if f, err = strconv.ParseFloat(strings.TrimSpace(n), 64); err != nil {
+				return fmt.Errorf("expected a number, got %q", n)
+			}
+		case int, int64, uint64:
+		case float64:
+			f = n
+		default:
+			return fmt.Errorf("expected a number, got %s", describeValue(v))
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%v is out of range", f)
+		}
+	case "string":
+		switch v.(type) {
+		case []interface{}, map[string]interface{}:
+			return fmt.Errorf("expected a string, got %s", describeValue(v))
+		}
+	}
+
+	if len(enum) > 0 {
+		str := fmt.Sprintf("%v", v)
+		for _, e := range enum {
+			if str == e {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", str, strings.Join(enum, ", "))
+	}
+	return nil
+}
+
+// validateInteger checks that the value is a whole number that fits in an
+// int64, which is the widest integer type of any property.
+func validateInteger(v interface{}) error {
+	switch n := v.(type) {
+	case int, int64:
+		return nil
+	case uint64:
+		if n > math.MaxInt64 {
+			return fmt.Errorf("%d is out of range", n)
+		}
+		return nil
+	case float64:
+		if n != math.Trunc(n) {
+			return fmt.Errorf("expected an integer, got %v", n)
+		}
+		if n < math.MinInt64 || n >= math.MaxInt64 {
+			return fmt.Errorf("%v is out of range", n)
+		}
+		return nil
+	case string:
+		s := strings.TrimSpace(n)
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			if ne := (*strconv.NumError)(nil); errors.As(err, &ne) && ne.Err == strconv.ErrRange {
+				return fmt.Errorf("%s is out of range", s)
+			}
+			return fmt.Errorf("expected an integer, got %q", n)
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected an integer, got %s", describeValue(v))
+	}
+}
+
+func describeValue(v interface{}) string {
+	switch v.(type) {
+	case []interface{}, []string:
+		return "a list"
+	case map[string]interface{}:
+		return "a map"
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case string:
+		return fmt.Sprintf("string %q", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// suggestProperty returns the property in the schema that is closest to the
+// unknown property, if any is close enough to likely be a typo.
+func suggestProperty(unknown string, schema admin.ConfigSchema) string {
+	var (
+		best     string
+		bestDist = math.MaxInt
+	)
+	for k := range schema {
+		d := levenshtein(unknown, k)
+		if d < bestDist || d == bestDist && k < best {
+			best, bestDist = k, d
+		}
+	}
+	maxDist := len(unknown) / 3
+	if maxDist < 2 {
+		maxDist = 2
+	}
+	if bestDist > maxDist {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
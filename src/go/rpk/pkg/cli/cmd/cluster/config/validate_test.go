@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	schema := admin.ConfigSchema{
+		"log_retention_ms":      {Type: "integer", Nullable: true, Units: "ms"},
+		"log_segment_size":      {Type: "integer", Units: "bytes"},
+		"log_compression_ratio": {Type: "number"},
+		"enable_sasl":           {Type: "boolean"},
+		"log_cleanup_policy":    {Type: "string", EnumValues: []string{"delete", "compact", "compact,delete"}},
+		"superusers":            {Type: "array", Items: admin.ConfigPropertyItems{Type: "string"}},
+		"admin_ports":           {Type: "array", Items: admin.ConfigPropertyItems{Type: "integer"}},
+		"cloud_storage_region":  {Type: "string"},
+	}
+
+	// Typed values, as decoded from YAML, and strings, as given to set.
+	require.Nil(t, validateConfig(map[string]interface{}{
+		"log_retention_ms":      nil,
+		"log_segment_size":      1 << 30,
+		"log_compression_ratio": 2,
+		"enable_sasl":           true,
+		"log_cleanup_policy":    "compact",
+		"superusers":            []interface{}{"admin", 1},
+		"admin_ports":           []interface{}{9644, float64(9645)},
+		"cloud_storage_region":  "us-east-1",
+	}, schema))
+	require.Nil(t, validateConfig(map[string]interface{}{
+		"log_retention_ms":      "-1",
+		"log_segment_size":      "1073741824",
+		"log_compression_ratio": "1.5",
+		"enable_sasl":           "false",
+		"log_cleanup_policy":    "compact,delete",
+		"superusers":            []string{"admin"},
+	}, schema))
+
+	require.Equal(t, map[string]string{
+		"log_retention_ms":      `expected an integer, got "1h"`,
+		"log_segment_size":      "may not be null",
+		"log_compression_ratio": "expected a number, got boolean true",
+		"enable_sasl":           `expected a boolean, got "yes please"`,
+		"log_cleanup_policy":    `"archive" is not one of delete, compact, compact,delete`,
+		"superusers":            "expected a list, got string \"admin\"",
+		"admin_ports":           "element 1: expected an integer, got 1.5",
+		"cloud_storage_region":  "expected a string, got a map",
+		"enable_sasll":          `unknown property, did you mean "enable_sasl"?`,
+		"completely_unrelated":  "unknown property",
+	}, validateConfig(map[string]interface{}{
+		"log_retention_ms":      "1h",
+		"log_segment_size":      nil,
+		"log_compression_ratio": true,
+		"enable_sasl":           "yes please",
+		"log_cleanup_policy":    "archive",
+		"superusers":            "admin",
+		"admin_ports":           []interface{}{1, 1.5},
+		"cloud_storage_region":  map[string]interface{}{"a": "b"},
+		"enable_sasll":          true,
+		"completely_unrelated":  1,
+	}, schema))
+
+	require.Equal(t, map[string]string{
+		"log_segment_size": "9223372036854775808 is out of range",
+	}, validateConfig(map[string]interface{}{"log_segment_size": "9223372036854775808"}, schema))
+}
+
+func TestSuggestProperty(t *testing.T) {
+	schema := admin.ConfigSchema{
+		"log_retention_ms":    {},
+		"retention_bytes":     {},
+		"auto_create_topics":  {},
+		"enable_idempotence":  {},
+		"enable_transactions": {},
+	}
+	for _, test := range []struct {
+		in, exp string
+	}{
+		{"log_retention", "log_retention_ms"},
+		{"retention_byte", "retention_bytes"},
+		{"auto_create_topic_enabled", "auto_create_topics"},
+		{"transactions", ""},
+		{"enable_idempotency", "enable_idempotence"},
+		{"foo", ""},
+	} {
+		require.Equal(t, test.exp, suggestProperty(test.in, schema), "input %s", test.in)
+	}
+	require.Equal(t, 3, levenshtein("kitten", "sitting"))
+	require.Equal(t, 0, levenshtein("", ""))
+	require.Equal(t, 4, levenshtein("", "abcd"))
+}
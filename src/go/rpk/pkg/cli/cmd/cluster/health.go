@@ -24,8 +24,9 @@ import (
 
 func newHealthOverviewCommand(fs afero.Fs) *cobra.Command {
 	var (
-		watch bool
-		exit  bool
+		watch  bool
+		exit   bool
+		format out.Format
 
 		adminURL       string
 		adminEnableTLS bool
@@ -45,6 +46,9 @@ following conditions are met:
 * all cluster nodes are responding
 * all partitions have leaders
 * the cluster controller is present
+
+With --format json or yaml, the overview is printed as an object; with --watch,
+one object is printed per change.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, _ []string) {
@@ -60,7 +64,7 @@ following conditions are met:
 				ret, err := cl.GetHealthOverview(cmd.Context())
 				out.MaybeDie(err, "unable to request cluster health: %v", err)
 				if !reflect.DeepEqual(ret, lastOverview) {
-					printHealthOverview(&ret)
+					format.Print(ret, func() { printHealthOverview(&ret) })
 				}
 				lastOverview = ret
 				if !watch || exit && lastOverview.IsHealthy {
@@ -86,6 +90,7 @@ following conditions are met:
 
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Blocks and writes out all cluster health changes")
 	cmd.Flags().BoolVarP(&exit, "exit-when-healthy", "e", false, "When used with watch, exits after cluster is back in healthy state")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
 
@@ -1,7 +1,6 @@
 package license
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -14,7 +13,7 @@ import (
 )
 
 func newInfoCommand(fs afero.Fs) *cobra.Command {
-	var format string
+	var format out.Format
 	command := &cobra.Command{
 		Use:   "info",
 		Args:  cobra.ExactArgs(0),
@@ -38,7 +37,7 @@ func newInfoCommand(fs afero.Fs) *cobra.Command {
 			out.MaybeDie(err, "unable to retrieve license info: %v", err)
 
 			if !info.Loaded {
-				if format == "json" {
+				if !format.IsText() {
 					out.Die("{}")
 				} else {
 					out.Die("this cluster is missing a license")
@@ -47,26 +46,22 @@ func newInfoCommand(fs afero.Fs) *cobra.Command {
 
 			if info.Properties != (admin.LicenseProperties{}) {
 				expired := info.Properties.Expires < 0
-				if format == "json" {
-					tm := time.Unix(info.Properties.Expires, 0).Format("Jan 2 2006")
-					props, err := json.MarshalIndent(struct {
-						Organization string
-						Type         string
-						Expires      string
-						Expired      bool `json:"license_expired,omitempty"`
-					}{info.Properties.Organization, info.Properties.Type, tm, expired}, "", "  ")
-					out.MaybeDie(err, "unable to print license information as json: %v", err)
-					fmt.Printf("%s\n", props)
-				} else {
+				tm := time.Unix(info.Properties.Expires, 0).Format("Jan 2 2006")
+				format.Print(struct {
+					Organization string
+					Type         string
+					Expires      string
+					Expired      bool `json:"license_expired,omitempty"`
+				}{info.Properties.Organization, info.Properties.Type, tm, expired}, func() {
 					printLicenseInfo(info.Properties, expired)
-				}
+				})
 			} else {
 				out.Die("no license loaded")
 			}
 		},
 	}
 
-	command.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	out.AddFormatFlag(command, &format)
 	return command
 }
 
@@ -70,6 +70,7 @@ func newLogdirsDescribeCommand(fs afero.Fs) *cobra.Command {
 		sortBySize    bool
 		topics        []string
 		aggregateInto string
+		format        out.Format
 	)
 
 	cmd := &cobra.Command{
@@ -90,6 +91,9 @@ the partition data lives underneath the the returned root directory in
     kafka/{topic}/{partition}_{revision}/
 
 where revision is a Redpanda internal concept.
+
+With --format json or yaml, the rows are printed as a list of objects with the
+same fields as the columns of the requested aggregation.
 `,
 
 		Args: cobra.ExactArgs(0),
@@ -177,22 +181,26 @@ where revision is a Redpanda internal concept.
 
 			var headers []string
 			var rowfn func(*out.TabWriter, row)
+			var depth int // how many of broker, dir, topic, partition are kept
 			switch strings.ToLower(aggregateInto) {
 			default:
 				out.Die("unrecognized --aggregate-into %q", aggregateInto)
 
 			case "broker":
 				headers = []string{"broker", "size", "error"}
+				depth = 1
 				collapse(func(prior, current row) bool { return prior.Broker != current.Broker })
 				rowfn = func(tw *out.TabWriter, r row) { tw.Print(r.Broker, r.Size, r.Err) }
 
 			case "dir":
 				headers = []string{"broker", "dir", "size", "error"}
+				depth = 2
 				collapse(func(prior, current row) bool { return prior.Broker != current.Broker || prior.Dir != current.Dir })
 				rowfn = func(tw *out.TabWriter, r row) { tw.Print(r.Broker, r.Dir, r.Size, r.Err) }
 
 			case "topic":
 				headers = []string{"broker", "dir", "topic", "size", "error"}
+				depth = 3
 				collapse(func(prior, current row) bool {
 					return prior.Broker != current.Broker || prior.Dir != current.Dir || prior.Topic != current.Topic
 				})
@@ -200,6 +208,7 @@ where revision is a Redpanda internal concept.
 
 			case "", "partition":
 				headers = []string{"broker", "dir", "topic", "partition", "size", "error"}
+				depth = 4
 				rowfn = func(tw *out.TabWriter, r row) { tw.PrintStructFields(r) }
 			}
 
@@ -212,6 +221,26 @@ where revision is a Redpanda internal concept.
 				})
 			}
 
+			if !format.IsText() {
+				dirs := []logdir{}
+				for _, r := range rows {
+					d := logdir{Broker: r.Broker, Size: r.Size, Error: r.Err}
+					if depth >= 2 {
+						d.Dir = r.Dir
+					}
+					if depth >= 3 {
+						d.Topic = r.Topic
+					}
+					if depth >= 4 && r.Err == "" {
+						partition := r.Partition
+						d.Partition = &partition
+					}
+					dirs = append(dirs, d)
+				}
+				format.Print(dirs, nil)
+				return
+			}
+
 			tw := out.NewTable(headers...)
 			defer tw.Flush()
 			for _, row := range rows {
@@ -224,5 +253,17 @@ where revision is a Redpanda internal concept.
 	cmd.Flags().BoolVar(&sortBySize, "sort-by-size", false, "If true, sort by size")
 	cmd.Flags().StringSliceVar(&topics, "topics", nil, "Specific topics to describe")
 	cmd.Flags().StringVar(&aggregateInto, "aggregate-into", "", "If non-empty, what column to aggregate into starting from the partition column (broker, dir, topic)")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
+
+// logdir is the structured output of a (possibly aggregated) log directory
+// row.
+type logdir struct {
+	Broker    int32  `json:"broker"`
+	Dir       string `json:"dir,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+	Partition *int32 `json:"partition,omitempty"`
+	Size      int64  `json:"size"`
+	Error     string `json:"error,omitempty"`
+}
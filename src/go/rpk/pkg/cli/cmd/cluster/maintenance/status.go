@@ -37,7 +37,14 @@ func addBrokerMaintenanceReport(table *out.TabWriter, b admin.Broker) {
 		b.Maintenance.Failed)
 }
 
+// nodeMaintenance is the structured output of a node's maintenance status.
+type nodeMaintenance struct {
+	NodeID int `json:"node_id"`
+	admin.MaintenanceStatus
+}
+
 func newStatusCommand(fs afero.Fs) *cobra.Command {
+	var format out.Format
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Report maintenance status",
@@ -68,6 +75,9 @@ Notes:
 
    - Only partitions with more than one replica are eligible for leadership
      transfer.
+
+   - With --format json or yaml, each node is printed as an object with the
+     same fields in snake_case.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -89,6 +99,19 @@ Notes:
 				out.Die("Maintenance mode is not supported in this cluster")
 			}
 
+			if !format.IsText() {
+				var nodes []nodeMaintenance
+				for _, b := range brokers {
+					n := nodeMaintenance{NodeID: b.NodeID}
+					if b.Maintenance != nil {
+						n.MaintenanceStatus = *b.Maintenance
+					}
+					nodes = append(nodes, n)
+				}
+				format.Print(nodes, nil)
+				return
+			}
+
 			table := newMaintenanceReportTable()
 			defer table.Flush()
 			for _, broker := range brokers {
@@ -96,5 +119,6 @@ Notes:
 			}
 		},
 	}
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
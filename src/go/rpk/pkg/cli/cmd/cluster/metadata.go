@@ -32,6 +32,7 @@ func newMetadataCommand(fs afero.Fs) *cobra.Command {
 		topics   bool
 		internal bool
 		detailed bool
+		format   out.Format
 	)
 	cmd := &cobra.Command{
 		Use:     "metadata",
@@ -52,6 +53,9 @@ can be printed with the -d flag, and internal topics can be printed with the -i
 flag.
 
 In the broker section, the controller node is suffixed with *.
+
+With --format json or yaml, the requested sections are printed as one object
+with the keys cluster, controller and brokers, and topics.
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
@@ -100,6 +104,11 @@ In the broker section, the controller node is suffixed with *.
 			}
 			out.MaybeDie(err, "unable to request metadata: %v", err)
 
+			if !format.IsText() {
+				format.Print(metadataOutput(m, cluster, brokers, topics, internal, detailed), nil)
+				return
+			}
+
 			// We only print the cluster section if the response
 			// has a cluster.
 			if cluster && m.Cluster != "" {
@@ -125,9 +134,104 @@ In the broker section, the controller node is suffixed with *.
 	cmd.Flags().BoolVarP(&topics, "print-topics", "t", false, "Print topics section (implied if any topics are specified)")
 	cmd.Flags().BoolVarP(&internal, "print-internal-topics", "i", false, "Print internal topics (if all topics requested, implies -t)")
 	cmd.Flags().BoolVarP(&detailed, "print-detailed-topics", "d", false, "Print per-partition information for topics (implies -t)")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
 
+// metadata is the structured output of the metadata command.
+type metadata struct {
+	Cluster    string         `json:"cluster,omitempty"`
+	Controller *int32         `json:"controller,omitempty"`
+	Brokers    []broker       `json:"brokers,omitempty"`
+	Topics     []TopicSummary `json:"topics,omitempty"`
+}
+
+type broker struct {
+	NodeID int32   `json:"node_id"`
+	Host   string  `json:"host"`
+	Port   int32   `json:"port"`
+	Rack   *string `json:"rack,omitempty"`
+}
+
+// TopicSummary is the structured output of a topic in metadata.
+type TopicSummary struct {
+	Name       string             `json:"name"`
+	Internal   bool               `json:"internal,omitempty"`
+	Partitions int                `json:"partitions"`
+	Replicas   int                `json:"replicas"`
+	Error      string             `json:"error,omitempty"`
+	Details    []PartitionSummary `json:"partition_details,omitempty"`
+}
+
+// PartitionSummary is the structured output of a partition in metadata.
+type PartitionSummary struct {
+	Partition       int32   `json:"partition"`
+	Leader          int32   `json:"leader"`
+	LeaderEpoch     int32   `json:"leader_epoch"`
+	Replicas        []int32 `json:"replicas"`
+	ISR             []int32 `json:"isr"`
+	OfflineReplicas []int32 `json:"offline_replicas,omitempty"`
+	LoadError       string  `json:"load_error,omitempty"`
+}
+
+func metadataOutput(m kadm.Metadata, cluster, brokers, topics, internal, detailed bool) metadata {
+	var o metadata
+	if cluster {
+		o.Cluster = m.Cluster
+	}
+	if brokers {
+		if m.Controller >= 0 {
+			controller := m.Controller
+			o.Controller = &controller
+		}
+		for _, b := range m.Brokers {
+			o.Brokers = append(o.Brokers, broker{b.NodeID, b.Host, b.Port, b.Rack})
+		}
+	}
+	if topics {
+		o.Topics = TopicSummaries(m.Topics, internal, detailed)
+	}
+	return o
+}
+
+// TopicSummaries returns the structured output of topics, optionally
+// including internal topics and per-partition details.
+func TopicSummaries(topics kadm.TopicDetails, internal, detailed bool) []TopicSummary {
+	summaries := []TopicSummary{}
+	for _, topic := range topics.Sorted() {
+		if !internal && topic.IsInternal {
+			continue
+		}
+		s := TopicSummary{
+			Name:       topic.Topic,
+			Internal:   topic.IsInternal,
+			Partitions: len(topic.Partitions),
+			Replicas:   topic.Partitions.NumReplicas(),
+		}
+		if topic.Err != nil {
+			s.Error = topic.Err.Error()
+		}
+		if detailed {
+			for _, p := range topic.Partitions.Sorted() {
+				ps := PartitionSummary{
+					Partition:       p.Partition,
+					Leader:          p.Leader,
+					LeaderEpoch:     p.LeaderEpoch,
+					Replicas:        int32s(p.Replicas).sort(),
+					ISR:             int32s(p.ISR).sort(),
+					OfflineReplicas: int32s(p.OfflineReplicas).sort(),
+				}
+				if p.Err != nil {
+					ps.LoadError = p.Err.Error()
+				}
+				s.Details = append(s.Details, ps)
+			}
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
 func printBrokers(controllerID int32, brokers kadm.BrokerDetails) {
 	headers := []string{"ID", "HOST", "PORT"}
 	args := func(b *kadm.BrokerDetail) []interface{} {
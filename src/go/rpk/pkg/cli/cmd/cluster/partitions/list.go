@@ -0,0 +1,342 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// Partition issues reported by the list command.
+const (
+	issueLeaderless      = "leaderless"
+	issueUnderReplicated = "under-replicated"
+	issueMoving          = "moving"
+	issueError           = "error"
+)
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	var (
+		re            bool
+		node          int
+		unhealthyOnly bool
+		format        out.Format
+	)
+	cmd := &cobra.Command{
+		Use:     "list [TOPICS...]",
+		Aliases: []string{"ls"},
+		Short:   "List partitions with their replicas, leader, and health",
+		Long: `List partitions with their replicas, leader, and health.
+
+This command combines the cluster's Kafka metadata with the admin API's
+partition details to print, for every partition, its leader, its replicas with
+the core (shard) each replica is assigned to, its raft group ID, and its
+reconfiguration status. Replicas that are not in sync are marked with '*'.
+
+By default, all partitions of all topics are listed. Topics can be specified
+as arguments; with --regex (-r), the arguments are parsed as regular
+expressions, which must match the whole topic name.
+
+Each partition reports any issues it has:
+
+    leaderless:        The partition has no leader.
+    under-replicated:  Some replicas are not in sync with the leader.
+    moving:            The partition's replicas are being reconfigured.
+    error:             The partition's details could not be loaded.
+
+The --unhealthy-only (-u) flag lists only partitions that have issues, and the
+--node flag lists only partitions that have a replica on the given node. Use
+--format json or yaml for output that is easier to consume from scripts.
+
+EXAMPLES
+
+List all partitions that have problems:
+    rpk cluster partitions list -u
+List partitions of topics starting with "orders" that have a replica on node 2:
+    rpk cluster partitions list -r 'orders.*' --node 2
+`,
+		Run: func(cmd *cobra.Command, topics []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			m, err := adm.Metadata(cmd.Context())
+			out.MaybeDie(err, "unable to request metadata: %v", err)
+
+			names, err := matchTopics(m.Topics.Names(), topics, re)
+			out.MaybeDieErr(err)
+
+			var listed []partitionInfo
+			for _, t := range names {
+				listed = append(listed, describeTopicPartitions(cmd.Context(), cl, m.Topics[t])...)
+			}
+			listed = filterPartitions(listed, node, unhealthyOnly)
+
+			if listed == nil {
+				listed = []partitionInfo{}
+			}
+			format.Print(listed, func() { printPartitions(listed) })
+		},
+	}
+	cmd.Flags().BoolVarP(&re, "regex", "r", false, "Parse topics as regex; list any topic that matches any expression")
+	cmd.Flags().IntVar(&node, "node", -1, "Only list partitions with a replica on this node")
+	cmd.Flags().BoolVarP(&unhealthyOnly, "unhealthy-only", "u", false, "Only list partitions that are leaderless, under-replicated, moving, or failed to load")
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+// partitionInfo is a partition as printed by the list command.
+type partitionInfo struct {
+	Namespace   string          `json:"ns"`
+	Topic       string          `json:"topic"`
+	Partition   int32           `json:"partition"`
+	Leader      int32           `json:"leader"`
+	LeaderEpoch int32           `json:"leader_epoch"`
+	Replicas    []partitionRepl `json:"replicas"`
+	RaftGroupID *int            `json:"raft_group_id,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	Issues      []string        `json:"issues"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// partitionRepl is a replica of a listed partition. The core is unknown if
+// the admin API did not return the partition's details.
+type partitionRepl struct {
+	NodeID int  `json:"node_id"`
+	Core   *int `json:"core,omitempty"`
+	InSync bool `json:"in_sync"`
+}
+
+func (p partitionInfo) healthy() bool { return len(p.Issues) == 0 }
+
+func (p partitionInfo) onNode(node int) bool {
+	for _, r := range p.Replicas {
+		if r.NodeID == node {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTopics returns the topics that match the requested topics, or all
+// topics if none are requested.
+func matchTopics(all, requested []string, re bool) ([]string, error) {
+	sort.Strings(all)
+	if len(requested) == 0 {
+		return all, nil
+	}
+	if !re {
+		exists := make(map[string]bool, len(all))
+		for _, t := range all {
+			exists[t] = true
+		}
+		for _, t := range requested {
+			if !exists[t] {
+				return nil, fmt.Errorf("topic %q does not exist", t)
+			}
+		}
+		return requested, nil
+	}
+	var compiled []*regexp.Regexp
+	for _, expression := range requested {
+		if !strings.HasPrefix(expression, "^") {
+			expression = "^" + expression
+		}
+		if !strings.HasSuffix(expression, "$") {
+			expression += "$"
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compile regex %q: %w", expression, err)
+		}
+		compiled = append(compiled, re)
+	}
+	var matched []string
+	for _, t := range all {
+		for _, re := range compiled {
+			if re.MatchString(t) {
+				matched = append(matched, t)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// describeTopicPartitions merges a topic's Kafka metadata with the admin
+// API's partition details. Admin API failures are not fatal: the partitions
+// are still listed from metadata, without their cores and raft groups.
+func describeTopicPartitions(
+	ctx context.Context, cl *admin.AdminAPI, t kadm.TopicDetail,
+) []partitionInfo {
+	details := make(map[int32]admin.Partition)
+	if t.Err == nil {
+		ps, err := cl.GetTopicPartitions(ctx, "kafka", t.Topic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to request admin details of topic %q: %v\n", t.Topic, err)
+		}
+		for _, p := range ps {
+			details[int32(p.PartitionID)] = p
+		}
+	}
+	return buildPartitions(t, details)
+}
+
+func buildPartitions(t kadm.TopicDetail, details map[int32]admin.Partition) []partitionInfo {
+	if t.Err != nil {
+		return []partitionInfo{{
+			Namespace: "kafka",
+			Topic:     t.Topic,
+			Partition: -1,
+			Leader:    -1,
+			Issues:    []string{issueError},
+			Error:     t.Err.Error(),
+		}}
+	}
+	var ps []partitionInfo
+	for _, pd := range t.Partitions.Sorted() {
+		p := partitionInfo{
+			Namespace:   "kafka",
+			Topic:       t.Topic,
+			Partition:   pd.Partition,
+			Leader:      pd.Leader,
+			LeaderEpoch: pd.LeaderEpoch,
+			Replicas:    []partitionRepl{},
+			Issues:      []string{},
+		}
+		if pd.Err != nil {
+			p.Error = pd.Err.Error()
+		}
+
+		detail, hasDetail := details[pd.Partition]
+		cores := make(map[int]int)
+		if hasDetail {
+			id := detail.RaftGroupID
+			p.RaftGroupID = &id
+			p.Status = detail.Status
+			for _, r := range detail.Replicas {
+				cores[r.NodeID] = r.Core
+			}
+		}
+		isr := make(map[int32]bool)
+		for _, r := range pd.ISR {
+			isr[r] = true
+		}
+		underReplicated := false
+		for _, r := range pd.Replicas {
+			repl := partitionRepl{NodeID: int(r), InSync: isr[r]}
+			if c, ok := cores[int(r)]; ok {
+				repl.Core = &c
+			}
+			if !repl.InSync {
+				underReplicated = true
+			}
+			p.Replicas = append(p.Replicas, repl)
+		}
+
+		if pd.Leader < 0 {
+			p.Issues = append(p.Issues, issueLeaderless)
+		}
+		if underReplicated {
+			p.Issues = append(p.Issues, issueUnderReplicated)
+		}
+		if hasDetail && detail.Status != "" && detail.Status != admin.PartitionStatusDone {
+			p.Issues = append(p.Issues, issueMoving)
+		}
+		if pd.Err != nil {
+			p.Issues = append(p.Issues, issueError)
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+func filterPartitions(ps []partitionInfo, node int, unhealthyOnly bool) []partitionInfo {
+	var keep []partitionInfo
+	for _, p := range ps {
+		if node >= 0 && !p.onNode(node) {
+			continue
+		}
+		if unhealthyOnly && p.healthy() {
+			continue
+		}
+		keep = append(keep, p)
+	}
+	return keep
+}
+
+func printPartitions(ps []partitionInfo) {
+	tw := out.NewTable("topic", "partition", "leader", "epoch", "replicas", "raft-group", "status", "issues")
+	defer tw.Flush()
+	for _, p := range ps {
+		var (
+			leader    = "-"
+			partition = "-"
+			raftGroup = "-"
+			status    = p.Status
+			issues    = strings.Join(p.Issues, ",")
+		)
+		if p.Leader >= 0 {
+			leader = fmt.Sprint(p.Leader)
+		}
+		if p.Partition >= 0 {
+			partition = fmt.Sprint(p.Partition)
+		}
+		if p.RaftGroupID != nil {
+			raftGroup = fmt.Sprint(*p.RaftGroupID)
+		}
+		if status == "" {
+			status = "-"
+		}
+		if p.Error != "" {
+			issues += ": " + p.Error
+		}
+		if issues == "" {
+			issues = "-"
+		}
+		tw.Print(p.Topic, partition, leader, p.LeaderEpoch, formatListedReplicas(p.Replicas), raftGroup, status, issues)
+	}
+}
+
+// formatListedReplicas formats replicas as node-core, marking out of sync
+// replicas with '*'.
+func formatListedReplicas(rs []partitionRepl) string {
+	s := make([]string, 0, len(rs))
+	for _, r := range rs {
+		repl := fmt.Sprint(r.NodeID)
+		if r.Core != nil {
+			repl += fmt.Sprintf("-%d", *r.Core)
+		}
+		if !r.InSync {
+			repl += "*"
+		}
+		s = append(s, repl)
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
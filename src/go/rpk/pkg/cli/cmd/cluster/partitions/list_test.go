@@ -0,0 +1,99 @@
+package partitions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestMatchTopics(t *testing.T) {
+	all := []string{"orders-eu", "orders-us", "payments", "_schemas"}
+
+	got, err := matchTopics(append([]string(nil), all...), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"_schemas", "orders-eu", "orders-us", "payments"}, got)
+
+	got, err = matchTopics(append([]string(nil), all...), []string{"payments"}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"payments"}, got)
+
+	_, err = matchTopics(append([]string(nil), all...), []string{"missing"}, false)
+	require.Error(t, err)
+
+	got, err = matchTopics(append([]string(nil), all...), []string{"orders.*", "pay"}, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"orders-eu", "orders-us"}, got)
+
+	_, err = matchTopics(append([]string(nil), all...), []string{"("}, true)
+	require.Error(t, err)
+}
+
+func TestBuildPartitions(t *testing.T) {
+	intp := func(i int) *int { return &i }
+
+	td := kadm.TopicDetail{
+		Topic: "foo",
+		Partitions: kadm.PartitionDetails{
+			0: {Topic: "foo", Partition: 0, Leader: 1, LeaderEpoch: 3, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2, 3}},
+			1: {Topic: "foo", Partition: 1, Leader: -1, Replicas: []int32{1, 2}, ISR: []int32{1}},
+			2: {Topic: "foo", Partition: 2, Leader: 2, Replicas: []int32{2, 3}, ISR: []int32{2, 3}},
+		},
+	}
+	details := map[int32]admin.Partition{
+		0: {PartitionID: 0, Status: "done", RaftGroupID: 10, Replicas: []admin.Replica{{NodeID: 1, Core: 0}, {NodeID: 2, Core: 1}, {NodeID: 3, Core: 0}}},
+		1: {PartitionID: 1, Status: "done", RaftGroupID: 11, Replicas: []admin.Replica{{NodeID: 1, Core: 1}, {NodeID: 2, Core: 0}}},
+		2: {PartitionID: 2, Status: "in_progress", RaftGroupID: 12, Replicas: []admin.Replica{{NodeID: 2, Core: 0}, {NodeID: 4, Core: 0}}},
+	}
+
+	ps := buildPartitions(td, details)
+	require.Equal(t, []partitionInfo{
+		{
+			Namespace: "kafka", Topic: "foo", Partition: 0, Leader: 1, LeaderEpoch: 3,
+			Replicas: []partitionRepl{
+				{NodeID: 1, Core: intp(0), InSync: true},
+				{NodeID: 2, Core: intp(1), InSync: true},
+				{NodeID: 3, Core: intp(0), InSync: true},
+			},
+			RaftGroupID: intp(10), Status: "done", Issues: []string{},
+		},
+		{
+			Namespace: "kafka", Topic: "foo", Partition: 1, Leader: -1,
+			Replicas: []partitionRepl{
+				{NodeID: 1, Core: intp(1), InSync: true},
+				{NodeID: 2, Core: intp(0)},
+			},
+			RaftGroupID: intp(11), Status: "done", Issues: []string{issueLeaderless, issueUnderReplicated},
+		},
+		{
+			Namespace: "kafka", Topic: "foo", Partition: 2, Leader: 2,
+			Replicas: []partitionRepl{
+				{NodeID: 2, Core: intp(0), InSync: true},
+				{NodeID: 3, InSync: true},
+			},
+			RaftGroupID: intp(12), Status: "in_progress", Issues: []string{issueMoving},
+		},
+	}, ps)
+	require.Equal(t, "[1-1 2-0*]", formatListedReplicas(ps[1].Replicas))
+	require.Equal(t, "[2-0 3]", formatListedReplicas(ps[2].Replicas))
+
+	unhealthy := filterPartitions(ps, -1, true)
+	require.Len(t, unhealthy, 2)
+	require.Equal(t, int32(1), unhealthy[0].Partition)
+	require.Equal(t, int32(2), unhealthy[1].Partition)
+
+	onNode := filterPartitions(ps, 3, false)
+	require.Len(t, onNode, 2)
+	require.Equal(t, int32(0), onNode[0].Partition)
+	require.Equal(t, int32(2), onNode[1].Partition)
+
+	both := filterPartitions(ps, 1, true)
+	require.Len(t, both, 1)
+	require.Equal(t, int32(1), both[0].Partition)
+
+	errored := buildPartitions(kadm.TopicDetail{Topic: "bar", Err: errors.New("boom")}, nil)
+	require.Equal(t, []string{issueError}, errored[0].Issues)
+	require.Equal(t, "boom", errored[0].Error)
+}
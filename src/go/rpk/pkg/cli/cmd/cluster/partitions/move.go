@@ -0,0 +1,413 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newMoveCommand(fs afero.Fs) *cobra.Command {
+	var (
+		partitions   []string
+		planFile     string
+		wait         bool
+		pollInterval time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "move",
+		Short: "Move partition replicas across nodes / cores",
+		Long: `Move partition replicas across nodes / cores.
+
+This command requests that the replicas of partitions be moved to the specified
+nodes and cores. Movements are asynchronous: Redpanda moves data in the
+background, and the new replicas only take over once they have caught up. Use
+--wait to poll the movements until they complete, or check them later with
+'rpk cluster partitions balancer-status'. An ongoing movement can be canceled
+with 'rpk cluster partitions movement-cancel'.
+
+Partitions to move are specified with --partition (-p), which can be repeated:
+
+    [NAMESPACE/]TOPIC/PARTITION:NODE[-CORE],NODE[-CORE],...
+
+The namespace defaults to "kafka". If a core is not specified, a replica that
+stays on a node keeps its current core, and a replica moved to a new node is
+assigned a core on that node.
+
+Alternatively, movements can be read from a JSON plan file with --plan-file,
+in a format similar to the one used by kafka-reassign-partitions:
+
+    {
+      "version": 1,
+      "partitions": [
+        {"topic": "foo", "partition": 0, "replicas": [1, 2, 3]},
+        {"topic": "bar", "partition": 1, "replicas": [2, 3, 4], "cores": [0, 1, 0]}
+      ]
+    }
+
+Each entry can optionally specify "ns" for the namespace and "cores" for the
+core of each replica.
+
+EXAMPLES
+
+Move partition 0 of topic foo to nodes 1, 2, and 3:
+    rpk cluster partitions move -p foo/0:1,2,3
+Move partition 0 of topic foo to core 1 of node 2, and wait for it to finish:
+    rpk cluster partitions move -p foo/0:1,2-1,3 --wait
+Move partitions from a plan file:
+    rpk cluster partitions move --plan-file plan.json
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			var (
+				moves []movement
+				err   error
+			)
+			switch {
+			case len(partitions) > 0 && planFile != "":
+				out.Die("--partition and --plan-file cannot be used together")
+			case planFile != "":
+				raw, err := afero.ReadFile(fs, planFile)
+				out.MaybeDie(err, "unable to read %q: %v", planFile, err)
+				moves, err = parsePlan(raw)
+				out.MaybeDie(err, "unable to parse %q: %v", planFile, err)
+			case len(partitions) > 0:
+				for _, p := range partitions {
+					m, err := parseMovement(p)
+					out.MaybeDieErr(err)
+					moves = append(moves, m)
+				}
+			default:
+				out.Die("must specify at least one --partition, or --plan-file")
+			}
+			err = checkDuplicateMovements(moves)
+			out.MaybeDieErr(err)
+			if wait && pollInterval < time.Second {
+				out.Die("--poll-interval must be at least 1s")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := admin.NewClient(fs, cfg)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			brokers, err := cl.Brokers(cmd.Context())
+			out.MaybeDie(err, "unable to request brokers: %v", err)
+
+			tw := out.NewTable("partition", "current-replicas", "new-replicas", "error")
+			var moved []movement
+			for _, m := range moves {
+				var (
+					current admin.Partition
+					errStr  string
+				)
+				current, err = cl.GetPartition(cmd.Context(), m.ns, m.topic, m.partition)
+				if err == nil {
+					m.replicas, err = resolveReplicas(m, current.Replicas, brokers)
+				}
+				if err == nil {
+					err = cl.UpdatePartitionReplicas(cmd.Context(), m.ns, m.topic, m.partition, m.replicas)
+				}
+				if err != nil {
+					errStr = err.Error()
+				} else {
+					moved = append(moved, m)
+				}
+				tw.Print(m.name(), formatReplicas(current.Replicas), formatReplicas(m.replicas), errStr)
+			}
+			tw.Flush()
+
+			if wait && len(moved) > 0 {
+				fmt.Println()
+				waitMovements(cmd.Context(), cl, moved, pollInterval)
+			}
+			if len(moved) < len(moves) {
+				out.Die("unable to move %d of %d partitions", len(moves)-len(moved), len(moves))
+			}
+		},
+	}
+	cmd.Flags().StringArrayVarP(&partitions, "partition", "p", nil, "Partition to move and its new replicas ([NAMESPACE/]TOPIC/PARTITION:NODE[-CORE],...), repeatable")
+	cmd.Flags().StringVar(&planFile, "plan-file", "", "JSON file of partition movements")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Poll the movements until they complete")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 2*time.Second, "How often to poll movements with --wait")
+	return cmd
+}
+
+// movement is a requested partition movement. Nodes and cores are parallel;
+// a core of -1 means that the core was not specified.
+type movement struct {
+	ns        string
+	topic     string
+	partition int
+	nodes     []int
+	cores     []int
+
+	replicas []admin.Replica // resolved from nodes and cores
+}
+
+func (m movement) name() string {
+	return fmt.Sprintf("%s/%s/%d", m.ns, m.topic, m.partition)
+}
+
+// parseMovement parses [NAMESPACE/]TOPIC/PARTITION:NODE[-CORE],...
+func parseMovement(s string) (movement, error) {
+	m := movement{ns: "kafka"}
+	tp, replicas, ok := strings.Cut(s, ":")
+	if !ok || replicas == "" {
+		return m, fmt.Errorf("invalid partition %q: missing ':' and replicas", s)
+	}
+	parts := strings.Split(tp, "/")
+	switch len(parts) {
+	case 2:
+		m.topic = parts[0]
+	case 3:
+		m.ns, m.topic = parts[0], parts[1]
+	default:
+		return m, fmt.Errorf("invalid partition %q: expected [NAMESPACE/]TOPIC/PARTITION", s)
+	}
+	if m.ns == "" || m.topic == "" {
+		return m, fmt.Errorf("invalid partition %q: empty namespace or topic", s)
+	}
+	p, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || p < 0 {
+		return m, fmt.Errorf("invalid partition %q: partition must be a non-negative number", s)
+	}
+	m.partition = p
+
+	for _, r := range strings.Split(replicas, ",") {
+		node, core, hasCore := strings.Cut(r, "-")
+		n, err := strconv.Atoi(node)
+		if err != nil || n < 0 {
+			return m, fmt.Errorf("invalid replica %q in %q: node must be a non-negative number", r, s)
+		}
+		c := -1
+		if hasCore {
+			c, err = strconv.Atoi(core)
+			if err != nil || c < 0 {
+				return m, fmt.Errorf("invalid replica %q in %q: core must be a non-negative number", r, s)
+			}
+		}
+		m.nodes = append(m.nodes, n)
+		m.cores = append(m.cores, c)
+	}
+	return m, nil
+}
+
+// movementPlan is the JSON plan file format.
+type movementPlan struct {
+	Version    int `json:"version"`
+	Partitions []struct {
+		Namespace string `json:"ns"`
+		Topic     string `json:"topic"`
+		Partition *int   `json:"partition"`
+		Replicas  []int  `json:"replicas"`
+		Cores     []int  `json:"cores"`
+	} `json:"partitions"`
+}
+
+func parsePlan(raw []byte) ([]movement, error) {
+	var plan movementPlan
+	if err := json.Unmarshal(raw, &plan); err != nil {
+		return nil, fmt.Errorf("unable to decode plan: %v", err)
+	}
+	if plan.Version > 1 {
+		return nil, fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+	if len(plan.Partitions) == 0 {
+		return nil, errors.New("plan contains no partitions")
+	}
+	var moves []movement
+	for i, p := range plan.Partitions {
+		m := movement{ns: p.Namespace, topic: p.Topic, nodes: p.Replicas, cores: p.Cores}
+		if m.ns == "" {
+			m.ns = "kafka"
+		}
+		switch {
+		case m.topic == "":
+			return nil, fmt.Errorf("partition entry %d is missing a topic", i)
+		case p.Partition == nil || *p.Partition < 0:
+			return nil, fmt.Errorf("partition entry %d (topic %s) is missing a valid partition", i, m.topic)
+		case len(m.nodes) == 0:
+			return nil, fmt.Errorf("partition entry %d (topic %s) has no replicas", i, m.topic)
+		case len(m.cores) > 0 && len(m.cores) != len(m.nodes):
+			return nil, fmt.Errorf("partition entry %d (topic %s) has %d cores for %d replicas", i, m.topic, len(m.cores), len(m.nodes))
+		}
+		m.partition = *p.Partition
+		if len(m.cores) == 0 {
+			m.cores = make([]int, len(m.nodes))
+			for j := range m.cores {
+				m.cores[j] = -1
+			}
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+func checkDuplicateMovements(moves []movement) error {
+	seen := make(map[string]bool)
+	for _, m := range moves {
+		if seen[m.name()] {
+			return fmt.Errorf("partition %s is specified more than once", m.name())
+		}
+		seen[m.name()] = true
+	}
+	return nil
+}
+
+// resolveReplicas returns the replica assignment for a movement, validating
+// that every node exists and every core is valid. Replicas with no specified
+// core keep their current core if the node already hosts the partition, and
+// otherwise are assigned the partition number modulo the node's core count.
+func resolveReplicas(
+	m movement, current []admin.Replica, brokers []admin.Broker,
+) ([]admin.Replica, error) {
+	numCores := make(map[int]int)
+	for _, b := range brokers {
+		numCores[b.NodeID] = b.NumCores
+	}
+	currentCores := make(map[int]int)
+	for _, r := range current {
+		currentCores[r.NodeID] = r.Core
+	}
+
+	seen := make(map[int]bool)
+	var replicas []admin.Replica
+	for i, node := range m.nodes {
+		if seen[node] {
+			return nil, fmt.Errorf("node %d is specified more than once", node)
+		}
+		seen[node] = true
+		cores, exists := numCores[node]
+		if !exists {
+			return nil, fmt.Errorf("node %d does not exist", node)
+		}
+		core := m.cores[i]
+		switch {
+		case core >= 0:
+			if cores > 0 && core >= cores {
+				return nil, fmt.Errorf("core %d is invalid for node %d, which has %d cores", core, node, cores)
+			}
+		default:
+			if c, ok := currentCores[node]; ok {
+				core = c
+			} else if cores > 0 {
+				core = m.partition % cores
+			} else {
+				core = 0
+			}
+		}
+		replicas = append(replicas, admin.Replica{NodeID: node, Core: core})
+	}
+	return replicas, nil
+}
+
+func formatReplicas(rs []admin.Replica) string {
+	s := make([]string, 0, len(rs))
+	for _, r := range rs {
+		s = append(s, fmt.Sprintf("%d-%d", r.NodeID, r.Core))
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
+
+// sameReplicas returns whether the two sets of replicas are the same,
+// ignoring order.
+func sameReplicas(l, r []admin.Replica) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	sorted := func(rs []admin.Replica) []admin.Replica {
+		s := append([]admin.Replica(nil), rs...)
+		sort.Slice(s, func(i, j int) bool { return s[i].NodeID < s[j].NodeID })
+		return s
+	}
+	ls, rs := sorted(l), sorted(r)
+	for i := range ls {
+		if ls[i] != rs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// movementDone returns whether a partition has finished moving to the
+// movement's replicas.
+func movementDone(m movement, p admin.Partition) bool {
+	return p.Status == admin.PartitionStatusDone && sameReplicas(m.replicas, p.Replicas)
+}
+
+// waitMovements polls the movements until every movement is complete,
+// printing the progress of each.
+func waitMovements(
+	ctx context.Context, cl *admin.AdminAPI, moves []movement, interval time.Duration,
+) {
+	pending := moves
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// The reconfigurations endpoint reports the bytes moved so far,
+		// but is not supported by older versions; it is best effort.
+		progress := make(map[string]admin.ReconfigurationsResponse)
+		if rs, err := cl.Reconfigurations(ctx); err == nil {
+			for _, r := range rs {
+				progress[fmt.Sprintf("%s/%s/%d", r.Namespace, r.Topic, r.PartitionID)] = r
+			}
+		}
+
+		var still []movement
+		now := time.Now().Format("15:04:05")
+		for _, m := range pending {
+			p, err := cl.GetPartition(ctx, m.ns, m.topic, m.partition)
+			switch {
+			case err != nil:
+				fmt.Printf("%s  %s: unable to request status: %v\n", now, m.name(), err)
+				still = append(still, m)
+			case movementDone(m, p):
+				fmt.Printf("%s  %s: moved to %s\n", now, m.name(), formatReplicas(p.Replicas))
+			default:
+				status := p.Status
+				if r, ok := progress[m.name()]; ok && r.PartitionSize > 0 {
+					status = fmt.Sprintf("%s, %s of %s moved (%.0f%%)", status,
+						units.BytesSize(float64(r.BytesMoved)),
+						units.BytesSize(float64(r.PartitionSize)),
+						100*float64(r.BytesMoved)/float64(r.PartitionSize))
+				}
+				fmt.Printf("%s  %s: %s\n", now, m.name(), status)
+				still = append(still, m)
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			fmt.Println("All partition movements are complete.")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			out.Die("stopped waiting with %d movements still in progress", len(pending))
+		case <-ticker.C:
+		}
+	}
+}
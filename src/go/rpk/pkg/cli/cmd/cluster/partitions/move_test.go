@@ -0,0 +1,136 @@
+package partitions
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMovement(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		exp    movement
+		expErr bool
+	}{
+		{
+			in:  "foo/0:1,2,3",
+			exp: movement{ns: "kafka", topic: "foo", partition: 0, nodes: []int{1, 2, 3}, cores: []int{-1, -1, -1}},
+		},
+		{
+			in:  "redpanda/controller/0:1-0,2-3",
+			exp: movement{ns: "redpanda", topic: "controller", partition: 0, nodes: []int{1, 2}, cores: []int{0, 3}},
+		},
+		{in: "foo/0", expErr: true},
+		{in: "foo/0:", expErr: true},
+		{in: "foo:1,2", expErr: true},
+		{in: "foo/bar:1", expErr: true},
+		{in: "foo/0:a", expErr: true},
+		{in: "foo/0:1-", expErr: true},
+		{in: "foo/0:1--1", expErr: true},
+		{in: "a/b/c/0:1", expErr: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			m, err := parseMovement(test.in)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, m)
+		})
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	moves, err := parsePlan([]byte(`{"version":1,"partitions":[
+		{"topic":"foo","partition":0,"replicas":[1,2,3]},
+		{"ns":"redpanda","topic":"bar","partition":1,"replicas":[2,3],"cores":[0,1]}
+	]}`))
+	require.NoError(t, err)
+	require.Equal(t, []movement{
+		{ns: "kafka", topic: "foo", partition: 0, nodes: []int{1, 2, 3}, cores: []int{-1, -1, -1}},
+		{ns: "redpanda", topic: "bar", partition: 1, nodes: []int{2, 3}, cores: []int{0, 1}},
+	}, moves)
+
+	for _, bad := range []string{
+		`{`,
+		`{"version":2,"partitions":[{"topic":"foo","partition":0,"replicas":[1]}]}`,
+		`{"version":1,"partitions":[]}`,
+		`{"partitions":[{"partition":0,"replicas":[1]}]}`,
+		`{"partitions":[{"topic":"foo","replicas":[1]}]}`,
+		`{"partitions":[{"topic":"foo","partition":0}]}`,
+		`{"partitions":[{"topic":"foo","partition":0,"replicas":[1,2],"cores":[0]}]}`,
+	} {
+		_, err := parsePlan([]byte(bad))
+		require.Error(t, err, "plan %s", bad)
+	}
+}
+
+func TestResolveReplicas(t *testing.T) {
+	brokers := []admin.Broker{
+		{NodeID: 1, NumCores: 2},
+		{NodeID: 2, NumCores: 4},
+		{NodeID: 3, NumCores: 4},
+	}
+	current := []admin.Replica{{NodeID: 1, Core: 1}, {NodeID: 2, Core: 0}}
+
+	for _, test := range []struct {
+		name   string
+		m      movement
+		exp    []admin.Replica
+		expErr bool
+	}{
+		{
+			name: "keep current cores, assign new",
+			m:    movement{partition: 5, nodes: []int{1, 3}, cores: []int{-1, -1}},
+			exp:  []admin.Replica{{NodeID: 1, Core: 1}, {NodeID: 3, Core: 1}},
+		},
+		{
+			name: "explicit cores",
+			m:    movement{partition: 5, nodes: []int{1, 2}, cores: []int{0, 3}},
+			exp:  []admin.Replica{{NodeID: 1, Core: 0}, {NodeID: 2, Core: 3}},
+		},
+		{
+			name:   "unknown node",
+			m:      movement{nodes: []int{4}, cores: []int{-1}},
+			expErr: true,
+		},
+		{
+			name:   "core out of range",
+			m:      movement{nodes: []int{1}, cores: []int{2}},
+			expErr: true,
+		},
+		{
+			name:   "duplicate node",
+			m:      movement{nodes: []int{1, 1}, cores: []int{-1, 0}},
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rs, err := resolveReplicas(test.m, current, brokers)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, rs)
+		})
+	}
+}
+
+func TestMovementDone(t *testing.T) {
+	m := movement{replicas: []admin.Replica{{NodeID: 1, Core: 0}, {NodeID: 2, Core: 1}}}
+	require.True(t, movementDone(m, admin.Partition{
+		Status:   admin.PartitionStatusDone,
+		Replicas: []admin.Replica{{NodeID: 2, Core: 1}, {NodeID: 1, Core: 0}},
+	}))
+	require.False(t, movementDone(m, admin.Partition{
+		Status:   "in_progress",
+		Replicas: []admin.Replica{{NodeID: 1, Core: 0}, {NodeID: 2, Core: 1}},
+	}))
+	require.False(t, movementDone(m, admin.Partition{
+		Status:   admin.PartitionStatusDone,
+		Replicas: []admin.Replica{{NodeID: 1, Core: 0}, {NodeID: 3, Core: 1}},
+	}))
+}
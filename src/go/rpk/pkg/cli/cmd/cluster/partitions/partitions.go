@@ -31,6 +31,8 @@ func NewPartitionsCommand(fs afero.Fs) *cobra.Command {
 
 	cmd.AddCommand(
 		newBalancerStatusCommand(fs),
+		newListCommand(fs),
+		newMoveCommand(fs),
 		newMovementCancelCommand(fs),
 	)
 
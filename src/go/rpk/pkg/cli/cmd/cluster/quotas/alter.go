@@ -0,0 +1,161 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package quotas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newAlterCommand(fs afero.Fs) *cobra.Command {
+	var (
+		names    []string
+		defaults []string
+		adds     []string
+		deletes  []string
+		dry      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "alter",
+		Args:  cobra.ExactArgs(0),
+		Short: "Add or delete client quotas of an entity",
+		Long: `Add or delete client quotas of an entity.
+
+This command sets quotas with --add key=value and deletes quotas with
+--delete key on a single entity, which is specified with --name type=name and
+--default type. See 'rpk cluster quotas --help' for more information about
+entities.
+
+With --dry, the change is validated by the cluster, but not applied.
+
+EXAMPLES
+
+Limit the client ID "foo" to producing 1MiB/s:
+    rpk cluster quotas alter --name client-id=foo --add producer_byte_rate=1048576
+
+Limit any user without its own quota to 10% request time:
+    rpk cluster quotas alter --default user --add request_percentage=10
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			e, err := parseEntity(names, defaults)
+			out.MaybeDieErr(err)
+			ops, err := parseOps(adds, deletes)
+			out.MaybeDieErr(err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			err = alterQuotas(cmd.Context(), cl, e, ops, dry)
+			out.MaybeDie(err, "unable to alter quotas of %s: %v", e, err)
+			if dry {
+				fmt.Printf("Dry run: the quotas of %s are valid, no changes were made.\n", e)
+				return
+			}
+			fmt.Printf("Altered the quotas of %s.\n", e)
+		},
+	}
+	cmd.Flags().StringSliceVar(&names, "name", nil, "Named entity of a type, type=name (repeatable)")
+	cmd.Flags().StringSliceVar(&defaults, "default", nil, "Default entity of a type (repeatable)")
+	cmd.Flags().StringSliceVar(&adds, "add", nil, "Quota to set, key=value (repeatable)")
+	cmd.Flags().StringSliceVar(&deletes, "delete", nil, "Quota to delete (repeatable)")
+	cmd.Flags().BoolVar(&dry, "dry", false, "Dry run: validate the change, but do not apply it")
+	return cmd
+}
+
+// parseOps parses --add key=value and --delete key flags into quota
+// operations; a key can only be used once.
+func parseOps(adds, deletes []string) ([]kmsg.AlterClientQuotasRequestEntryOp, error) {
+	if len(adds) == 0 && len(deletes) == 0 {
+		return nil, errors.New("no quotas to add or delete, use --add or --delete")
+	}
+	var ops []kmsg.AlterClientQuotasRequestEntryOp
+	seen := make(map[string]bool)
+	add := func(op kmsg.AlterClientQuotasRequestEntryOp) error {
+		if seen[op.Key] {
+			return fmt.Errorf("quota %q is specified more than once", op.Key)
+		}
+		seen[op.Key] = true
+		ops = append(ops, op)
+		return nil
+	}
+	for _, kv := range adds {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid --add %q, must be key=value", kv)
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of quota %q: must be a number", v, k)
+		}
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = k
+		op.Value = f
+		if err := add(op); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range deletes {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = k
+		op.Remove = true
+		if err := add(op); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+// alterQuotas applies (or with dry, validates) the operations on the entity.
+func alterQuotas(
+	ctx context.Context, cl *kgo.Client, e entity, ops []kmsg.AlterClientQuotasRequestEntryOp, dry bool,
+) error {
+	if len(e) == 0 {
+		return errors.New("missing entity, use --name or --default")
+	}
+	req := kmsg.NewPtrAlterClientQuotasRequest()
+	entry := kmsg.NewAlterClientQuotasRequestEntry()
+	entry.Entity = e.alterEntity()
+	entry.Ops = ops
+	req.Entries = append(req.Entries, entry)
+	req.ValidateOnly = dry
+
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return err
+	}
+	if len(resp.Entries) != 1 {
+		return fmt.Errorf("response returned %d entries when we altered 1", len(resp.Entries))
+	}
+	r := resp.Entries[0]
+	if err := kerr.ErrorForCode(r.ErrorCode); err != nil {
+		if r.ErrorMessage != nil {
+			return fmt.Errorf("%v: %s", err, *r.ErrorMessage)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package quotas
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newDeleteCommand(fs afero.Fs) *cobra.Command {
+	var (
+		names    []string
+		defaults []string
+		dry      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Args:  cobra.ExactArgs(0),
+		Short: "Delete all client quotas of an entity",
+		Long: `Delete all client quotas of an entity.
+
+This command deletes every quota of a single entity, which is specified with
+--name type=name and --default type. To delete only some quotas, use
+'rpk cluster quotas alter --delete'.
+
+With --dry, the deletion is validated by the cluster, but not applied.
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			e, err := parseEntity(names, defaults)
+			out.MaybeDieErr(err)
+			if len(e) == 0 {
+				out.Die("missing entity, use --name or --default")
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			req := kmsg.NewPtrDescribeClientQuotasRequest()
+			req.Components = e.describeComponents()
+			req.Strict = true
+			quotas, err := describeQuotas(cmd.Context(), cl, req)
+			out.MaybeDie(err, "unable to describe quotas of %s: %v", e, err)
+			ops := removeOps(quotas)
+			if len(ops) == 0 {
+				out.Exit("%s has no quotas.", e)
+			}
+
+			err = alterQuotas(cmd.Context(), cl, e, ops, dry)
+			out.MaybeDie(err, "unable to delete quotas of %s: %v", e, err)
+
+			tw := out.NewTable("entity", "key", "status")
+			for _, op := range ops {
+				status := "deleted"
+				if dry {
+					status = "would delete"
+				}
+				tw.Print(e, op.Key, status)
+			}
+			tw.Flush()
+			if dry {
+				fmt.Println("\nDry run, no changes were made.")
+			}
+		},
+	}
+	cmd.Flags().StringSliceVar(&names, "name", nil, "Named entity of a type, type=name (repeatable)")
+	cmd.Flags().StringSliceVar(&defaults, "default", nil, "Default entity of a type (repeatable)")
+	cmd.Flags().BoolVar(&dry, "dry", false, "Dry run: validate the deletion, but do not apply it")
+	return cmd
+}
+
+// removeOps returns operations removing every quota value.
+func removeOps(quotas []quota) []kmsg.AlterClientQuotasRequestEntryOp {
+	var ops []kmsg.AlterClientQuotasRequestEntryOp
+	for _, q := range quotas {
+		for _, v := range q.Values {
+			op := kmsg.NewAlterClientQuotasRequestEntryOp()
+			op.Key = v.Key
+			op.Remove = true
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// quota is an entity and its quota values, in structured output.
+type quota struct {
+	Entity entity       `json:"entity"`
+	Values []quotaValue `json:"values"`
+}
+
+type quotaValue struct {
+	Key   string  `json:"key"`
+	Value float64 `json:"value"`
+}
+
+func newDescribeCommand(fs afero.Fs) *cobra.Command {
+	var (
+		names    []string
+		defaults []string
+		anys     []string
+		strict   bool
+		format   out.Format
+	)
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Args:  cobra.ExactArgs(0),
+		Short: "Describe client quotas",
+		Long: `Describe client quotas.
+
+Without flags, this command describes every quota in the cluster. Otherwise,
+quotas are filtered with --name type=name, which matches the named entity,
+--default type, which matches the default entity of the type, and --any type,
+which matches any named or default entity of the type. Filters of different
+types are combined: --name user=alice --any client-id matches the quotas of
+"alice" with any client ID.
+
+By default, the filters also match entities that have more types than were
+filtered for: --name user=alice matches the quotas of "alice" with or without
+a client ID. Use --strict to only match entities with exactly the filtered
+types.
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			req, err := describeRequest(names, defaults, anys, strict)
+			out.MaybeDieErr(err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			quotas, err := describeQuotas(cmd.Context(), cl, req)
+			out.MaybeDie(err, "unable to describe quotas: %v", err)
+
+			format.Print(struct {
+				Quotas []quota `json:"quotas"`
+			}{quotas}, func() {
+				if len(quotas) == 0 {
+					fmt.Println("No quotas match.")
+					return
+				}
+				tw := out.NewTable("entity", "key", "value")
+				defer tw.Flush()
+				for _, q := range quotas {
+					for _, v := range q.Values {
+						tw.Print(q.Entity, v.Key, formatValue(v.Value))
+					}
+				}
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&names, "name", nil, "Match the named entity of a type, type=name (repeatable)")
+	cmd.Flags().StringSliceVar(&defaults, "default", nil, "Match the default entity of a type (repeatable)")
+	cmd.Flags().StringSliceVar(&anys, "any", nil, "Match any named or default entity of a type (repeatable)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Only match entities that have exactly the filtered types")
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+func describeRequest(names, defaults, anys []string, strict bool) (*kmsg.DescribeClientQuotasRequest, error) {
+	e, err := parseEntity(names, defaults)
+	if err != nil {
+		return nil, err
+	}
+	req := kmsg.NewPtrDescribeClientQuotasRequest()
+	req.Components = e.describeComponents()
+	for _, typ := range anys {
+		if err := checkEntityType(typ); err != nil {
+			return nil, err
+		}
+		for _, c := range e {
+			if c.Type == typ {
+				return nil, fmt.Errorf("entity type %q is specified more than once", typ)
+			}
+		}
+		c := kmsg.NewDescribeClientQuotasRequestComponent()
+		c.EntityType = typ
+		c.MatchType = kmsg.QuotasMatchTypeAny
+		req.Components = append(req.Components, c)
+	}
+	req.Strict = strict
+	return req, nil
+}
+
+// describeQuotas issues the request, returning the matched quotas sorted by
+// entity and key.
+func describeQuotas(ctx context.Context, cl *kgo.Client, req *kmsg.DescribeClientQuotasRequest) ([]quota, error) {
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		if resp.ErrorMessage != nil {
+			return nil, fmt.Errorf("%v: %s", err, *resp.ErrorMessage)
+		}
+		return nil, err
+	}
+	return responseQuotas(resp), nil
+}
+
+func responseQuotas(resp *kmsg.DescribeClientQuotasResponse) []quota {
+	quotas := []quota{}
+	for _, e := range resp.Entries {
+		var q quota
+		for _, c := range e.Entity {
+			ec := entityComponent{Type: c.Type, Default: c.Name == nil}
+			if c.Name != nil {
+				ec.Name = *c.Name
+			}
+			q.Entity = append(q.Entity, ec)
+		}
+		sortEntity(q.Entity)
+		for _, v := range e.Values {
+			q.Values = append(q.Values, quotaValue{v.Key, v.Value})
+		}
+		sort.Slice(q.Values, func(i, j int) bool { return q.Values[i].Key < q.Values[j].Key })
+		quotas = append(quotas, q)
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Entity.String() < quotas[j].Entity.String() })
+	return quotas
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package quotas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The entity types that quotas can be set on.
+const (
+	entityClientID = "client-id"
+	entityUser     = "user"
+)
+
+func NewQuotasCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quotas",
+		Args:  cobra.ExactArgs(0),
+		Short: "Manage client quotas",
+		Long: `Manage client quotas.
+
+Client quotas limit the rate at which clients can produce and fetch, and the
+rate at which they can issue requests. Quotas are set on entities: a client ID,
+a user, or a user and client ID together. An entity can also be the default
+for its type, which applies to any client ID or user that has no quota of its
+own.
+
+Entities are specified with --name and --default:
+
+    --name client-id=foo                   the client ID "foo"
+    --name user=alice --name client-id=foo the user "alice" using client ID "foo"
+    --default client-id                    the default client ID
+    --name user=alice --default client-id  the default client ID of "alice"
+
+The quota keys are producer_byte_rate, consumer_byte_rate, request_percentage,
+and controller_mutation_rate.
+`,
+	}
+	cmd.AddCommand(
+		newAlterCommand(fs),
+		newDeleteCommand(fs),
+		newDescribeCommand(fs),
+	)
+	return cmd
+}
+
+// entityComponent is a component of a quota entity. A component without a
+// name is the default entity of its type.
+type entityComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name,omitempty"`
+	Default bool   `json:"default,omitempty"`
+}
+
+func (c entityComponent) String() string {
+	if c.Default {
+		return c.Type + "=<default>"
+	}
+	return c.Type + "=" + c.Name
+}
+
+type entity []entityComponent
+
+func (e entity) String() string {
+	s := make([]string, 0, len(e))
+	for _, c := range e {
+		s = append(s, c.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// sortEntity sorts the components of an entity by type, such that the
+// user component comes before the client ID component.
+func sortEntity(e entity) {
+	sort.Slice(e, func(i, j int) bool { return e[i].Type > e[j].Type })
+}
+
+func checkEntityType(typ string) error {
+	switch typ {
+	case entityClientID, entityUser:
+		return nil
+	default:
+		return fmt.Errorf("unsupported entity type %q, must be %s or %s", typ, entityClientID, entityUser)
+	}
+}
+
+// parseEntity parses --name type=name and --default type flags into an
+// entity, which must have at most one component per type.
+func parseEntity(names, defaults []string) (entity, error) {
+	var e entity
+	seen := make(map[string]bool)
+	add := func(c entityComponent) error {
+		if err := checkEntityType(c.Type); err != nil {
+			return err
+		}
+		if seen[c.Type] {
+			return fmt.Errorf("entity type %q is specified more than once", c.Type)
+		}
+		seen[c.Type] = true
+		e = append(e, c)
+		return nil
+	}
+	for _, n := range names {
+		typ, name, ok := strings.Cut(n, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --name %q, must be type=name", n)
+		}
+		if err := add(entityComponent{Type: typ, Name: name}); err != nil {
+			return nil, err
+		}
+	}
+	for _, typ := range defaults {
+		if err := add(entityComponent{Type: typ, Default: true}); err != nil {
+			return nil, err
+		}
+	}
+	sortEntity(e)
+	return e, nil
+}
+
+func (e entity) alterEntity() []kmsg.AlterClientQuotasRequestEntryEntity {
+	var ke []kmsg.AlterClientQuotasRequestEntryEntity
+	for _, c := range e {
+		k := kmsg.NewAlterClientQuotasRequestEntryEntity()
+		k.Type = c.Type
+		if !c.Default {
+			k.Name = kmsg.StringPtr(c.Name)
+		}
+		ke = append(ke, k)
+	}
+	return ke
+}
+
+// describeComponents returns strict components matching exactly the entity.
+func (e entity) describeComponents() []kmsg.DescribeClientQuotasRequestComponent {
+	var cs []kmsg.DescribeClientQuotasRequestComponent
+	for _, c := range e {
+		k := kmsg.NewDescribeClientQuotasRequestComponent()
+		k.EntityType = c.Type
+		if c.Default {
+			k.MatchType = kmsg.QuotasMatchTypeDefault
+		} else {
+			k.MatchType = kmsg.QuotasMatchTypeExact
+			k.Match = kmsg.StringPtr(c.Name)
+		}
+		cs = append(cs, k)
+	}
+	return cs
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,105 @@
+package quotas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestParseEntity(t *testing.T) {
+	e, err := parseEntity([]string{"client-id=foo"}, []string{"user"})
+	require.NoError(t, err)
+	require.Equal(t, entity{
+		{Type: "user", Default: true},
+		{Type: "client-id", Name: "foo"},
+	}, e)
+	require.Equal(t, "user=<default>, client-id=foo", e.String())
+
+	alter := e.alterEntity()
+	require.Nil(t, alter[0].Name)
+	require.Equal(t, "foo", *alter[1].Name)
+
+	describe := e.describeComponents()
+	require.Equal(t, kmsg.QuotasMatchTypeDefault, describe[0].MatchType)
+	require.Equal(t, kmsg.QuotasMatchTypeExact, describe[1].MatchType)
+	require.Equal(t, "foo", *describe[1].Match)
+
+	for _, bad := range []struct {
+		names, defaults []string
+	}{
+		{names: []string{"client-id"}},
+		{names: []string{"client-id="}},
+		{names: []string{"ip=127.0.0.1"}},
+		{names: []string{"user=a"}, defaults: []string{"user"}},
+	} {
+		_, err := parseEntity(bad.names, bad.defaults)
+		require.Error(t, err, "names %v defaults %v", bad.names, bad.defaults)
+	}
+}
+
+func TestDescribeRequest(t *testing.T) {
+	req, err := describeRequest([]string{"user=alice"}, nil, []string{"client-id"}, true)
+	require.NoError(t, err)
+	require.True(t, req.Strict)
+	require.Len(t, req.Components, 2)
+	require.Equal(t, kmsg.QuotasMatchTypeAny, req.Components[1].MatchType)
+
+	_, err = describeRequest([]string{"user=alice"}, nil, []string{"user"}, false)
+	require.Error(t, err)
+}
+
+func TestParseOps(t *testing.T) {
+	ops, err := parseOps([]string{"producer_byte_rate=1048576"}, []string{"consumer_byte_rate"})
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, "producer_byte_rate", ops[0].Key)
+	require.Equal(t, 1048576.0, ops[0].Value)
+	require.False(t, ops[0].Remove)
+	require.True(t, ops[1].Remove)
+
+	for _, bad := range [][2][]string{
+		{nil, nil},
+		{{"producer_byte_rate"}, nil},
+		{{"producer_byte_rate=fast"}, nil},
+		{{"producer_byte_rate=1"}, {"producer_byte_rate"}},
+	} {
+		_, err := parseOps(bad[0], bad[1])
+		require.Error(t, err, "adds %v deletes %v", bad[0], bad[1])
+	}
+}
+
+func TestResponseQuotas(t *testing.T) {
+	name := "foo"
+	resp := &kmsg.DescribeClientQuotasResponse{Entries: []kmsg.DescribeClientQuotasResponseEntry{
+		{
+			Entity: []kmsg.DescribeClientQuotasResponseEntryEntity{{Type: "user"}},
+			Values: []kmsg.DescribeClientQuotasResponseEntryValue{{Key: "request_percentage", Value: 10}},
+		},
+		{
+			Entity: []kmsg.DescribeClientQuotasResponseEntryEntity{{Type: "client-id", Name: &name}},
+			Values: []kmsg.DescribeClientQuotasResponseEntryValue{
+				{Key: "producer_byte_rate", Value: 1024},
+				{Key: "consumer_byte_rate", Value: 2048.5},
+			},
+		},
+	}}
+	quotas := responseQuotas(resp)
+	require.Equal(t, []quota{
+		{
+			Entity: entity{{Type: "client-id", Name: "foo"}},
+			Values: []quotaValue{{"consumer_byte_rate", 2048.5}, {"producer_byte_rate", 1024}},
+		},
+		{
+			Entity: entity{{Type: "user", Default: true}},
+			Values: []quotaValue{{"request_percentage", 10}},
+		},
+	}, quotas)
+	require.Equal(t, "2048.5", formatValue(quotas[0].Values[0].Value))
+
+	ops := removeOps(quotas)
+	require.Len(t, ops, 3)
+	for _, op := range ops {
+		require.True(t, op.Remove)
+	}
+}
@@ -11,6 +11,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/spf13/cobra"
@@ -70,7 +71,27 @@ func AddKafkaFlags(
 		saslMechanism,
 		config.FlagSASLMechanism,
 		"",
-		"The authentication mechanism to use. Supported values: SCRAM-SHA-256, SCRAM-SHA-512",
+		"The authentication mechanism to use. Supported values: "+strings.Join(config.SASLMechanisms(), ", "),
+	)
+	command.PersistentFlags().String(
+		config.FlagSASLToken,
+		"",
+		"OAUTHBEARER token to be used for authentication",
+	)
+	command.PersistentFlags().String(
+		config.FlagSASLTokenFile,
+		"",
+		"File containing the OAUTHBEARER token to be used for authentication, re-read on every authentication",
+	)
+	command.PersistentFlags().String(
+		config.FlagSASLTokenURL,
+		"",
+		"OAuth token endpoint to request OAUTHBEARER tokens from using the client credentials grant, with --user and --password as the client ID and secret",
+	)
+	command.PersistentFlags().String(
+		config.FlagSASLTokenScope,
+		"",
+		"Optional scope to request from the OAuth token endpoint",
 	)
 
 	AddTLSFlags(command, enableTLS, certFile, keyFile, truststoreFile)
@@ -143,3 +164,36 @@ func AddAdminAPITLSFlags(
 
 	return command
 }
+
+func AddSchemaRegistryTLSFlags(
+	command *cobra.Command,
+	enableTLS *bool,
+	certFile, keyFile, truststoreFile *string,
+) *cobra.Command {
+	command.PersistentFlags().BoolVar(
+		enableTLS,
+		config.FlagEnableRegistryTLS,
+		false,
+		"Enable TLS for the Schema Registry API (not necessary if specifying custom certs)",
+	)
+	command.PersistentFlags().StringVar(
+		certFile,
+		config.FlagRegistryTLSCert,
+		"",
+		"The certificate to be used for TLS authentication with the Schema Registry API",
+	)
+	command.PersistentFlags().StringVar(
+		keyFile,
+		config.FlagRegistryTLSKey,
+		"",
+		"The certificate key to be used for TLS authentication with the Schema Registry API",
+	)
+	command.PersistentFlags().StringVar(
+		truststoreFile,
+		config.FlagRegistryTLSCA,
+		"",
+		"The truststore to be used for TLS communication with the Schema Registry API",
+	)
+
+	return command
+}
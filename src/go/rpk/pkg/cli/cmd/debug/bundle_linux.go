@@ -384,6 +384,9 @@ func saveConfig(ps *stepParams, conf *config.Config) step {
 		if conf.Rpk.KafkaAPI.SASL != nil {
 			conf.Rpk.KafkaAPI.SASL.User = redacted
 			conf.Rpk.KafkaAPI.SASL.Password = redacted
+			if conf.Rpk.KafkaAPI.SASL.Token != "" {
+				conf.Rpk.KafkaAPI.SASL.Token = redacted
+			}
 		}
 		if conf.Rpk.SASL != nil {
 			conf.Rpk.SASL.User = redacted
@@ -11,7 +11,9 @@ package group
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"time"
 
@@ -24,7 +26,13 @@ import (
 )
 
 func NewDescribeCommand(fs afero.Fs) *cobra.Command {
-	var summary bool
+	var (
+		summary          bool
+		watch            bool
+		interval         time.Duration
+		prometheusListen string
+		format           out.Format
+	)
 
 	cmd := &cobra.Command{
 		Use:   "describe [GROUPS...]",
@@ -32,9 +40,45 @@ func NewDescribeCommand(fs afero.Fs) *cobra.Command {
 		Long: `Describe group offset status & lag.
 
 This command describes group members, calculates their lag, and prints detailed
-information about the members.
+information about the members. With --format json or yaml, the groups are
+printed as a list of objects.
+
+WATCH
+
+With --watch, this command polls the groups every --interval and prints each
+partition's lag along with how it changed since the previous poll:
+
+    LAG-DELTA      how much the lag grew (positive) or shrank (negative)
+    COMMIT-RATE    committed records per second
+    PRODUCE-RATE   produced records per second
+    CATCH-UP       estimated time until the lag reaches zero at the current
+                   rates; "-" if the group is not catching up
+
+Rates and estimates are printed from the second poll onward.
+
+PROMETHEUS
+
+With --prometheus-listen, this command serves group lag as Prometheus metrics
+on the given address at /metrics until interrupted. Offsets are fetched when
+metrics are scraped. If no groups are specified, every group is exported. The
+following gauges are exported:
+
+    rpk_group_lag{group,topic,partition}
+    rpk_group_committed_offset{group,topic,partition}
+    rpk_group_log_end_offset{group,topic,partition}
+    rpk_group_total_lag{group}
+    rpk_group_members{group}
+
+For example, to serve the lag of all groups on port 9308:
+
+    rpk group describe --prometheus-listen :9308
 `,
-		Args: cobra.MinimumNArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if prometheusListen != "" {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, groups []string) {
 			p := config.ParamsFromCommand(cmd)
 			cfg, err := p.Load(fs)
@@ -44,45 +88,183 @@ information about the members.
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 			defer adm.Close()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			described, err := adm.DescribeGroups(ctx, groups...)
-			out.HandleShardError("DescribeGroups", err)
+			if !format.IsText() && (watch || prometheusListen != "") {
+				out.Die("--format cannot be used with --watch or --prometheus-listen")
+			}
+			// Errors are printed to stderr in structured output to
+			// keep stdout parseable.
+			onErr := func(err error) { fmt.Println(err) }
+			if !format.IsText() {
+				onErr = func(err error) { fmt.Fprintln(os.Stderr, err) }
+			}
 
-			if summary {
-				printDescribedSummary(described)
+			switch {
+			case prometheusListen != "":
+				err := serveLagMetrics(adm, prometheusListen, groups)
+				out.MaybeDie(err, "unable to serve metrics: %v", err)
+				return
+			case watch:
+				if interval < time.Second {
+					out.Die("invalid --interval less than 1s")
+				}
+				watchLag(adm, groups, interval)
 				return
 			}
 
-			fetched := adm.FetchManyOffsets(ctx, groups...)
-			fetched.EachError(func(r kadm.FetchOffsetsResponse) {
-				fmt.Printf("unable to fetch offsets for group %q: %v\n", r.Group, r.Err)
-				delete(fetched, r.Group)
-			})
-			if fetched.AllFailed() {
-				out.Die("unable to fetch offsets for any group")
-			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
 
-			var listed kadm.ListedOffsets
-			listPartitions := described.AssignedPartitions()
-			listPartitions.Merge(fetched.CommittedPartitions())
-			if topics := listPartitions.Topics(); len(topics) > 0 {
-				listed, err = adm.ListEndOffsets(ctx, topics...)
-				out.HandleShardError("ListOffsets", err)
+			if summary {
+				described, err := adm.DescribeGroups(ctx, groups...)
+				out.HandleShardError("DescribeGroups", err)
+				structured := []describedGroup{}
+				for _, g := range described.Sorted() {
+					structured = append(structured, newDescribedGroup(g))
+				}
+				format.Print(structured, func() { printDescribedSummary(described) })
+				return
 			}
 
-			printDescribed(
-				described,
-				fetched,
-				listed,
-			)
+			lags, err := describeLag(ctx, adm, groups, onErr)
+			out.MaybeDieErr(err)
+			format.Print(describedLags(lags), func() { printDescribed(lags) })
 		},
 	}
 	cmd.Flags().BoolVarP(&summary, "print-summary", "s", false, "Print only the group summary section")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Poll lag every --interval and print lag deltas and catch up estimates")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Poll interval for --watch")
+	cmd.Flags().StringVar(&prometheusListen, "prometheus-listen", "", "Serve group lag as Prometheus metrics on this address (e.g. :9308)")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
 
+// describedLag is a described group and its lag.
+type describedLag struct {
+	group kadm.DescribedGroup
+	lag   kadm.GroupLag
+}
+
+// describeLag describes the groups and calculates their lag, sorted by group.
+// Groups whose offsets cannot be fetched are reported to onErr and skipped;
+// this returns an error if no group could be described.
+func describeLag(
+	ctx context.Context, adm *kadm.Client, groups []string, onErr func(error),
+) ([]describedLag, error) {
+	described, err := adm.DescribeGroups(ctx, groups...)
+	if err != nil {
+		var se *kadm.ShardErrors
+		if !errors.As(err, &se) || se.AllFailed {
+			return nil, fmt.Errorf("unable to describe groups: %v", err)
+		}
+		onErr(fmt.Errorf("partial DescribeGroups failure: %v", err))
+	}
+
+	fetched := adm.FetchManyOffsets(ctx, groups...)
+	fetched.EachError(func(r kadm.FetchOffsetsResponse) {
+		onErr(fmt.Errorf("unable to fetch offsets for group %q: %v", r.Group, r.Err))
+		delete(fetched, r.Group)
+	})
+	if fetched.AllFailed() {
+		return nil, errors.New("unable to fetch offsets for any group")
+	}
+
+	var listed kadm.ListedOffsets
+	listPartitions := described.AssignedPartitions()
+	listPartitions.Merge(fetched.CommittedPartitions())
+	if topics := listPartitions.Topics(); len(topics) > 0 {
+		listed, err = adm.ListEndOffsets(ctx, topics...)
+		if err != nil {
+			var se *kadm.ShardErrors
+			if !errors.As(err, &se) || se.AllFailed {
+				return nil, fmt.Errorf("unable to list end offsets: %v", err)
+			}
+			onErr(fmt.Errorf("partial ListOffsets failure: %v", err))
+		}
+	}
+
+	var lags []describedLag
+	for _, group := range described.Sorted() {
+		lags = append(lags, describedLag{
+			group: group,
+			lag:   kadm.CalculateGroupLag(group, fetched[group.Group].Fetched, listed),
+		})
+	}
+	return lags, nil
+}
+
+// describedGroup is the structured output of a described group. Partitions
+// and the total lag are only included if the lag was described.
+type describedGroup struct {
+	Group       string                    `json:"group"`
+	Coordinator int32                     `json:"coordinator"`
+	State       string                    `json:"state"`
+	Balancer    string                    `json:"balancer"`
+	Members     int                       `json:"members"`
+	TotalLag    *int64                    `json:"total_lag,omitempty"`
+	Error       string                    `json:"error,omitempty"`
+	Partitions  []describedGroupPartition `json:"partitions,omitempty"`
+}
+
+// describedGroupPartition is the lag of a partition in the structured
+// output. The current offset is -1 if nothing is committed, and the lag is
+// -1 if it could not be calculated.
+type describedGroupPartition struct {
+	Topic         string  `json:"topic"`
+	Partition     int32   `json:"partition"`
+	CurrentOffset int64   `json:"current_offset"`
+	LogEndOffset  int64   `json:"log_end_offset"`
+	Lag           int64   `json:"lag"`
+	MemberID      string  `json:"member_id,omitempty"`
+	InstanceID    *string `json:"instance_id,omitempty"`
+	ClientID      string  `json:"client_id,omitempty"`
+	Host          string  `json:"host,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+func newDescribedGroup(g kadm.DescribedGroup) describedGroup {
+	d := describedGroup{
+		Group:       g.Group,
+		Coordinator: g.Coordinator.NodeID,
+		State:       g.State,
+		Balancer:    g.Protocol,
+		Members:     len(g.Members),
+	}
+	if g.Err != nil {
+		d.Error = g.Err.Error()
+	}
+	return d
+}
+
+func describedLags(lags []describedLag) []describedGroup {
+	groups := []describedGroup{}
+	for _, dl := range lags {
+		d := newDescribedGroup(dl.group)
+		total := dl.lag.Total()
+		d.TotalLag = &total
+		for _, l := range dl.lag.Sorted() {
+			p := describedGroupPartition{
+				Topic:         l.End.Topic,
+				Partition:     l.End.Partition,
+				CurrentOffset: l.Commit.At,
+				LogEndOffset:  l.End.Offset,
+				Lag:           l.Lag,
+			}
+			if !l.IsEmpty() {
+				p.MemberID = l.Member.MemberID
+				p.InstanceID = l.Member.InstanceID
+				p.ClientID = l.Member.ClientID
+				p.Host = l.Member.ClientHost
+			}
+			if l.Err != nil {
+				p.Error = l.Err.Error()
+			}
+			d.Partitions = append(d.Partitions, p)
+		}
+		groups = append(groups, d)
+	}
+	return groups
+}
+
 // Below here lies printing the output of everything we have done.
 //
 // There is not much logic; the main thing to note is that we use dashes when
@@ -102,17 +284,13 @@ type describeRow struct {
 	err           error
 }
 
-func printDescribed(
-	groups kadm.DescribedGroups,
-	fetched map[string]kadm.FetchOffsetsResponse,
-	listed kadm.ListedOffsets,
-) {
-	for _, group := range groups.Sorted() {
-		lag := kadm.CalculateGroupLag(group, fetched[group.Group].Fetched, listed)
+func printDescribed(lags []describedLag) {
+	for _, dl := range lags {
+		group := dl.group
 
 		var rows []describeRow
 		var useInstanceID, useErr bool
-		for _, l := range lag.Sorted() {
+		for _, l := range dl.lag.Sorted() {
 			row := describeRow{
 				topic:     l.End.Topic,
 				partition: l.End.Partition,
@@ -0,0 +1,82 @@
+package group
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestCalculateProgress(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	for _, test := range []struct {
+		name string
+		prev lagSample
+		cur  lagSample
+		exp  lagProgress
+	}{
+		{
+			name: "catching up",
+			prev: lagSample{at: t0, commit: 100, end: 200, lag: 100},
+			cur:  lagSample{at: t0.Add(10 * time.Second), commit: 150, end: 220, lag: 70},
+			// commit 5/s, produce 2/s, shrinking 3/s: 70/3 s.
+			exp: lagProgress{lagDelta: -30, commitRate: 5, produceRate: 2, catchUp: 70 * time.Second / 3},
+		},
+		{
+			name: "falling behind",
+			prev: lagSample{at: t0, commit: 100, end: 200, lag: 100},
+			cur:  lagSample{at: t0.Add(10 * time.Second), commit: 110, end: 300, lag: 190},
+			exp:  lagProgress{lagDelta: 90, commitRate: 1, produceRate: 10, catchUp: -1},
+		},
+		{
+			name: "caught up",
+			prev: lagSample{at: t0, commit: 200, end: 200, lag: 0},
+			cur:  lagSample{at: t0.Add(time.Second), commit: 200, end: 200, lag: 0},
+			exp:  lagProgress{catchUp: 0},
+		},
+		{
+			name: "first commit",
+			prev: lagSample{at: t0, commit: -1, end: 200, lag: 200},
+			cur:  lagSample{at: t0.Add(time.Second), commit: 50, end: 200, lag: 150},
+			exp:  lagProgress{lagDelta: -50, catchUp: -1},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := calculateProgress(test.prev, test.cur)
+			require.Equal(t, test.exp.lagDelta, got.lagDelta)
+			require.InDelta(t, test.exp.commitRate, got.commitRate, 0.001)
+			require.InDelta(t, test.exp.produceRate, got.produceRate, 0.001)
+			require.InDelta(t, float64(test.exp.catchUp), float64(got.catchUp), float64(time.Millisecond))
+		})
+	}
+}
+
+func TestWriteLagMetrics(t *testing.T) {
+	lags := []describedLag{{
+		group: kadm.DescribedGroup{Group: "g", Members: make([]kadm.DescribedGroupMember, 2)},
+		lag: kadm.GroupLag{"foo": {
+			0: {Commit: kadm.Offset{At: 5}, End: kadm.ListedOffset{Topic: "foo", Partition: 0, Offset: 10}, Lag: 5},
+			1: {Commit: kadm.Offset{At: -1}, End: kadm.ListedOffset{Topic: "foo", Partition: 1, Offset: 3}, Lag: 3},
+			2: {End: kadm.ListedOffset{Topic: "foo", Partition: 2}, Lag: -1, Err: errors.New("skipped")},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeLagMetrics(&buf, lags))
+	got := buf.String()
+	for _, exp := range []string{
+		"# TYPE rpk_group_lag gauge\n",
+		`rpk_group_lag{group="g",topic="foo",partition="0"} 5` + "\n",
+		`rpk_group_lag{group="g",topic="foo",partition="1"} 3` + "\n",
+		`rpk_group_committed_offset{group="g",topic="foo",partition="1"} -1` + "\n",
+		`rpk_group_log_end_offset{group="g",topic="foo",partition="0"} 10` + "\n",
+		`rpk_group_total_lag{group="g"} 8` + "\n",
+		`rpk_group_members{group="g"} 2` + "\n",
+	} {
+		require.Contains(t, got, exp)
+	}
+	require.NotContains(t, got, `partition="2"`)
+}
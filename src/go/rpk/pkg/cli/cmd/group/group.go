@@ -98,6 +98,7 @@ members and their lag), and manage offsets.
 		newDeleteCommand(fs),
 		NewDescribeCommand(fs),
 		newListCommand(fs),
+		newOffsetsCommand(fs),
 		newSeekCommand(fs),
 	)
 
@@ -105,7 +106,8 @@ members and their lag), and manage offsets.
 }
 
 func newListCommand(fs afero.Fs) *cobra.Command {
-	return &cobra.Command{
+	var format out.Format
+	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all groups",
@@ -115,6 +117,9 @@ This command lists all groups currently known to Redpanda, including empty
 groups that have not yet expired. The BROKER column is which broker node is the
 coordinator for the group. This command can be used to track down unknown
 groups, or to list groups that need to be cleaned up.
+
+With --format json or yaml, each group also includes its state and protocol
+type.
 `,
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, _ []string) {
@@ -129,16 +134,32 @@ groups, or to list groups that need to be cleaned up.
 			listed, err := adm.ListGroups(context.Background())
 			out.HandleShardError("ListGroups", err)
 
-			tw := out.NewTable("BROKER", "GROUP")
-			defer tw.Flush()
+			groups := []listedGroup{}
 			for _, g := range listed.Sorted() {
-				tw.PrintStructFields(struct {
-					Broker int32
-					Group  string
-				}{g.Coordinator, g.Group})
+				groups = append(groups, listedGroup{g.Coordinator, g.Group, g.State, g.ProtocolType})
 			}
+			format.Print(groups, func() {
+				tw := out.NewTable("BROKER", "GROUP")
+				defer tw.Flush()
+				for _, g := range groups {
+					tw.PrintStructFields(struct {
+						Broker int32
+						Group  string
+					}{g.Broker, g.Group})
+				}
+			})
 		},
 	}
+	out.AddFormatFlag(cmd, &format)
+	return cmd
+}
+
+// listedGroup is the structured output of the list command.
+type listedGroup struct {
+	Broker       int32  `json:"broker"`
+	Group        string `json:"group"`
+	State        string `json:"state,omitempty"`
+	ProtocolType string `json:"protocol_type,omitempty"`
 }
 
 func newDeleteCommand(fs afero.Fs) *cobra.Command {
@@ -0,0 +1,114 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// serveLagMetrics serves the lag of the groups, or of all groups if none are
+// specified, as Prometheus metrics on addr until the server fails.
+func serveLagMetrics(adm *kadm.Client, addr string, groups []string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		scrape := groups
+		if len(scrape) == 0 {
+			listed, err := adm.ListGroups(ctx)
+			if err != nil && len(listed) == 0 {
+				http.Error(w, fmt.Sprintf("unable to list groups: %v", err), http.StatusInternalServerError)
+				return
+			}
+			scrape = listed.Groups()
+			if len(scrape) == 0 {
+				w.Header().Set("Content-Type", string(expfmt.FmtText))
+				return
+			}
+		}
+		lags, err := describeLag(ctx, adm, scrape, func(err error) { log.Debug(err) })
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", string(expfmt.FmtText))
+		if err := writeLagMetrics(w, lags); err != nil {
+			log.Debugf("unable to write metrics: %v", err)
+		}
+	})
+	fmt.Printf("Serving group lag metrics on http://%s/metrics\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// writeLagMetrics writes the lag of the groups in the Prometheus text format.
+func writeLagMetrics(w io.Writer, lags []describedLag) error {
+	gauge := func(name, help string) *dto.MetricFamily {
+		return &dto.MetricFamily{
+			Name: &name,
+			Help: &help,
+			Type: dto.MetricType_GAUGE.Enum(),
+		}
+	}
+	var (
+		lag       = gauge("rpk_group_lag", "Number of records a group has not yet committed in a partition.")
+		committed = gauge("rpk_group_committed_offset", "Offset a group has committed in a partition, or -1 if nothing is committed.")
+		end       = gauge("rpk_group_log_end_offset", "Log end offset of a partition consumed by a group.")
+		total     = gauge("rpk_group_total_lag", "Total lag of a group across all partitions.")
+		members   = gauge("rpk_group_members", "Number of members in a group.")
+	)
+	add := func(mf *dto.MetricFamily, v float64, kvs ...string) {
+		m := &dto.Metric{Gauge: &dto.Gauge{Value: &v}}
+		for i := 0; i < len(kvs); i += 2 {
+			name, value := kvs[i], kvs[i+1]
+			m.Label = append(m.Label, &dto.LabelPair{Name: &name, Value: &value})
+		}
+		mf.Metric = append(mf.Metric, m)
+	}
+
+	for _, dl := range lags {
+		group := dl.group.Group
+		var sum int64
+		for _, l := range dl.lag.Sorted() {
+			if l.Err != nil {
+				continue
+			}
+			labels := []string{"group", group, "topic", l.End.Topic, "partition", strconv.Itoa(int(l.End.Partition))}
+			add(lag, float64(l.Lag), labels...)
+			add(committed, float64(l.Commit.At), labels...)
+			add(end, float64(l.End.Offset), labels...)
+			if l.Lag > 0 {
+				sum += l.Lag
+			}
+		}
+		add(total, float64(sum), "group", group)
+		add(members, float64(len(dl.group.Members)), "group", group)
+	}
+
+	for _, mf := range []*dto.MetricFamily{lag, committed, end, total, members} {
+		if len(mf.Metric) == 0 {
+			continue
+		}
+		if _, err := expfmt.MetricFamilyToText(w, mf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
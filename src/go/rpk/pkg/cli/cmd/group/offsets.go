@@ -0,0 +1,502 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func newOffsetsCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offsets",
+		Short: "Export, back up, and restore group offsets",
+		Long: `Export, back up, and restore group offsets.
+
+Every 'rpk group seek' backs up the group's prior offsets of every partition it
+changes before committing. If a seek was a mistake, 'rpk group offsets undo'
+restores the offsets from the most recent backup. Backups are stored in your
+user config directory (~/.config/rpk/group-offset-backups on Linux) and can be
+listed with 'rpk group offsets backups'.
+
+Exported offsets and backups can also be passed to 'rpk group seek --to-file'.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newOffsetsBackupsCommand(fs),
+		newOffsetsExportCommand(fs),
+		newOffsetsUndoCommand(fs),
+	)
+	return cmd
+}
+
+// offsetsFile is the JSON format of exported offsets and offset backups.
+type offsetsFile struct {
+	Group      string           `json:"group"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Offsets    []exportedOffset `json:"offsets"`
+}
+
+// exportedOffset is a committed offset. An offset of -1 means that nothing
+// was committed for the partition.
+type exportedOffset struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+
+	// Timestamp, if exported, is the timestamp in milliseconds of the
+	// record at the offset: the next record the group will consume.
+	Timestamp *int64 `json:"timestamp,omitempty"`
+}
+
+func newOffsetsFile(group string, offsets kadm.Offsets, timestamps map[string]map[int32]int64) offsetsFile {
+	f := offsetsFile{
+		Group:      group,
+		ExportedAt: time.Now().UTC(),
+		Offsets:    []exportedOffset{},
+	}
+	for _, o := range offsets.Sorted() {
+		e := exportedOffset{Topic: o.Topic, Partition: o.Partition, Offset: o.At}
+		if ts, ok := timestamps[o.Topic][o.Partition]; ok {
+			e.Timestamp = &ts
+		}
+		f.Offsets = append(f.Offsets, e)
+	}
+	return f
+}
+
+// offsets returns the file's offsets that can be committed (non-negative)
+// and the partitions with no prior commit (negative).
+func (f *offsetsFile) offsets() (commit kadm.Offsets, uncommitted kadm.TopicsSet) {
+	commit, uncommitted = make(kadm.Offsets), make(kadm.TopicsSet)
+	for _, o := range f.Offsets {
+		if o.Offset < 0 {
+			uncommitted.Add(o.Topic, o.Partition)
+			continue
+		}
+		commit.Add(kadm.Offset{Topic: o.Topic, Partition: o.Partition, At: o.Offset, LeaderEpoch: -1})
+	}
+	return commit, uncommitted
+}
+
+// writeText writes the offsets in the format understood by seek --to-file.
+// Timestamps, if any, are written as trailing comments.
+func (f *offsetsFile) writeText(w io.Writer) {
+	fmt.Fprintf(w, "# group %s exported at %s\n", f.Group, f.ExportedAt.Format(time.RFC3339))
+	for _, o := range f.Offsets {
+		if o.Offset < 0 {
+			fmt.Fprintf(w, "# %s %d has no committed offset\n", o.Topic, o.Partition)
+			continue
+		}
+		fmt.Fprintf(w, "%s %d %d", o.Topic, o.Partition, o.Offset)
+		if o.Timestamp != nil {
+			fmt.Fprintf(w, " # timestamp %d (%s)", *o.Timestamp, time.UnixMilli(*o.Timestamp).UTC().Format(time.RFC3339Nano))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func parseOffsetsFile(raw []byte) (*offsetsFile, error) {
+	var f offsetsFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("unable to decode offsets: %v", err)
+	}
+	return &f, nil
+}
+
+// isJSONOffsets returns whether the contents of a seek file are JSON exported
+// offsets rather than text.
+func isJSONOffsets(raw []byte) bool {
+	trimmed := strings.TrimSpace(string(raw))
+	return strings.HasPrefix(trimmed, "{")
+}
+
+func newOffsetsExportCommand(fs afero.Fs) *cobra.Command {
+	var (
+		format     string
+		outFile    string
+		topics     []string
+		timestamps bool
+	)
+	cmd := &cobra.Command{
+		Use:   "export [GROUP]",
+		Short: "Export the committed offsets of a group",
+		Long: `Export the committed offsets of a group.
+
+This writes the group's committed offsets to stdout, or to a file with
+--output. The text format is the same format that 'rpk group seek --to-file'
+reads, one "TOPIC PARTITION OFFSET" line per partition. The json format also
+includes the group and the time of the export, and can also be read by
+'rpk group seek --to-file'.
+
+With --with-timestamps, the timestamp of the record at each committed offset
+(the next record the group will consume) is looked up and exported. In the
+text format, timestamps are written as trailing comments. These timestamps can
+be used with 'rpk group seek --to' to seek a group to the same position in a
+different cluster. Partitions that the group has fully consumed have no
+timestamp.
+
+EXAMPLES
+
+Export group G's offsets as text:
+    rpk group offsets export G
+Export group G's offsets for topic foo as JSON to a file:
+    rpk group offsets export G --topics foo --format json -o g.json
+Later, restore the offsets:
+    rpk group seek G --to-file g.json
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			group := args[0]
+			if format != "text" && format != "json" {
+				out.Die("invalid --format %q, only text and json are supported", format)
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			tset := make(map[string]bool)
+			for _, topic := range topics {
+				tset[topic] = true
+			}
+			offsets := seekFetch(adm, group, tset)
+
+			var ts map[string]map[int32]int64
+			if timestamps {
+				ts, err = offsetTimestamps(fs, p, cfg, adm, offsets)
+				out.MaybeDie(err, "unable to look up timestamps: %v", err)
+			}
+			f := newOffsetsFile(group, offsets, ts)
+
+			w := io.Writer(os.Stdout)
+			if outFile != "" {
+				file, err := fs.OpenFile(outFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+				out.MaybeDie(err, "unable to open %q: %v", outFile, err)
+				defer file.Close()
+				w = file
+			}
+			if format == "json" {
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(f)
+				out.MaybeDie(err, "unable to write offsets: %v", err)
+				return
+			}
+			f.writeText(w)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().StringVarP(&outFile, "output", "o", "", "File to write to, rather than stdout")
+	cmd.Flags().StringSliceVar(&topics, "topics", nil, "Only export these topics, if any are specified")
+	cmd.Flags().BoolVar(&timestamps, "with-timestamps", false, "Include the timestamp of the record at each committed offset")
+	return cmd
+}
+
+// offsetTimestamps returns the timestamp of the record at each offset, for
+// every offset that is before the end of its partition.
+func offsetTimestamps(
+	fs afero.Fs, p *config.Params, cfg *config.Config, adm *kadm.Client, offsets kadm.Offsets,
+) (map[string]map[int32]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	topics := offsets.TopicsSet().Topics()
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	ends, err := adm.ListEndOffsets(ctx, topics...)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to list end offsets: %v", err)
+	}
+
+	consume := make(map[string]map[int32]kgo.Offset)
+	var want int
+	offsets.Each(func(o kadm.Offset) {
+		end, ok := ends.Lookup(o.Topic, o.Partition)
+		if !ok || o.At < 0 || o.At >= end.Offset {
+			return
+		}
+		if consume[o.Topic] == nil {
+			consume[o.Topic] = make(map[int32]kgo.Offset)
+		}
+		consume[o.Topic][o.Partition] = kgo.NewOffset().At(o.At)
+		want++
+	})
+	if want == 0 {
+		return nil, nil
+	}
+
+	cl, err := kafka.NewFranzClient(fs, p, cfg, kgo.ConsumePartitions(consume))
+	if err != nil {
+		return nil, err
+	}
+	defer cl.Close()
+
+	timestamps := make(map[string]map[int32]int64)
+	var got int
+	for got < want {
+		fetches := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after finding %d of %d timestamps", got, want)
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			fmt.Fprintf(os.Stderr, "unable to fetch %s/%d: %v\n", t, p, err)
+		})
+		fetches.EachRecord(func(r *kgo.Record) {
+			ps := timestamps[r.Topic]
+			if ps == nil {
+				ps = make(map[int32]int64)
+				timestamps[r.Topic] = ps
+			}
+			if _, ok := ps[r.Partition]; ok {
+				return
+			}
+			ps[r.Partition] = r.Timestamp.UnixMilli()
+			got++
+			cl.PauseFetchPartitions(map[string][]int32{r.Topic: {r.Partition}})
+		})
+	}
+	return timestamps, nil
+}
+
+// offsetBackupDir returns the directory that offset backups for the group
+// are stored in.
+func offsetBackupDir(group string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the user config directory: %v", err)
+	}
+	return filepath.Join(dir, "rpk", "group-offset-backups", url.PathEscape(group)), nil
+}
+
+// backupFileTimeFormat is used to name backup files such that they sort by
+// time.
+const backupFileTimeFormat = "20060102T150405.000000000Z"
+
+// writeOffsetBackup writes the offsets to a new backup file in dir, returning
+// the path of the file.
+func writeOffsetBackup(fs afero.Fs, dir string, f offsetsFile) (string, error) {
+	if err := fs.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create backup directory %q: %v", dir, err)
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("unable to encode offsets: %v", err)
+	}
+	path := filepath.Join(dir, f.ExportedAt.UTC().Format(backupFileTimeFormat)+".json")
+	if err := afero.WriteFile(fs, path, b, 0o644); err != nil {
+		return "", fmt.Errorf("unable to write backup %q: %v", path, err)
+	}
+	return path, nil
+}
+
+// listOffsetBackups returns the backup files in dir, oldest first.
+func listOffsetBackups(fs afero.Fs, dir string) ([]string, error) {
+	infos, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read backup directory %q: %v", dir, err)
+	}
+	var paths []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") {
+			paths = append(paths, filepath.Join(dir, info.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// backupPriorOffsets backs up the current offsets of every partition in
+// commitTo, recording partitions that have no current commit with offset -1.
+func backupPriorOffsets(
+	fs afero.Fs, group string, current, commitTo kadm.Offsets,
+) (string, error) {
+	prior := make(kadm.Offsets)
+	commitTo.Each(func(o kadm.Offset) {
+		if c, ok := current.Lookup(o.Topic, o.Partition); ok {
+			prior.Add(c)
+			return
+		}
+		prior.Add(kadm.Offset{Topic: o.Topic, Partition: o.Partition, At: -1, LeaderEpoch: -1})
+	})
+	dir, err := offsetBackupDir(group)
+	if err != nil {
+		return "", err
+	}
+	return writeOffsetBackup(fs, dir, newOffsetsFile(group, prior, nil))
+}
+
+func newOffsetsBackupsCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "backups [GROUP]",
+		Short: "List the offset backups of a group",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			dir, err := offsetBackupDir(args[0])
+			out.MaybeDieErr(err)
+			paths, err := listOffsetBackups(fs, dir)
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable("backup", "created", "partitions")
+			defer tw.Flush()
+			for _, path := range paths {
+				raw, err := afero.ReadFile(fs, path)
+				out.MaybeDie(err, "unable to read %q: %v", path, err)
+				f, err := parseOffsetsFile(raw)
+				out.MaybeDie(err, "unable to parse %q: %v", path, err)
+				tw.Print(path, f.ExportedAt.Format(time.RFC3339), len(f.Offsets))
+			}
+		},
+	}
+}
+
+func newOffsetsUndoCommand(fs afero.Fs) *cobra.Command {
+	var (
+		backup    string
+		noConfirm bool
+	)
+	cmd := &cobra.Command{
+		Use:   "undo [GROUP]",
+		Short: "Restore a group's offsets from the most recent backup",
+		Long: `Restore a group's offsets from the most recent backup.
+
+This commits the offsets saved in the most recent backup of the group, or the
+backup specified with --backup. Partitions that had no commit before the
+backed up seek have their offsets deleted. As with seek, the group must be
+empty.
+
+Undo is itself a seek, and backs up the offsets it replaces: running undo twice
+redoes the original seek.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			group := args[0]
+			if backup == "" {
+				dir, err := offsetBackupDir(group)
+				out.MaybeDieErr(err)
+				paths, err := listOffsetBackups(fs, dir)
+				out.MaybeDieErr(err)
+				if len(paths) == 0 {
+					out.Die("no offset backups exist for group %q", group)
+				}
+				backup = paths[len(paths)-1]
+			}
+			raw, err := afero.ReadFile(fs, backup)
+			out.MaybeDie(err, "unable to read %q: %v", backup, err)
+			f, err := parseOffsetsFile(raw)
+			out.MaybeDie(err, "unable to parse %q: %v", backup, err)
+			if f.Group != group {
+				out.Die("backup %q is for group %q, not %q", backup, f.Group, group)
+			}
+			commitTo, uncommitted := f.offsets()
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			current := seekFetch(adm, group, nil)
+
+			fmt.Printf("Restoring offsets from %s (backed up at %s):\n", backup, f.ExportedAt.Format(time.RFC3339))
+			tw := out.NewTable("topic", "partition", "current-offset", "restore-offset")
+			for _, o := range f.Offsets {
+				cur, restore := "-", "-"
+				if c, ok := current.Lookup(o.Topic, o.Partition); ok {
+					cur = fmt.Sprint(c.At)
+				}
+				if o.Offset >= 0 {
+					restore = fmt.Sprint(o.Offset)
+				}
+				tw.Print(o.Topic, o.Partition, cur, restore)
+			}
+			tw.Flush()
+
+			if !noConfirm {
+				confirmed, err := out.Confirm("Restore the above offsets?")
+				out.MaybeDie(err, "unable to confirm restore: %v", err)
+				if !confirmed {
+					out.Exit("Command execution canceled.")
+				}
+			}
+
+			all := make(kadm.Offsets)
+			commitTo.Each(all.Add)
+			uncommitted.Each(func(t string, p int32) {
+				all.Add(kadm.Offset{Topic: t, Partition: p, At: -1})
+			})
+			path, err := backupPriorOffsets(fs, group, current, all)
+			out.MaybeDie(err, "unable to back up current offsets: %v", err)
+
+			var failed bool
+			if len(commitTo) > 0 {
+				committed, err := adm.CommitOffsets(context.Background(), group, commitTo)
+				out.MaybeDie(err, "unable to commit offsets: %v", err)
+				committed.Each(func(c kadm.OffsetResponse) {
+					if c.Err != nil {
+						failed = true
+						fmt.Fprintf(os.Stderr, "unable to commit %s/%d: %v\n", c.Topic, c.Partition, commitErrMessage(c.Err))
+					}
+				})
+			}
+			if len(uncommitted) > 0 {
+				deleted, err := adm.DeleteOffsets(context.Background(), group, uncommitted)
+				out.MaybeDie(err, "unable to delete offsets: %v", err)
+				for t, ps := range deleted {
+					for p, err := range ps {
+						if err != nil {
+							failed = true
+							fmt.Fprintf(os.Stderr, "unable to delete the offset of %s/%d: %v\n", t, p, err)
+						}
+					}
+				}
+			}
+			fmt.Printf("Backed up replaced offsets to %s.\n", path)
+			if failed {
+				out.Die("unable to restore all offsets")
+			}
+			fmt.Println("Restored offsets.")
+		},
+	}
+	cmd.Flags().StringVar(&backup, "backup", "", "Backup file to restore, rather than the most recent backup")
+	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "Disable confirmation prompt")
+	return cmd
+}
@@ -0,0 +1,82 @@
+package group
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestOffsetBackups(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	dir := "/backups/g"
+
+	paths, err := listOffsetBackups(fs, dir)
+	require.NoError(t, err)
+	require.Empty(t, paths)
+
+	current := kadm.Offsets{"foo": {
+		0: {Topic: "foo", Partition: 0, At: 5, LeaderEpoch: -1},
+		1: {Topic: "foo", Partition: 1, At: 7, LeaderEpoch: -1},
+	}}
+	first := newOffsetsFile("g", current, nil)
+	_, err = writeOffsetBackup(fs, dir, first)
+	require.NoError(t, err)
+
+	second := newOffsetsFile("g", kadm.Offsets{"foo": {
+		0: {Topic: "foo", Partition: 0, At: 9, LeaderEpoch: -1},
+		2: {Topic: "foo", Partition: 2, At: -1, LeaderEpoch: -1},
+	}}, nil)
+	second.ExportedAt = first.ExportedAt.Add(time.Second)
+	latest, err := writeOffsetBackup(fs, dir, second)
+	require.NoError(t, err)
+
+	paths, err = listOffsetBackups(fs, dir)
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	require.Equal(t, latest, paths[1])
+
+	raw, err := afero.ReadFile(fs, latest)
+	require.NoError(t, err)
+	f, err := parseOffsetsFile(raw)
+	require.NoError(t, err)
+	require.Equal(t, "g", f.Group)
+
+	commit, uncommitted := f.offsets()
+	require.Equal(t, kadm.Offsets{"foo": {
+		0: {Topic: "foo", Partition: 0, At: 9, LeaderEpoch: -1},
+	}}, commit)
+	require.Equal(t, kadm.TopicsSet{"foo": {2: {}}}, uncommitted)
+}
+
+func TestOffsetsFileWriteText(t *testing.T) {
+	ts := int64(1622505600000)
+	f := offsetsFile{
+		Group:      "g",
+		ExportedAt: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		Offsets: []exportedOffset{
+			{Topic: "foo", Partition: 0, Offset: 1, Timestamp: &ts},
+			{Topic: "foo", Partition: 1, Offset: -1},
+			{Topic: "foo", Partition: 2, Offset: 3},
+		},
+	}
+	var buf bytes.Buffer
+	f.writeText(&buf)
+	require.Equal(t, `# group g exported at 2021-06-01T00:00:00Z
+foo 0 1 # timestamp 1622505600000 (2021-06-01T00:00:00Z)
+# foo 1 has no committed offset
+foo 2 3
+`, buf.String())
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "offsets", buf.Bytes(), 0o644))
+	o, err := parseSeekFile(fs, "offsets", nil)
+	require.NoError(t, err)
+	require.Equal(t, kadm.Offsets{"foo": {
+		0: {Topic: "foo", Partition: 0, At: 1, LeaderEpoch: -1},
+		2: {Topic: "foo", Partition: 2, At: 3, LeaderEpoch: -1},
+	}}, o)
+}
@@ -11,9 +11,11 @@ package group
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -33,6 +35,7 @@ func newSeekCommand(fs afero.Fs) *cobra.Command {
 		toFile         string
 		topics         []string
 		allowNewTopics bool
+		noBackup       bool
 	)
 
 	cmd := &cobra.Command{
@@ -62,15 +65,21 @@ the following format:
     [TOPIC] [PARTITION] [OFFSET]
     [TOPIC] [PARTITION] [OFFSET]
     ...
-Each line contains the topic, the partition, and the offset to seek to. As with
-the prior options, --topics allows filtering which topics are updated. Similar
-to --to-group, all non-filtered topics are committed, even topics not yet being
-consumed, meaning --allow-new-topics is not needed.
+Each line contains the topic, the partition, and the offset to seek to. Text
+after a '#' is ignored. The file can also be the JSON output of 'rpk group
+offsets export --format json' or an offset backup. As with the prior options,
+--topics allows filtering which topics are updated. Similar to --to-group, all
+non-filtered topics are committed, even topics not yet being consumed, meaning
+--allow-new-topics is not needed.
 
 The --to, --to-group, and --to-file options are mutually exclusive. If you are
 not authorized to describe or read some topics used in a group, you will not be
 able to modify offsets for those topics.
 
+Before committing, the group's prior offsets of every partition being modified
+are backed up to your user config directory. 'rpk group offsets undo' restores
+the most recent backup. Backing up can be disabled with --no-backup.
+
 EXAMPLES
 
 Seek group G to June 1st, 2021:
@@ -83,6 +92,8 @@ Seek group G's topics foo, bar, and biz to the end:
     rpk group seek G --to end --topics foo,bar,biz
 Seek group G to the beginning of a topic it was not previously consuming:
     rpk group seek G --to start --topics foo --allow-new-topics
+Undo the prior seek of group G:
+    rpk group offsets undo G
 `,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -115,7 +126,7 @@ Seek group G to the beginning of a topic it was not previously consuming:
 
 			group := args[0]
 
-			seek(fs, adm, group, to, toGroup, toFile, tset, allowNewTopics)
+			seek(fs, adm, group, to, toGroup, toFile, tset, allowNewTopics, noBackup)
 		},
 	}
 
@@ -124,6 +135,7 @@ Seek group G to the beginning of a topic it was not previously consuming:
 	cmd.Flags().StringVar(&toFile, "to-file", "", "Seek to offsets as specified in the file")
 	cmd.Flags().StringSliceVar(&topics, "topics", nil, "Only seek these topics, if any are specified")
 	cmd.Flags().BoolVar(&allowNewTopics, "allow-new-topics", false, "Allow seeking to new topics not currently consumed (implied with --to-group or --to-file)")
+	cmd.Flags().BoolVar(&noBackup, "no-backup", false, "Do not back up the group's prior offsets before seeking")
 
 	return cmd
 }
@@ -131,15 +143,32 @@ Seek group G to the beginning of a topic it was not previously consuming:
 func parseSeekFile(
 	fs afero.Fs, file string, topics map[string]bool,
 ) (kadm.Offsets, error) {
-	f, err := fs.Open(file)
+	raw, err := afero.ReadFile(fs, file)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open %q: %v", file, err)
 	}
 
 	o := make(kadm.Offsets)
-	s := bufio.NewScanner(f)
+	if isJSONOffsets(raw) {
+		f, err := parseOffsetsFile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("file %q: %v", file, err)
+		}
+		// Partitions with no prior commit (from backups) cannot be
+		// seeked to; 'rpk group offsets undo' deletes them instead.
+		o, _ = f.offsets()
+		if len(topics) > 0 {
+			o.KeepFunc(func(o kadm.Offset) bool { return topics[o.Topic] })
+		}
+		return o, nil
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(raw))
 	for s.Scan() {
 		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = strings.TrimRight(line[:i], " \t")
+		}
 		if len(line) == 0 {
 			continue
 		}
@@ -204,6 +233,7 @@ func seek(
 	toFile string,
 	topics map[string]bool,
 	allowNewTopics bool,
+	noBackup bool,
 ) {
 	current := seekFetch(adm, group, topics)
 	var commitTo kadm.Offsets
@@ -253,6 +283,12 @@ func seek(
 		commitTo = listed.Offsets()
 	}
 
+	if !noBackup && len(commitTo) > 0 {
+		path, err := backupPriorOffsets(fs, group, current, commitTo)
+		out.MaybeDie(err, "unable to back up prior offsets (use --no-backup to skip): %v", err)
+		fmt.Fprintf(os.Stderr, "Backed up prior offsets to %s; undo with 'rpk group offsets undo %s'.\n", path, group)
+	}
+
 	// Finally, we commit.
 	committed, err := adm.CommitOffsets(context.Background(), group, commitTo)
 	out.MaybeDie(err, "unable to commit offsets: %v", err)
@@ -274,14 +310,7 @@ func seek(
 		}
 		se := seekCommitErr{c.Topic, c.Partition, -1, -1, ""}
 		if c.Err != nil {
-			// Redpanda / Kafka send UnknownMemberID when issuing OffsetCommit
-			// if the group is not empty. This error is unclear to end users, so
-			// we remap it here.
-			if errors.Is(c.Err, kerr.UnknownMemberID) {
-				se.Error = "INVALID_OPERATION: seeking a non-empty group is not allowed."
-			} else {
-				se.Error = c.Err.Error()
-			}
+			se.Error = commitErrMessage(c.Err)
 		}
 		if useErr {
 			tw.PrintStructFields(se)
@@ -291,6 +320,17 @@ func seek(
 	}
 }
 
+// commitErrMessage returns the message to print for an OffsetCommit error.
+func commitErrMessage(err error) string {
+	// Redpanda / Kafka send UnknownMemberID when issuing OffsetCommit if
+	// the group is not empty. This error is unclear to end users, so we
+	// remap it here.
+	if errors.Is(err, kerr.UnknownMemberID) {
+		return "INVALID_OPERATION: seeking a non-empty group is not allowed."
+	}
+	return err.Error()
+}
+
 type seekCommit struct {
 	Topic     string
 	Partition int32
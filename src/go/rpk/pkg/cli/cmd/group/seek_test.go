@@ -70,6 +70,24 @@ func TestParseSeekFile(t *testing.T) {
 			exp:      expBoth,
 		},
 
+		{
+			name:     "comments",
+			contents: "# group g\nfoo 0 1 # timestamp 1622505600000\nfoo 2 3\n",
+			exp:      expBoth,
+		},
+
+		{
+			name:     "json",
+			contents: `{"group":"g","offsets":[{"topic":"foo","partition":0,"offset":1,"timestamp":1622505600000},{"topic":"foo","partition":2,"offset":3},{"topic":"foo","partition":4,"offset":-1},{"topic":"bar","partition":0,"offset":2}]}`,
+			exp:      expBoth,
+		},
+
+		{
+			name:     "bad_json",
+			contents: `{"group":`,
+			expErr:   true,
+		},
+
 		{
 			name:     "nothing_is_fine",
 			contents: "",
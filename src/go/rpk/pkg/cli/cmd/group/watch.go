@@ -0,0 +1,137 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package group
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// lagSample is the committed offset and end offset of a group's partition at
+// a point in time.
+type lagSample struct {
+	at     time.Time
+	commit int64
+	end    int64
+	lag    int64
+}
+
+type lagKey struct {
+	group     string
+	topic     string
+	partition int32
+}
+
+// lagProgress is how a partition's lag changed between two samples.
+type lagProgress struct {
+	lagDelta    int64
+	commitRate  float64 // committed records per second
+	produceRate float64 // produced records per second
+
+	// catchUp is the estimated time until the lag reaches zero, or -1 if
+	// the lag is not shrinking.
+	catchUp time.Duration
+}
+
+func calculateProgress(prev, cur lagSample) lagProgress {
+	p := lagProgress{
+		lagDelta: cur.lag - prev.lag,
+		catchUp:  -1,
+	}
+	elapsed := cur.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return p
+	}
+	if prev.commit >= 0 && cur.commit >= 0 {
+		p.commitRate = float64(cur.commit-prev.commit) / elapsed
+	}
+	p.produceRate = float64(cur.end-prev.end) / elapsed
+
+	switch shrink := p.commitRate - p.produceRate; {
+	case cur.lag == 0:
+		p.catchUp = 0
+	case shrink > 0:
+		p.catchUp = time.Duration(float64(cur.lag) / shrink * float64(time.Second))
+	}
+	return p
+}
+
+// watchLag polls the lag of the groups every interval, printing the lag of
+// each partition and how it changed since the last poll. This runs until the
+// process is interrupted.
+func watchLag(adm *kadm.Client, groups []string, interval time.Duration) {
+	prev := make(map[lagKey]lagSample)
+	for first := true; ; first = false {
+		if !first {
+			time.Sleep(interval)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		lags, err := describeLag(ctx, adm, groups, func(err error) {
+			fmt.Fprintln(os.Stderr, err)
+		})
+		cancel()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		now := time.Now()
+		fmt.Println(now.Format(time.RFC3339))
+		for _, dl := range lags {
+			printWatchedGroup(dl, now, prev)
+		}
+		fmt.Println()
+	}
+}
+
+func printWatchedGroup(dl describedLag, now time.Time, prev map[lagKey]lagSample) {
+	fmt.Printf("GROUP %s (%s, %d members)\n", dl.group.Group, dl.group.State, len(dl.group.Members))
+	tw := out.NewTable(
+		"topic",
+		"partition",
+		"current-offset",
+		"log-end-offset",
+		"lag",
+		"lag-delta",
+		"commit-rate",
+		"produce-rate",
+		"catch-up",
+	)
+	defer tw.Flush()
+	for _, l := range dl.lag.Sorted() {
+		key := lagKey{dl.group.Group, l.End.Topic, l.End.Partition}
+		if l.Err != nil {
+			tw.Print(key.topic, key.partition, "-", "-", "-", "-", "-", "-", l.Err)
+			delete(prev, key)
+			continue
+		}
+		cur := lagSample{at: now, commit: l.Commit.At, end: l.End.Offset, lag: l.Lag}
+		current := "-"
+		if cur.commit >= 0 {
+			current = fmt.Sprint(cur.commit)
+		}
+		delta, commitRate, produceRate, catchUp := "-", "-", "-", "-"
+		if last, ok := prev[key]; ok {
+			p := calculateProgress(last, cur)
+			delta = fmt.Sprintf("%+d", p.lagDelta)
+			commitRate = fmt.Sprintf("%.1f/s", p.commitRate)
+			produceRate = fmt.Sprintf("%.1f/s", p.produceRate)
+			if p.catchUp >= 0 {
+				catchUp = p.catchUp.Round(time.Second).String()
+			}
+		}
+		prev[key] = cur
+		tw.Print(key.topic, key.partition, current, cur.end, cur.lag, delta, commitRate, produceRate, catchUp)
+	}
+}
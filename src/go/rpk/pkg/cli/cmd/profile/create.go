@@ -0,0 +1,88 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCommand(fs afero.Fs) *cobra.Command {
+	var (
+		description string
+		fromConfig  bool
+		use         bool
+	)
+	cmd := &cobra.Command{
+		Use:   "create [NAME]",
+		Short: "Create a profile",
+		Long: `Create a profile.
+
+The profile is created from the connection flags passed to this command. Only
+settings that are passed are saved; anything else continues to come from
+redpanda.yaml when the profile is used. For example:
+
+  rpk profile create prod \
+    --brokers prod-0:9092,prod-1:9092 \
+    --tls-enabled \
+    --user admin --password secret --sasl-mechanism SCRAM-SHA-256 \
+    --api-urls prod-0:9644,prod-1:9644
+
+With --from-config, the profile starts with the rpk section of your
+redpanda.yaml (see --config), and any flags are applied on top.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			p, ps := loadProfiles(fs, cmd)
+
+			profile, err := p.ProfileFromFlags(name)
+			out.MaybeDieErr(err)
+			profile.Description = description
+
+			if fromConfig {
+				cfg, err := p.Load(fs)
+				out.MaybeDie(err, "unable to load config: %v", err)
+				file := cfg.FileOrDefaults()
+				base := &config.RpkProfile{
+					Name:              name,
+					Description:       description,
+					KafkaAPI:          file.Rpk.KafkaAPI,
+					AdminAPI:          file.Rpk.AdminAPI,
+					SchemaRegistryAPI: file.Rpk.SchemaRegistryAPI,
+				}
+				base.Merge(profile)
+				profile = base
+			}
+
+			err = ps.Add(*profile)
+			out.MaybeDieErr(err)
+			if use {
+				ps.CurrentProfile = name
+			}
+			err = ps.Write(fs)
+			out.MaybeDie(err, "unable to write profiles: %v", err)
+
+			fmt.Printf("Created profile %q.\n", name)
+			if use {
+				fmt.Printf("Using profile %q.\n", name)
+			}
+		},
+	}
+	addConnectionFlags(cmd)
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Optional description of the profile")
+	cmd.Flags().BoolVar(&fromConfig, "from-config", false, "Start from the rpk section of redpanda.yaml")
+	cmd.Flags().BoolVar(&use, "use", false, "Use the profile after creating it")
+	return cmd
+}
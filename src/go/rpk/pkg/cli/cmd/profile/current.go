@@ -0,0 +1,49 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newCurrentCommand(fs afero.Fs) *cobra.Command {
+	var printPath bool
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Print the name of the active profile",
+		Long: `Print the name of the active profile.
+
+The active profile is the one named by the --profile flag, the RPK_PROFILE
+environment variable, or selected with 'rpk profile use', in that order. If no
+profile is active, this exits with an error.
+
+Use --print-path to print the location of the profiles file instead.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p, ps := loadProfiles(fs, cmd)
+			if printPath {
+				fmt.Println(ps.FileLocation())
+				return
+			}
+			name := p.ActiveProfileName(ps)
+			if name == "" {
+				out.Die("no profile is active")
+			}
+			fmt.Println(name)
+		},
+	}
+	cmd.Flags().BoolVar(&printPath, "print-path", false, "Print the path of the profiles file")
+	return cmd
+}
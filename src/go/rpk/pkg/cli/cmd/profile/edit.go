@@ -0,0 +1,118 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newEditCommand(fs afero.Fs) *cobra.Command {
+	var description string
+	cmd := &cobra.Command{
+		Use:   "edit [NAME]",
+		Short: "Edit a profile",
+		Long: `Edit a profile.
+
+If any connection flags are passed, they are saved into the profile, replacing
+the settings the profile previously had for them. Otherwise, the profile is
+opened as YAML in your $EDITOR, and saved once the editor exits.
+
+The profile cannot be renamed with this command; a "name" changed in the
+editor is ignored.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			p, ps := loadProfiles(fs, cmd)
+			profile := ps.Profile(name)
+			if profile == nil {
+				out.Die("profile %q does not exist", name)
+			}
+
+			if len(p.FlagOverrides) > 0 || cmd.Flags().Changed("description") {
+				update, err := p.ProfileFromFlags(name)
+				out.MaybeDieErr(err)
+				update.Description = description
+				profile.Merge(update)
+			} else {
+				edited, err := editProfile(profile)
+				out.MaybeDieErr(err)
+				edited.Name = name
+				*profile = *edited
+			}
+
+			err := ps.Write(fs)
+			out.MaybeDie(err, "unable to write profiles: %v", err)
+			fmt.Printf("Updated profile %q.\n", name)
+		},
+	}
+	addConnectionFlags(cmd)
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Description of the profile")
+	return cmd
+}
+
+// editProfile writes the profile to a temporary file, launches $EDITOR on it,
+// and returns the profile parsed from the edited file.
+func editProfile(profile *config.RpkProfile) (*config.RpkProfile, error) {
+	b, err := yaml.Marshal(profile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode profile: %v", err)
+	}
+
+	file, err := os.CreateTemp("", "rpk-profile-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary file: %v", err)
+	}
+	filename := file.Name()
+	defer os.Remove(filename)
+
+	if _, err = file.Write(b); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to write temporary file %q: %v", filename, err)
+	}
+	if err = file.Close(); err != nil {
+		return nil, fmt.Errorf("error closing temporary file %q: %v", filename, err)
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		const fallbackEditor = "/usr/bin/nano"
+		if _, err := os.Stat(fallbackEditor); err != nil {
+			return nil, fmt.Errorf("please set $EDITOR to use this command")
+		}
+		editor = fallbackEditor
+	}
+
+	child := exec.Command(editor, filename)
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	child.Stdin = os.Stdin
+	if err = child.Run(); err != nil {
+		return nil, fmt.Errorf("error running editor: %v", err)
+	}
+
+	b, err = os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read edited profile: %v", err)
+	}
+	var edited config.RpkProfile
+	if err := yaml.Unmarshal(b, &edited); err != nil {
+		return nil, fmt.Errorf("unable to decode edited profile: %v", err)
+	}
+	return &edited, nil
+}
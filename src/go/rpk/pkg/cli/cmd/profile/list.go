@@ -0,0 +1,39 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List profiles, marking the active profile with an asterisk",
+		Args:    cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p, ps := loadProfiles(fs, cmd)
+			active := p.ActiveProfileName(ps)
+
+			tw := out.NewTable("name", "description")
+			defer tw.Flush()
+			for _, profile := range ps.Profiles {
+				name := profile.Name
+				if name == active {
+					name += "*"
+				}
+				tw.Print(name, profile.Description)
+			}
+		},
+	}
+}
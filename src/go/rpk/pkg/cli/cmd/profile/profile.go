@@ -0,0 +1,95 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage rpk connection profiles",
+		Long: `Manage rpk connection profiles.
+
+A profile is a named set of connection settings: Kafka brokers, TLS, and SASL,
+Admin API addresses and TLS, and Schema Registry addresses and TLS. Profiles
+let you switch between clusters (for example, dev, staging, and prod) without
+editing redpanda.yaml or passing flags on every command.
+
+Profiles are stored in a user-level file, ~/.config/rpk/profiles.yaml on Linux
+(see 'rpk profile current --help' for how to find it on your system).
+
+When a profile is active, every setting it defines takes precedence over the
+rpk section of redpanda.yaml. Environment variables and flags still take
+precedence over the profile. The active profile is, in order:
+
+  * the profile named with the global --profile flag
+  * the profile named in the RPK_PROFILE environment variable
+  * the profile most recently selected with 'rpk profile use'
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newCreateCommand(fs),
+		newCurrentCommand(fs),
+		newDeleteCommand(fs),
+		newEditCommand(fs),
+		newListCommand(fs),
+		newUseCommand(fs),
+	)
+	return cmd
+}
+
+// addConnectionFlags adds every connection flag that can be saved in a
+// profile.
+func addConnectionFlags(cmd *cobra.Command) {
+	var (
+		brokers        []string
+		configFile     string
+		user           string
+		password       string
+		mechanism      string
+		enableTLS      bool
+		certFile       string
+		keyFile        string
+		truststoreFile string
+
+		adminURLs              []string
+		adminEnableTLS         bool
+		adminCertFile          string
+		adminKeyFile           string
+		adminTruststoreFile    string
+		registryURLs           []string
+		registryEnableTLS      bool
+		registryCertFile       string
+		registryKeyFile        string
+		registryTruststoreFile string
+	)
+	common.AddKafkaFlags(cmd, &configFile, &user, &password, &mechanism, &enableTLS, &certFile, &keyFile, &truststoreFile, &brokers)
+
+	cmd.PersistentFlags().StringSliceVar(&adminURLs, config.FlagAdminHosts2, nil, "Comma-separated list of admin API addresses (<IP>:<port>)")
+	common.AddAdminAPITLSFlags(cmd, &adminEnableTLS, &adminCertFile, &adminKeyFile, &adminTruststoreFile)
+
+	cmd.PersistentFlags().StringSliceVar(&registryURLs, config.FlagRegistryHosts, nil, "Comma-separated list of Schema Registry addresses (<IP>:<port>)")
+	common.AddSchemaRegistryTLSFlags(cmd, &registryEnableTLS, &registryCertFile, &registryKeyFile, &registryTruststoreFile)
+}
+
+// loadProfiles loads the profiles file, exiting on failure.
+func loadProfiles(fs afero.Fs, cmd *cobra.Command) (*config.Params, *config.RpkProfiles) {
+	p := config.ParamsFromCommand(cmd)
+	ps, err := p.LoadProfiles(fs)
+	out.MaybeDie(err, "unable to load profiles: %v", err)
+	return p, ps
+}
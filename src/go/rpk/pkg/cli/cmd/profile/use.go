@@ -0,0 +1,37 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newUseCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [NAME]",
+		Short: "Select the profile to use for subsequent commands",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			_, ps := loadProfiles(fs, cmd)
+			if ps.Profile(name) == nil {
+				out.Die("profile %q does not exist", name)
+			}
+			ps.CurrentProfile = name
+			err := ps.Write(fs)
+			out.MaybeDie(err, "unable to write profiles: %v", err)
+			fmt.Printf("Using profile %q.\n", name)
+		},
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/ui"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/remote"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -29,18 +30,84 @@ import (
 
 func NewCheckCommand(fs afero.Fs) *cobra.Command {
 	var (
-		configFile string
-		timeout    time.Duration
+		configFile   string
+		timeout      time.Duration
+		format       out.Format
+		junitFile    string
+		preProvision bool
+		remoteCfg    remote.Config
 	)
 	command := &cobra.Command{
 		Use:   "check",
 		Short: "Check if system meets redpanda requirements",
+		Long: `Check if system meets redpanda requirements.
+
+Every failed check is reported with how to fix it: either the tuner that fixes
+it ('rpk redpanda tune <tuner>'), or a manual step.
+
+The results can be printed as json or yaml with --format, and written as a
+JUnit XML report with --junit, such that CI and provisioning tools can gate on
+them. The exit code depends on the severity of the failed checks:
+
+  0  all checks passed
+  1  a Fatal check failed, or the checks could not run
+  2  only Warning checks failed
+
+With --pre-provision, the checks run against the --config file of a node that
+is not provisioned yet: the config file must exist, the checks of a data
+directory that does not exist yet run against the directory it will be
+created in, and the checks of files that provisioning creates (the I/O config
+and ballast files) are skipped.
+
+With --hosts, the checks run on every host over SSH, by running the same rpk
+command there, and the results are aggregated into one table with a column
+per host, which shows the checks whose values drift across the hosts. With
+--junit, the report has one test suite per host. The exit code is the worst
+across the hosts; hosts that cannot be checked exit 1.
+`,
+		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
+			if preProvision {
+				if configFile == "" {
+					out.Die("--pre-provision requires --config")
+				}
+				// With --hosts, the file only has to exist on the
+				// remote hosts, whose rpk reports it if it does not.
+				if exists, _ := afero.Exists(fs, configFile); !exists && len(remoteCfg.Hosts) == 0 {
+					out.Die("config file %q does not exist", configFile)
+				}
+			}
+			if len(remoteCfg.Hosts) > 0 {
+				code, err := checkHosts(fs, cmd, &remoteCfg, format, junitFile)
+				out.MaybeDie(err, "unable to check hosts: %v", err)
+				if code != 0 {
+					os.Exit(code)
+				}
+				return
+			}
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
-			err = executeCheck(fs, cfg, timeout)
+
+			check := tuners.Check
+			if preProvision {
+				check = tuners.CheckPreProvision
+			}
+			results, err := check(fs, cfg, timeout)
 			out.MaybeDie(err, "unable to check: %v", err)
+
+			report := newCheckReport(results)
+			if junitFile != "" {
+				hostname, _ := os.Hostname()
+				err = writeJUnitReport(fs, junitFile, newJUnitSuite(hostname, &report, "", time.Now()))
+				out.MaybeDie(err, "unable to write JUnit report: %v", err)
+			}
+			format.Print(report, func() {
+				printCheckResults(results)
+			})
+			if code := report.exitCode(); code != 0 {
+				os.Exit(code)
+			}
 		},
 	}
 	command.Flags().StringVar(
@@ -59,6 +126,10 @@ func NewCheckCommand(fs afero.Fs) *cobra.Command {
 			"fraction and a unit suffix, such as '300ms', '1.5s' or '2h45m'. "+
 			"Valid time units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'",
 	)
+	command.Flags().StringVar(&junitFile, "junit", "", "Also write the results as a JUnit XML report to this file")
+	command.Flags().BoolVar(&preProvision, "pre-provision", false, "Check the --config file of a node that is not provisioned yet")
+	out.AddFormatFlag(command, &format)
+	remote.AddFlags(command, &remoteCfg)
 	return command
 }
 
@@ -72,13 +143,7 @@ func appendToTable(t *tablewriter.Table, r tuners.CheckResult) {
 	})
 }
 
-func executeCheck(
-	fs afero.Fs, cfg *config.Config, timeout time.Duration,
-) error {
-	results, err := tuners.Check(fs, cfg, timeout)
-	if err != nil {
-		return err
-	}
+func printCheckResults(results []tuners.CheckResult) {
 	table := ui.NewRpkTable(os.Stdout)
 	table.SetHeader([]string{
 		"Condition",
@@ -93,7 +158,22 @@ func executeCheck(
 	}
 	fmt.Printf("\nSystem check results\n")
 	table.Render()
-	return nil
+
+	var failed []tuners.CheckResult
+	for _, res := range results {
+		if !res.IsOk {
+			failed = append(failed, res)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Printf("\nTo fix the failed checks:\n")
+	tw := out.NewTabWriter()
+	defer tw.Flush()
+	for _, res := range failed {
+		tw.Print("  "+res.Desc+":", res.Remediation)
+	}
 }
 
 func printResult(sev tuners.Severity, isOk bool) string {
@@ -0,0 +1,166 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package redpanda
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/remote"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type hostCheckReport struct {
+	Host   string       `json:"host"`
+	Error  string       `json:"error,omitempty"`
+	Report *checkReport `json:"report,omitempty"`
+}
+
+// checkHosts runs the check command on every host, and returns the worst exit
+// code across the hosts.
+func checkHosts(
+	fs afero.Fs,
+	cmd *cobra.Command,
+	cfg *remote.Config,
+	format out.Format,
+	junitFile string,
+) (int, error) {
+	results, err := cfg.Run(fs, remote.Args(cmd, nil, "junit"))
+	if err != nil {
+		return 0, err
+	}
+	reports := decodeHostCheckReports(results)
+	if junitFile != "" {
+		now := time.Now()
+		var suites []junitTestSuite
+		for _, h := range reports {
+			suites = append(suites, newJUnitSuite(h.Host, h.Report, h.Error, now))
+		}
+		if err := writeJUnitReport(fs, junitFile, suites...); err != nil {
+			return 0, fmt.Errorf("unable to write JUnit report: %v", err)
+		}
+	}
+	format.Print(reports, func() {
+		printHostCheckReports(cfg.Hosts, reports)
+	})
+	return hostsCheckExitCode(reports), nil
+}
+
+func decodeHostCheckReports(results []remote.Result) []hostCheckReport {
+	reports := make([]hostCheckReport, 0, len(results))
+	for _, r := range results {
+		h := hostCheckReport{Host: r.Host}
+		var report checkReport
+		if err := r.Decode(&report); err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Report = &report
+		}
+		reports = append(reports, h)
+	}
+	return reports
+}
+
+// hostsCheckExitCode returns 1 if a host could not be checked or a Fatal check
+// failed on any host, 2 if only Warning checks failed, and 0 otherwise.
+func hostsCheckExitCode(reports []hostCheckReport) int {
+	var code int
+	for _, h := range reports {
+		if h.Report == nil {
+			return 1
+		}
+		switch h.Report.exitCode() {
+		case 1:
+			return 1
+		case 2:
+			code = 2
+		}
+	}
+	return code
+}
+
+func printHostCheckReports(hosts []string, reports []hostCheckReport) {
+	type condition struct {
+		severity    string
+		required    string
+		values      map[string]string
+		remediation string
+		failedOn    []string
+	}
+	var (
+		names      []string
+		conditions = make(map[string]*condition)
+		hostErrs   []hostCheckReport
+	)
+	for _, h := range reports {
+		if h.Report == nil {
+			hostErrs = append(hostErrs, h)
+			continue
+		}
+		for _, c := range h.Report.Checks {
+			cond, ok := conditions[c.Condition]
+			if !ok {
+				cond = &condition{severity: c.Severity, required: c.Required, values: make(map[string]string)}
+				conditions[c.Condition] = cond
+				names = append(names, c.Condition)
+			}
+			value := c.Current
+			if c.Error != "" && value == "" {
+				value = "error"
+			}
+			if !c.Passed {
+				value += " (failed)"
+				cond.failedOn = append(cond.failedOn, h.Host)
+				if c.Remediation != nil {
+					cond.remediation = c.Remediation.String()
+				}
+			}
+			cond.values[h.Host] = value
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\nSystem check results\n")
+	t := remote.NewDriftTable(hosts, "condition", "severity", "required")
+	for _, name := range names {
+		c := conditions[name]
+		t.Add([]string{name, c.severity, c.required}, c.values)
+	}
+	t.Print()
+
+	var fixes [][]interface{}
+	for _, name := range names {
+		if c := conditions[name]; len(c.failedOn) > 0 {
+			fixes = append(fixes, []interface{}{"  " + name + ":", c.remediation, "(" + strings.Join(c.failedOn, ", ") + ")"})
+		}
+	}
+	if len(fixes) > 0 {
+		fmt.Printf("\nTo fix the failed checks:\n")
+		tw := out.NewTabWriter()
+		for _, fix := range fixes {
+			tw.Print(fix...)
+		}
+		tw.Flush()
+	}
+	if len(hostErrs) > 0 {
+		fmt.Printf("\nUnable to check hosts:\n")
+		tw := out.NewTabWriter()
+		for _, h := range hostErrs {
+			tw.Print("  "+h.Host+":", h.Error)
+		}
+		tw.Flush()
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package redpanda
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/spf13/afero"
+)
+
+type checkReport struct {
+	Passed bool          `json:"passed"`
+	Checks []checkResult `json:"checks"`
+}
+
+type checkResult struct {
+	Condition   string              `json:"condition"`
+	Required    string              `json:"required"`
+	Current     string              `json:"current"`
+	Severity    string              `json:"severity"`
+	Passed      bool                `json:"passed"`
+	Error       string              `json:"error,omitempty"`
+	Remediation *tuners.Remediation `json:"remediation,omitempty"`
+}
+
+func newCheckReport(results []tuners.CheckResult) checkReport {
+	report := checkReport{Passed: true, Checks: []checkResult{}}
+	for _, r := range results {
+		c := checkResult{
+			Condition: r.Desc,
+			Required:  r.Required,
+			Current:   r.Current,
+			Severity:  r.Severity.String(),
+			Passed:    r.IsOk,
+		}
+		if r.Err != nil {
+			c.Error = r.Err.Error()
+		}
+		if !r.IsOk {
+			remediation := r.Remediation
+			c.Remediation = &remediation
+		}
+		report.Passed = report.Passed && r.IsOk
+		report.Checks = append(report.Checks, c)
+	}
+	return report
+}
+
+// exitCode returns 1 if a Fatal check failed, 2 if only Warning checks
+// failed, and 0 otherwise.
+func (r checkReport) exitCode() int {
+	var code int
+	for _, c := range r.Checks {
+		if c.Passed {
+			continue
+		}
+		if c.Severity == tuners.Severity(tuners.Fatal).String() {
+			return 1
+		}
+		code = 2
+	}
+	return code
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Hostname  string          `xml:"hostname,attr,omitempty"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// newJUnitSuite returns the test suite of the checks of a host, with one test
+// case per check. Failed checks are failures whose type is the severity, and
+// whose text is the remediation. If the host could not be checked, the suite
+// has a single error.
+func newJUnitSuite(hostname string, report *checkReport, hostErr string, now time.Time) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      "rpk redpanda check",
+		Hostname:  hostname,
+		Timestamp: now.UTC().Format("2006-01-02T15:04:05"),
+	}
+	if report == nil {
+		suite.Tests, suite.Errors = 1, 1
+		suite.Cases = []junitTestCase{{
+			Name:      "Run checks",
+			Classname: "redpanda.check",
+			Error:     &junitFailure{Message: hostErr, Type: "Error"},
+		}}
+		return suite
+	}
+	suite.Tests = len(report.Checks)
+	for _, c := range report.Checks {
+		tc := junitTestCase{Name: c.Condition, Classname: "redpanda.check"}
+		if !c.Passed {
+			suite.Failures++
+			msg := fmt.Sprintf("required %s, current %s", c.Required, c.Current)
+			if c.Error != "" {
+				msg += ": " + c.Error
+			}
+			tc.Failure = &junitFailure{Message: msg, Type: c.Severity}
+			if c.Remediation != nil {
+				tc.Failure.Text = c.Remediation.String()
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
+
+func writeJUnitReport(fs afero.Fs, path string, suites ...junitTestSuite) error {
+	raw, err := xml.MarshalIndent(junitTestSuites{Suites: suites}, "", "  ")
+	if err != nil {
+		return err
+	}
+	raw = append([]byte(xml.Header), append(raw, '\n')...)
+	return afero.WriteFile(fs, path, raw, 0o644)
+}
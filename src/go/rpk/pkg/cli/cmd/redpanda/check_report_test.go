@@ -0,0 +1,128 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package redpanda
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	passedCheck = tuners.CheckResult{
+		CheckerID: tuners.ConfigFileChecker,
+		IsOk:      true,
+		Desc:      "Config file valid",
+		Severity:  tuners.Fatal,
+		Required:  "true",
+		Current:   "true",
+	}
+	failedWarning = tuners.CheckResult{
+		CheckerID:   tuners.Swappiness,
+		Desc:        "Swappiness",
+		Severity:    tuners.Warning,
+		Required:    "1",
+		Current:     "60",
+		Remediation: tuners.RemediationFor(tuners.Swappiness),
+	}
+	failedFatal = tuners.CheckResult{
+		CheckerID:   tuners.DataDirAccessChecker,
+		Desc:        "Data directory is writable",
+		Severity:    tuners.Fatal,
+		Required:    "true",
+		Current:     "false",
+		Err:         errors.New("permission denied"),
+		Remediation: tuners.RemediationFor(tuners.DataDirAccessChecker),
+	}
+)
+
+func TestCheckExitCode(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		results []tuners.CheckResult
+		exp     int
+	}{
+		{"no checks", nil, 0},
+		{"all passed", []tuners.CheckResult{passedCheck}, 0},
+		{"warning failed", []tuners.CheckResult{passedCheck, failedWarning}, 2},
+		{"fatal failed", []tuners.CheckResult{failedWarning, failedFatal}, 1},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, newCheckReport(test.results).exitCode())
+		})
+	}
+}
+
+func TestNewCheckReport(t *testing.T) {
+	report := newCheckReport([]tuners.CheckResult{passedCheck, failedWarning, failedFatal})
+	require.False(t, report.Passed)
+	require.Len(t, report.Checks, 3)
+	require.Nil(t, report.Checks[0].Remediation)
+	require.Equal(t, &tuners.Remediation{Tuner: "swappiness"}, report.Checks[1].Remediation)
+	require.Equal(t, "Warning", report.Checks[1].Severity)
+	require.Equal(t, "permission denied", report.Checks[2].Error)
+
+	require.True(t, newCheckReport([]tuners.CheckResult{passedCheck}).Passed)
+}
+
+func TestHostsCheckExitCode(t *testing.T) {
+	passed := newCheckReport([]tuners.CheckResult{passedCheck})
+	warning := newCheckReport([]tuners.CheckResult{failedWarning})
+	fatal := newCheckReport([]tuners.CheckResult{failedFatal})
+	for _, test := range []struct {
+		name    string
+		reports []hostCheckReport
+		exp     int
+	}{
+		{"all passed", []hostCheckReport{{Host: "a", Report: &passed}, {Host: "b", Report: &passed}}, 0},
+		{"warning on one host", []hostCheckReport{{Host: "a", Report: &passed}, {Host: "b", Report: &warning}}, 2},
+		{"fatal on one host", []hostCheckReport{{Host: "a", Report: &warning}, {Host: "b", Report: &fatal}}, 1},
+		{"host error", []hostCheckReport{{Host: "a", Report: &passed}, {Host: "b", Error: "unable to connect"}}, 1},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, hostsCheckExitCode(test.reports))
+		})
+	}
+}
+
+func TestJUnitReport(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	now := time.Date(2022, 9, 1, 12, 30, 0, 0, time.UTC)
+	report := newCheckReport([]tuners.CheckResult{passedCheck, failedWarning})
+	err := writeJUnitReport(fs, "/report.xml",
+		newJUnitSuite("node-0", &report, "", now),
+		newJUnitSuite("node-1", nil, "unable to connect", now),
+	)
+	require.NoError(t, err)
+	raw, err := afero.ReadFile(fs, "/report.xml")
+	require.NoError(t, err)
+	exp := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="rpk redpanda check" hostname="node-0" timestamp="2022-09-01T12:30:00" tests="2" failures="1" errors="0">
+    <testcase name="Config file valid" classname="redpanda.check"></testcase>
+    <testcase name="Swappiness" classname="redpanda.check">
+      <failure message="required 1, current 60" type="Warning">rpk redpanda tune swappiness</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="rpk redpanda check" hostname="node-1" timestamp="2022-09-01T12:30:00" tests="1" failures="0" errors="1">
+    <testcase name="Run checks" classname="redpanda.check">
+      <error message="unable to connect" type="Error"></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	require.Equal(t, exp, string(raw))
+}
@@ -83,7 +83,7 @@ The json format can be used to set values as json:
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 			cfg = cfg.FileOrDefaults() // we set fields in the raw file without writing env / flag overrides
 
@@ -121,7 +121,7 @@ func bootstrap(fs afero.Fs) *cobra.Command {
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 			cfg = cfg.FileOrDefaults() // we modify fields in the raw file without writing env / flag overrides
 
@@ -187,7 +187,7 @@ func initNode(fs afero.Fs) *cobra.Command {
 		Args:  cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 			cfg = cfg.FileOrDefaults() // we modify fields in the raw file without writing env / flag overrides
 
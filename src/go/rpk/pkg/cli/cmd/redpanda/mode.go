@@ -52,7 +52,7 @@ func NewModeCommand(fs afero.Fs) *cobra.Command {
 
 func executeMode(fs afero.Fs, cmd *cobra.Command, mode string) error {
 	p := config.ParamsFromCommand(cmd)
-	cfg, err := p.Load(fs)
+	cfg, err := p.LoadWithoutProfile(fs)
 	if err != nil {
 		return fmt.Errorf("unable to load config: %v", err)
 	}
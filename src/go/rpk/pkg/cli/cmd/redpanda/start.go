@@ -28,6 +28,7 @@ import (
 	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
 	rp "github.com/redpanda-data/redpanda/src/go/rpk/pkg/redpanda"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/factory"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/iotune"
@@ -149,7 +150,7 @@ func NewStartCommand(fs afero.Fs, launcher rp.Launcher) *cobra.Command {
 			// blows up when there's a JSON object.
 			configKvs, filteredArgs := parseConfigKvs(os.Args)
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			if err != nil {
 				return fmt.Errorf("unable to load config file: %s", err)
 			}
@@ -712,7 +713,18 @@ func tuneAll(
 	fs afero.Fs, cpuSet string, conf *config.Config, timeout time.Duration,
 ) error {
 	params := &factory.TunerParams{}
-	tunerFactory := factory.NewDirectExecutorTunersFactory(fs, *conf, timeout)
+	// A bad state file must not keep redpanda from starting tuned, so we
+	// only warn and tune without recording what 'rpk redpanda tune
+	// --revert' would restore.
+	var executor executors.Executor = executors.NewDirectExecutor()
+	recorder, err := executors.NewRecordingExecutor(fs, executors.DefaultStateFile, executor)
+	if err != nil {
+		log.Warnf("Unable to read tuner state file, tuning without recording the prior values: %v", err)
+	} else {
+		recorder.SetBestEffort()
+		executor = recorder
+	}
+	tunerFactory := factory.NewTunersFactory(fs, *conf, executor, timeout)
 	hw := hwloc.NewHwLocCmd(vos.NewProc(), timeout)
 	if cpuSet == "" {
 		cpuMask, err := hw.All()
@@ -728,7 +740,7 @@ func tuneAll(
 		params.CPUMask = cpuMask
 	}
 
-	err := factory.FillTunerParamsWithValuesFromConfig(params, conf)
+	err = factory.FillTunerParamsWithValuesFromConfig(params, conf)
 	if err != nil {
 		return err
 	}
@@ -748,6 +760,9 @@ func tuneAll(
 			continue
 		}
 		log.Debugf("Tuner parameters %+v", params)
+		if recorder != nil {
+			recorder.SetTuner(tunerName)
+		}
 		result := tuner.Tune()
 		if result.IsFailed() {
 			return result.Error()
@@ -766,6 +781,17 @@ func checkFailedActions(
 			// Do not set --lock-memory flag when swap is disabled
 			args.SeastarFlags[lockMemoryFlag] = "false"
 		},
+		tuners.CPUSetNumaChecker: func(result *tuners.CheckResult) {
+			// The checker only knows the --cpuset set in
+			// rpk.additional_start_flags, so we check again with the
+			// one redpanda is started with.
+			cpuset := args.SeastarFlags[cpuSetFlag]
+			if cpuset == "" {
+				return
+			}
+			result.Current = cpuset
+			result.IsOk, _ = tuners.CPUSetWithin(cpuset, result.Required)
+		},
 	}
 }
 
@@ -784,6 +810,9 @@ func check(
 			if action, exists := checkFailedActions[result.CheckerID]; exists {
 				action(&result)
 			}
+			if result.IsOk {
+				continue
+			}
 			msg := fmt.Sprintf("System check '%s' failed. Required: %v, Current %v",
 				result.Desc, result.Required, result.Current)
 			if result.Severity == tuners.Fatal {
@@ -1540,6 +1540,37 @@ func TestStartCommand(t *testing.T) {
 	}
 }
 
+func TestStartIgnoresProfiles(t *testing.T) {
+	const profilesYAML = `current_profile: prod
+profiles:
+    - name: prod
+      kafka_api:
+        brokers:
+            - prod:9092
+        sasl:
+            user: produser
+            password: prodpass
+            type: SCRAM-SHA-256
+`
+	t.Setenv("XDG_CONFIG_HOME", "/home/rpk/.config")
+	profilesPath, err := config.DefaultProfilesPath()
+	require.NoError(t, err)
+
+	for _, profiles := range []string{profilesYAML, "^&notyaml"} {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, config.Default().Write(fs))
+		require.NoError(t, afero.WriteFile(fs, profilesPath, []byte(profiles), 0o600))
+
+		c := NewStartCommand(fs, &noopLauncher{})
+		c.SetArgs([]string{"--check=false", "--install-dir", "/var/lib/redpanda"})
+		require.NoError(t, c.Execute())
+
+		file, err := afero.ReadFile(fs, config.DefaultPath)
+		require.NoError(t, err)
+		require.NotContains(t, string(file), "prod")
+	}
+}
+
 func TestExtraFlags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -41,7 +41,7 @@ hasn't stopped, it sends SIGTERM. Lastly, it sends SIGKILL if it's still
 running.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
 			err = executeStop(fs, cfg, timeout)
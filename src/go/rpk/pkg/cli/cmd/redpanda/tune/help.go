@@ -102,13 +102,21 @@ Modes description:
 		them all to CPU0. In this mode RPS is always enabled to
 		spread NAPIs' handling between all CPUs.
 
+	numa - like mq, but distribute NIC's IRQs only among the CPUs of
+		the NUMA node the NIC is attached to. This mode is never
+		chosen by default.
+
 If there isn't any mode given script will use a default mode:
 
 	- If number of physical CPU cores per Rx HW queue
 	  is greater than 4 - use the 'sq-split' mode.
 	- Otherwise, if number of hyper-threads per Rx HW queue
 	  is greater than 4 - use the 'sq' mode.
-	- Otherwise use the 'mq' mode.`
+	- Otherwise use the 'mq' mode.
+
+If --mode is not given, the mode is rpk.irq_mode in the config file, which
+is also the mode that 'rpk redpanda start' tunes with and that
+'rpk redpanda check' checks the IRQs against.`
 
 const diskSchedulerTunerHelp = `
 This tuner sets the preferred I/O scheduler for given block devices and disables
@@ -149,6 +157,10 @@ Modes description:
 
 	mq - distribute all devices IRQs across all available CPUs
 
+	numa - distribute each device IRQs across the available CPUs
+		of the NUMA node the device is attached to. This mode is
+		never chosen by default.
+
 If there isn't any mode given script will use a default mode:
 
 	- If there are no non-NVME devices use ‘mq’ mode
@@ -156,7 +168,11 @@ If there isn't any mode given script will use a default mode:
 	  is lower than 4 - use the ‘mq’ mode
 	- Otherwise, if number of physical CPU cores
 	  is lower than 4 - use the 'sq' mode.
-	- Otherwise use the ‘sq-split’ mode.`
+	- Otherwise use the ‘sq-split’ mode.
+
+If --mode is not given, the mode is rpk.irq_mode in the config file, which
+is also the mode that 'rpk redpanda start' tunes with and that
+'rpk redpanda check' checks the IRQs against.`
 
 const swappinessTunerHelp = `
 Tunes the kernel to keep process data in-memory for as long as possible, instead
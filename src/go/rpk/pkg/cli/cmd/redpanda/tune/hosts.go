@@ -0,0 +1,134 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tune
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/remote"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type hostTuneReport struct {
+	Host     string      `json:"host"`
+	ExitCode int         `json:"exit_code"`
+	Error    string      `json:"error,omitempty"`
+	Report   *tuneReport `json:"report,omitempty"`
+}
+
+// tuneHosts runs the tune command on every host, returning whether any host
+// could not be tuned or exited non-zero.
+func tuneHosts(
+	fs afero.Fs,
+	cmd *cobra.Command,
+	args []string,
+	cfg *remote.Config,
+	format out.Format,
+) (bool, error) {
+	results, err := cfg.Run(fs, remote.Args(cmd, args))
+	if err != nil {
+		return false, err
+	}
+	var exit1 bool
+	reports := make([]hostTuneReport, 0, len(results))
+	for _, r := range results {
+		h := hostTuneReport{Host: r.Host, ExitCode: r.ExitCode}
+		var report tuneReport
+		if err := r.Decode(&report); err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Report = &report
+		}
+		exit1 = exit1 || h.Report == nil || h.ExitCode != 0
+		reports = append(reports, h)
+	}
+	format.Print(reports, func() {
+		printHostTuneReports(cfg.Hosts, reports)
+	})
+	return exit1, nil
+}
+
+// tuneStatus returns the status of a tuner on a host, for the drift table.
+func tuneStatus(r result) string {
+	switch {
+	case !r.Enabled:
+		return "disabled"
+	case !r.Supported:
+		return "unsupported"
+	case r.ErrMsg != "":
+		return "failed"
+	case r.Applied:
+		return "applied"
+	default:
+		return "not applied"
+	}
+}
+
+func printHostTuneReports(hosts []string, reports []hostTuneReport) {
+	var (
+		names    []string
+		statuses = make(map[string]map[string]string)
+		errs     [][]interface{}
+		reboot   []string
+		hostErrs []hostTuneReport
+	)
+	for _, h := range reports {
+		if h.Report == nil {
+			hostErrs = append(hostErrs, h)
+			continue
+		}
+		if h.Report.RebootRequired {
+			reboot = append(reboot, h.Host)
+		}
+		for _, r := range h.Report.Tuners {
+			if _, ok := statuses[r.Name]; !ok {
+				statuses[r.Name] = make(map[string]string)
+				names = append(names, r.Name)
+			}
+			statuses[r.Name][h.Host] = tuneStatus(r)
+			if r.ErrMsg != "" {
+				errs = append(errs, []interface{}{"  " + h.Host + ":", r.Name, r.ErrMsg})
+			}
+		}
+	}
+	sort.Strings(names)
+
+	t := remote.NewDriftTable(hosts, "tuner")
+	for _, name := range names {
+		t.Add([]string{name}, statuses[name])
+	}
+	t.Print()
+
+	if len(errs) > 0 {
+		fmt.Printf("\nTuner errors:\n")
+		tw := out.NewTabWriter()
+		for _, e := range errs {
+			tw.Print(e...)
+		}
+		tw.Flush()
+	}
+	if len(reboot) > 0 {
+		fmt.Printf("\nReboot required on: %s\n", strings.Join(reboot, ", "))
+	}
+	if len(hostErrs) > 0 {
+		fmt.Printf("\nUnable to tune hosts:\n")
+		tw := out.NewTabWriter()
+		for _, h := range hostErrs {
+			tw.Print("  "+h.Host+":", h.Error)
+		}
+		tw.Flush()
+	}
+}
@@ -51,7 +51,7 @@ You may use 'rpk redpanda config set' to enable or disable a tuner.
 		Args: cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
 			p := config.ParamsFromCommand(cmd)
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
 			// Using cpu mask and timeout defaults since we are not executing
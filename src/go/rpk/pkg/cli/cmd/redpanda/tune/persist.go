@@ -0,0 +1,99 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tune
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/spf13/pflag"
+)
+
+// persistTuner is the name that the writes of persisted files are recorded
+// under in the state file, such that they can be reverted.
+const persistTuner = "persist"
+
+// persistedTuneCommand returns the command that the persisted systemd unit
+// re-runs tuners with: this rpk binary, with the flags that select what the
+// tuners tune.
+func persistedTuneCommand(flags *pflag.FlagSet) []string {
+	rpk, err := os.Executable()
+	if err != nil {
+		rpk = executors.DefaultRpkPath
+	}
+	args := []string{rpk, "redpanda", "tune"}
+	for _, name := range []string{config.FlagConfig, "cpu-set", "mode", "disks", "nic", "dirs"} {
+		f := flags.Lookup(name)
+		if f == nil || !f.Changed {
+			continue
+		}
+		value := f.Value.String()
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			value = strings.Join(s.GetSlice(), ",")
+		}
+		args = append(args, "--"+name+"="+value)
+	}
+	return args
+}
+
+// persistTuning writes the files that persist the tuning, or only renders
+// them if dry is true.
+func persistTuning(
+	persister *executors.PersistingExecutor,
+	recorder *executors.RecordingExecutor,
+	dry bool,
+) ([]executors.PersistedFile, error) {
+	if dry {
+		files, err := persister.Files()
+		if err != nil {
+			return nil, fmt.Errorf("unable to render persisted tuning: %v", err)
+		}
+		return files, nil
+	}
+	recorder.SetTuner(persistTuner)
+	files, err := persister.Persist()
+	if err != nil {
+		return nil, fmt.Errorf("unable to persist tuning: %v", err)
+	}
+	return files, nil
+}
+
+func printPersisted(files []executors.PersistedFile, dry bool) {
+	if len(files) == 0 {
+		fmt.Println("\nNo tuning to persist.")
+		return
+	}
+	if dry {
+		for _, f := range files {
+			if !f.Changed {
+				fmt.Printf("\n%s is up to date.\n", f.Path)
+				continue
+			}
+			fmt.Printf("\nWould write %s:\n\n%s", f.Path, f.Content)
+		}
+		return
+	}
+	fmt.Println()
+	tw := out.NewTable("persisted file", "status")
+	defer tw.Flush()
+	for _, f := range files {
+		status := "unchanged"
+		if f.Changed {
+			status = "written"
+		}
+		tw.Print(f.Path, status)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tune
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/spf13/afero"
+)
+
+// revertTuners restores the values recorded for the tuners, or for all tuners
+// if none are given. With a script file, the revert commands are rendered
+// to the script and the state file is not modified; otherwise, reverted
+// entries are removed from the state file, and entries that failed to
+// revert are kept.
+func revertTuners(
+	fs afero.Fs, stateFile string, tuners []string, scriptFile string,
+) (bool, error) {
+	state, err := executors.ReadTuneState(fs, stateFile)
+	if err != nil {
+		return false, fmt.Errorf("unable to read tuner state file: %v", err)
+	}
+	entries := state.Take(tuners)
+	defer func() {
+		for _, e := range state.Entries {
+			if e.Tuner == persistTuner {
+				fmt.Printf("\nThe tuning persisted with --persist still applies at boot; run 'rpk redpanda tune --revert %s' to remove it.\n", persistTuner)
+				return
+			}
+		}
+	}()
+	if len(entries) == 0 {
+		fmt.Println("No recorded tuning to revert.")
+		return false, nil
+	}
+
+	executor := executors.NewDirectExecutor()
+	if scriptFile != "" {
+		executor = executors.NewScriptRenderingExecutor(fs, scriptFile)
+	}
+
+	var (
+		exit1  bool
+		failed []executors.StateEntry
+	)
+	tw := out.NewTable("tuner", "kind", "key", "value", "status")
+	for _, e := range entries {
+		status := "reverted"
+		if scriptFile != "" {
+			status = "rendered"
+		}
+		cmd, err := commands.NewRevertCmd(fs, e.Prior)
+		if err == nil {
+			err = executor.Execute(cmd)
+		}
+		switch {
+		case errors.Is(err, commands.ErrNotRevertible):
+			status = "not revertible"
+		case err != nil:
+			status = err.Error()
+			exit1 = true
+			failed = append(failed, e)
+		}
+		tw.Print(e.Tuner, e.Prior.Kind, e.Prior.Key, priorString(e.Prior), status)
+	}
+	tw.Flush()
+
+	if scriptFile != "" {
+		return exit1, nil
+	}
+	// Failed entries are kept, in their original (oldest first) order.
+	for i := len(failed) - 1; i >= 0; i-- {
+		state.Entries = append(state.Entries, failed[i])
+	}
+	if err := state.Write(fs, stateFile); err != nil {
+		return exit1, fmt.Errorf("unable to write tuner state file: %v", err)
+	}
+	return exit1, nil
+}
+
+// priorString returns the value that reverting restores.
+func priorString(v commands.PriorValue) string {
+	switch v.Kind {
+	case commands.PriorFile:
+		if !v.Exists {
+			return "(removed)"
+		}
+		if strings.Contains(v.Value, "\n") {
+			return fmt.Sprintf("(%d bytes)", len(v.Value))
+		}
+		return v.Value
+	case commands.PriorFileSize:
+		return fmt.Sprintf("%d B", v.Size)
+	case commands.PriorEthtool:
+		var features []string
+		for f, on := range v.Features {
+			state := "off"
+			if on {
+				state = "on"
+			}
+			features = append(features, f+"="+state)
+		}
+		sort.Strings(features)
+		return strings.Join(features, " ")
+	case commands.PriorSystemdUnit:
+		if v.Exists {
+			return "enabled"
+		}
+		return "disabled"
+	case commands.PriorNone:
+		return ""
+	default:
+		return v.Value
+	}
+}
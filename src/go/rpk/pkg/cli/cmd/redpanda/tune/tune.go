@@ -23,7 +23,10 @@ import (
 	"github.com/fatih/color"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/ui"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/remote"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/factory"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
 	log "github.com/sirupsen/logrus"
@@ -32,11 +35,18 @@ import (
 )
 
 type result struct {
-	name      string
-	applied   bool
-	enabled   bool
-	supported bool
-	errMsg    string
+	Name      string `json:"name"`
+	Applied   bool   `json:"applied"`
+	Enabled   bool   `json:"enabled"`
+	Supported bool   `json:"supported"`
+	ErrMsg    string `json:"error,omitempty"`
+}
+
+type tuneReport struct {
+	Tuners         []result                  `json:"tuners"`
+	AllDisabled    bool                      `json:"all_disabled"`
+	RebootRequired bool                      `json:"reboot_required"`
+	Persisted      []executors.PersistedFile `json:"persisted,omitempty"`
 }
 
 func NewCommand(fs afero.Fs) *cobra.Command {
@@ -44,8 +54,15 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 	var (
 		configFile        string
 		outTuneScriptFile string
+		outUndoScriptFile string
+		stateFile         string
+		revert            bool
+		persist           bool
+		dry               bool
 		cpuSet            string
 		timeout           time.Duration
+		format            out.Format
+		remoteCfg         remote.Config
 	)
 	baseMsg := "Sets the OS parameters to tune system performance"
 	longMsg := fmt.Sprintf(`Sets the OS parameters to tune system performance.
@@ -56,7 +73,44 @@ Available tuners:
   - %s
 
 To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
-`, strings.Join(factory.AvailableTuners(), "\n  - "))
+
+Before tuners change anything, the current values are recorded to a state
+file (--state-file). Running 'rpk redpanda tune --revert <tuners>' restores
+the recorded values of the given tuners, or of every tuner with 'all', and
+removes them from the state file. Changes that cannot be reverted, such as
+running a command, are reported. With --output-script, reverting renders the
+revert commands to the script instead, and leaves the state file as is.
+
+With --output-script, --output-undo-script renders a second script that
+reverts the values changed by the tuning script to their current values.
+
+Most tuners change kernel state that is lost on reboot. With --persist, the
+changes are also written to files that apply them at boot:
+
+  - sysctl values go to %s,
+  - disk scheduler, nomerges and write cache settings go to udev rules in
+    %s,
+  - other kernel values and NIC features go to the oneshot systemd unit
+    %s, which is enabled,
+  - IRQ affinity is persisted by having the systemd unit re-run the tuner
+    that set it, with the same --config, --cpu-set, --mode, --disks, --nic
+    and --dirs flags, because IRQ numbers change across reboots.
+
+Every command of the systemd unit may fail without failing the others. Values
+persisted by previous runs are kept unless they are changed, so persisting is
+idempotent and can be done tuner by tuner. Use --dry to print the files
+without changing anything.
+
+The writes of these files are recorded as the 'persist' tuner. Reverting
+other tuners does not change the persisted files, so their values still apply
+at the next boot: only 'rpk redpanda tune --revert persist', or '--revert
+all', removes them.
+
+With --hosts, the tuners run on every host over SSH, by running the same rpk
+command there, and the results are aggregated into one table with a column
+per host, which shows the tuners whose results drift across the hosts.
+`, strings.Join(factory.AvailableTuners(), "\n  - "),
+		executors.SysctlDropInFile, executors.UdevRulesFile, executors.PersistUnitName)
 	command := &cobra.Command{
 		Use:   "tune <list of elements to tune>",
 		Short: baseMsg,
@@ -70,7 +124,7 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 			}
 
 			for _, toTune := range strings.Split(args[0], ",") {
-				if !factory.IsTunerAvailable(toTune) {
+				if !factory.IsTunerAvailable(toTune) && !(revert && toTune == persistTuner) {
 					return fmt.Errorf("invalid element to tune '%s' "+
 						"only %s are supported",
 						args[0], factory.AvailableTuners())
@@ -82,6 +136,17 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 			if !tunerParamsEmpty(&tunerParams) && configFile != "" {
 				out.Die("use either tuner params or redpanda config file")
 			}
+			if len(remoteCfg.Hosts) > 0 {
+				if revert || outTuneScriptFile != "" {
+					out.Die("--hosts cannot be used with --revert or --output-script")
+				}
+				exit1, err := tuneHosts(fs, cmd, args, &remoteCfg, format)
+				out.MaybeDie(err, "unable to tune hosts: %v", err)
+				if exit1 {
+					os.Exit(1)
+				}
+				return
+			}
 			var tuners []string
 			p := config.ParamsFromCommand(cmd)
 			if args[0] == "all" {
@@ -93,18 +158,72 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 			out.MaybeDieErr(err)
 
 			tunerParams.CPUMask = cpuMask
-			cfg, err := p.Load(fs)
+			cfg, err := p.LoadWithoutProfile(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
-			var tunerFactory factory.TunersFactory
-			if outTuneScriptFile != "" {
+			if outUndoScriptFile != "" && (outTuneScriptFile == "" || revert) {
+				out.Die("--output-undo-script requires --output-script, and cannot be used with --revert")
+			}
+			if dry && !persist {
+				out.Die("--dry requires --persist")
+			}
+			if persist && (outTuneScriptFile != "" || revert) {
+				out.Die("--persist cannot be used with --output-script or --revert")
+			}
+			if revert {
+				if args[0] == "all" {
+					tuners = nil
+				}
+				exit1, err := revertTuners(fs, stateFile, tuners, outTuneScriptFile)
+				out.MaybeDieErr(err)
+				if exit1 {
+					os.Exit(1)
+				}
+				return
+			}
+
+			var (
+				tunerFactory factory.TunersFactory
+				recorder     *executors.RecordingExecutor
+				persister    *executors.PersistingExecutor
+				tunerSetter  executors.TunerSetter
+			)
+			switch {
+			case outUndoScriptFile != "":
+				executor := executors.NewUndoScriptRenderingExecutor(fs, outTuneScriptFile, outUndoScriptFile)
+				tunerFactory = factory.NewTunersFactory(fs, *cfg, executor, timeout)
+			case outTuneScriptFile != "":
 				tunerFactory = factory.NewScriptRenderingTunersFactory(
 					fs, *cfg, outTuneScriptFile, timeout)
-			} else {
-				tunerFactory = factory.NewDirectExecutorTunersFactory(
-					fs, *cfg, timeout)
+			case dry:
+				persister = executors.NewPersistingExecutor(fs, nil, vos.NewProc(), timeout)
+				persister.SetTuneCommand(persistedTuneCommand(cmd.Flags()))
+				tunerFactory = factory.NewTunersFactory(fs, *cfg, persister, timeout)
+				tunerSetter = persister
+			default:
+				recorder, err = executors.NewRecordingExecutor(fs, stateFile, executors.NewDirectExecutor())
+				out.MaybeDie(err, "unable to read tuner state file: %v", err)
+				var executor executors.Executor = recorder
+				tunerSetter = recorder
+				if persist {
+					persister = executors.NewPersistingExecutor(fs, recorder, vos.NewProc(), timeout)
+					persister.SetTuneCommand(persistedTuneCommand(cmd.Flags()))
+					executor = persister
+					tunerSetter = persister
+				}
+				tunerFactory = factory.NewTunersFactory(fs, *cfg, executor, timeout)
 			}
-			exit1, err := tune(cfg, tuners, tunerFactory, &tunerParams)
+			report, exit1, err := tune(cfg, tuners, tunerFactory, &tunerParams, tunerSetter)
 			out.MaybeDieErr(err)
+			if persister != nil {
+				report.Persisted, err = persistTuning(persister, recorder, dry)
+				out.MaybeDieErr(err)
+			}
+			format.Print(report, func() {
+				printTuneReport(report, tuners)
+				if persister != nil {
+					printPersisted(report.Persisted, dry)
+				}
+			})
 			if exit1 {
 				os.Exit(1)
 			}
@@ -125,6 +244,26 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 		"output-script",
 		"",
 		"If set tuners will generate tuning file that can later be used to tune the system")
+	command.Flags().StringVar(&outUndoScriptFile,
+		"output-undo-script",
+		"",
+		"If set with --output-script, tuners will also generate a file that reverts the tuning script")
+	command.Flags().StringVar(&stateFile,
+		"state-file",
+		executors.DefaultStateFile,
+		"File that records the values changed by tuners, which --revert restores")
+	command.Flags().BoolVar(&revert,
+		"revert",
+		false,
+		"Restore the values recorded before the given tuners changed them")
+	command.Flags().BoolVar(&persist,
+		"persist",
+		false,
+		"Also write sysctl.d, udev and systemd files that apply the tuning at boot")
+	command.Flags().BoolVar(&dry,
+		"dry",
+		false,
+		"With --persist, print the files that would be written without changing anything")
 	command.Flags().DurationVar(
 		&timeout,
 		"timeout",
@@ -143,6 +282,9 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 	)
 	command.Flags().MarkDeprecated("interactive", "not needed: tune will use default configuration if config file is not found.")
 
+	out.AddFormatFlag(command, &format)
+	remote.AddFlags(command, &remoteCfg)
+
 	command.AddCommand(newHelpCommand())
 	command.AddCommand(newListCommand(fs))
 	return command
@@ -151,7 +293,7 @@ To learn more about a tuner, run 'rpk redpanda tune help <tuner name>'.
 func addTunerParamsFlags(command *cobra.Command, tunerParams *factory.TunerParams) {
 	command.Flags().StringVarP(&tunerParams.Mode,
 		"mode", "m", "",
-		"Operation Mode: one of: [sq, sq_split, mq]")
+		"Operation Mode: one of: [sq, sq_split, mq, numa], defaults to rpk.irq_mode")
 	command.Flags().StringSliceVarP(&tunerParams.Disks,
 		"disks", "d",
 		[]string{}, "Lists of devices to tune f.e. 'sda1'")
@@ -173,25 +315,22 @@ func tune(
 	tunerNames []string,
 	tunersFactory factory.TunersFactory,
 	params *factory.TunerParams,
-) (bool, error) {
+	tunerSetter executors.TunerSetter,
+) (tuneReport, bool, error) {
+	report := tuneReport{Tuners: []result{}, AllDisabled: true}
 	params, err := factory.MergeTunerParamsConfig(params, conf)
 	if err != nil {
-		return false, err
+		return report, false, err
 	}
-	var (
-		rebootRequired, includeErr, exit1 bool
-		results                           []result
-		allDisabled                       = true
-	)
+	var exit1 bool
 
 	for _, tunerName := range tunerNames {
 		enabled := factory.IsTunerEnabled(tunerName, conf.Rpk)
-		allDisabled = allDisabled && !enabled
+		report.AllDisabled = report.AllDisabled && !enabled
 		tuner := tunersFactory.CreateTuner(tunerName, params)
 		supported, reason := tuner.CheckIfSupported()
 		if !enabled || !supported {
-			includeErr = includeErr || !supported
-			results = append(results, result{tunerName, false, enabled, supported, reason})
+			report.Tuners = append(report.Tuners, result{tunerName, false, enabled, supported, reason})
 			// We exit with code 1 when it's enabled and not supported except
 			// for disk_write_cache since it's only supported for GCP.
 			// We also allow clocksource to fail, see #6444.
@@ -199,24 +338,36 @@ func tune(
 			continue
 		}
 		log.Debugf("Tuner parameters %+v", params)
+		if tunerSetter != nil {
+			tunerSetter.SetTuner(tunerName)
+		}
 		res := tuner.Tune()
-		includeErr = includeErr || res.IsFailed()
-		rebootRequired = rebootRequired || res.IsRebootRequired()
+		report.RebootRequired = report.RebootRequired || res.IsRebootRequired()
 		errMsg := ""
 		if res.IsFailed() {
 			errMsg = res.Error().Error()
 			exit1 = true
 		}
-		results = append(results, result{tunerName, !res.IsFailed(), enabled, supported, errMsg})
+		report.Tuners = append(report.Tuners, result{tunerName, !res.IsFailed(), enabled, supported, errMsg})
 	}
+	sort.Slice(report.Tuners, func(i, j int) bool {
+		return report.Tuners[i].Name < report.Tuners[j].Name
+	})
+	return report, exit1, nil
+}
 
-	if allDisabled {
+func printTuneReport(report tuneReport, tunerNames []string) {
+	if report.AllDisabled {
 		fmt.Println("All tuners were disabled, so none were applied. You may run `rpk redpanda mode prod` to enable the recommended set of tuners for non-containerized production use.")
 	}
 
-	printTuneResult(results, includeErr)
+	var includeErr bool
+	for _, res := range report.Tuners {
+		includeErr = includeErr || !res.Supported || res.ErrMsg != ""
+	}
+	printTuneResult(report.Tuners, includeErr)
 
-	if rebootRequired {
+	if report.RebootRequired {
 		red := color.New(color.FgRed).SprintFunc()
 		fmt.Printf(
 			"%s: Reboot system and run 'rpk tune %s' again\n",
@@ -224,7 +375,6 @@ func tune(
 			strings.Join(tunerNames, ","),
 		)
 	}
-	return exit1, nil
 }
 
 func tunerParamsEmpty(params *factory.TunerParams) bool {
@@ -234,9 +384,6 @@ func tunerParamsEmpty(params *factory.TunerParams) bool {
 }
 
 func printTuneResult(results []result, includeErr bool) {
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].name < results[j].name
-	})
 	headers := []string{
 		"Tuner",
 		"Applied",
@@ -257,19 +404,19 @@ func printTuneResult(results []result, includeErr bool) {
 	for _, res := range results {
 		c := white
 		row := []string{
-			res.name,
-			strconv.FormatBool(res.applied),
-			strconv.FormatBool(res.enabled),
-			strconv.FormatBool(res.supported),
+			res.Name,
+			strconv.FormatBool(res.Applied),
+			strconv.FormatBool(res.Enabled),
+			strconv.FormatBool(res.Supported),
 		}
 		if includeErr {
-			row = append(row, res.errMsg)
+			row = append(row, res.ErrMsg)
 		}
-		if !res.supported {
+		if !res.Supported {
 			c = yellow
-		} else if res.errMsg != "" {
+		} else if res.ErrMsg != "" {
 			c = red
-		} else if res.applied {
+		} else if res.Applied {
 			c = green
 		}
 		t.Append(colorRow(c, row))
@@ -0,0 +1,151 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newCompatibilityCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "compatibility",
+		Aliases: []string{"compat"},
+		Short:   "Get, set, or check schema compatibility",
+		Long: `Get, set, or check schema compatibility.
+
+The compatibility level of a subject determines which schema changes are
+allowed when registering a new version of a schema under the subject. Subjects
+without their own compatibility level use the global level.
+
+Supported levels: ` + strings.Join(schemaregistry.CompatibilityLevels, ", ") + `.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newCompatibilityGetCommand(fs),
+		newCompatibilitySetCommand(fs),
+		newCompatibilityCheckCommand(fs),
+	)
+	return cmd
+}
+
+func newCompatibilityGetCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [SUBJECTS...]",
+		Short: "Get the global or per-subject compatibility level",
+		Long: `Get the global or per-subject compatibility level.
+
+If no subjects are specified, this prints the global compatibility level.
+Subjects without their own level print the global level.
+`,
+		Run: func(cmd *cobra.Command, subjects []string) {
+			cl := newClient(fs, cmd)
+			tw := out.NewTable("subject", "level", "error")
+			defer tw.Flush()
+			for _, s := range subjectsOrGlobal(subjects) {
+				level, err := cl.Compatibility(cmd.Context(), s)
+				tw.Print(subjectName(s), level, errString(err))
+			}
+		},
+	}
+}
+
+func newCompatibilitySetCommand(fs afero.Fs) *cobra.Command {
+	var level string
+	cmd := &cobra.Command{
+		Use:   "set [SUBJECTS...] --level [LEVEL]",
+		Short: "Set the global or per-subject compatibility level",
+		Long: `Set the global or per-subject compatibility level.
+
+If no subjects are specified, this sets the global compatibility level.
+`,
+		Run: func(cmd *cobra.Command, subjects []string) {
+			cl := newClient(fs, cmd)
+			tw := out.NewTable("subject", "level", "error")
+			defer tw.Flush()
+			for _, s := range subjectsOrGlobal(subjects) {
+				set, err := cl.SetCompatibility(cmd.Context(), s, level)
+				tw.Print(subjectName(s), set, errString(err))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&level, "level", "", "Compatibility level to set (required)")
+	cmd.MarkFlagRequired("level")
+	return cmd
+}
+
+func newCompatibilityCheckCommand(fs afero.Fs) *cobra.Command {
+	var (
+		file       string
+		schemaType string
+		references []string
+		version    string
+	)
+	cmd := &cobra.Command{
+		Use:   "check [SUBJECT] --schema [FILE]",
+		Short: "Check if a schema is compatible with a subject's schema",
+		Long: `Check if a schema is compatible with a subject's schema.
+
+This checks the schema in the given file against the schema registered under
+the subject at --schema-version (by default, the latest version), using the
+subject's compatibility level. Nothing is registered.
+
+If the schema is incompatible, this exits with status 1.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			subject := args[0]
+			s, err := readSchema(fs, file, schemaType, references)
+			out.MaybeDieErr(err)
+
+			cl := newClient(fs, cmd)
+			compatible, err := cl.CheckCompatibility(cmd.Context(), subject, version, s)
+			out.MaybeDie(err, "unable to check compatibility: %v", err)
+			if !compatible {
+				out.Die("Schema is not compatible with version %s of subject %q.", version, subject)
+			}
+			fmt.Printf("Schema is compatible with version %s of subject %q.\n", version, subject)
+		},
+	}
+	cmd.Flags().StringVar(&file, "schema", "", "Schema file to check (required)")
+	cmd.Flags().StringVar(&schemaType, "type", "", "Schema type (avro, protobuf, json); overrides the file extension")
+	cmd.Flags().StringSliceVar(&references, "references", nil, "Comma-separated list of references to other schemas (NAME:SUBJECT:VERSION)")
+	cmd.Flags().StringVar(&version, "schema-version", schemaregistry.VersionLatest, "Version of the subject's schema to check against (a number or \"latest\")")
+	cmd.MarkFlagRequired("schema")
+	return cmd
+}
+
+// An empty subject refers to the global configuration in the client.
+func subjectsOrGlobal(subjects []string) []string {
+	if len(subjects) == 0 {
+		return []string{""}
+	}
+	return subjects
+}
+
+func subjectName(s string) string {
+	if s == "" {
+		return "{GLOBAL}"
+	}
+	return s
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
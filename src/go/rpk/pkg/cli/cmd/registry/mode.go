@@ -0,0 +1,76 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newModeCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mode",
+		Short: "Get or set the schema registry mode",
+		Long: `Get or set the schema registry mode.
+
+The mode controls whether schemas can be registered: READWRITE allows
+registering schemas, READONLY rejects all writes, and IMPORT allows registering
+schemas with explicit IDs (for migrating from another registry).
+
+Supported modes: ` + strings.Join(schemaregistry.Modes, ", ") + `.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newModeGetCommand(fs),
+		newModeSetCommand(fs),
+	)
+	return cmd
+}
+
+func newModeGetCommand(fs afero.Fs) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [SUBJECTS...]",
+		Short: "Get the global or per-subject mode",
+		Run: func(cmd *cobra.Command, subjects []string) {
+			cl := newClient(fs, cmd)
+			tw := out.NewTable("subject", "mode", "error")
+			defer tw.Flush()
+			for _, s := range subjectsOrGlobal(subjects) {
+				mode, err := cl.Mode(cmd.Context(), s)
+				tw.Print(subjectName(s), mode, errString(err))
+			}
+		},
+	}
+}
+
+func newModeSetCommand(fs afero.Fs) *cobra.Command {
+	var mode string
+	cmd := &cobra.Command{
+		Use:   "set [SUBJECTS...] --mode [MODE]",
+		Short: "Set the global or per-subject mode",
+		Run: func(cmd *cobra.Command, subjects []string) {
+			cl := newClient(fs, cmd)
+			tw := out.NewTable("subject", "mode", "error")
+			defer tw.Flush()
+			for _, s := range subjectsOrGlobal(subjects) {
+				set, err := cl.SetMode(cmd.Context(), s, mode)
+				tw.Print(subjectName(s), set, errString(err))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&mode, "mode", "", "Mode to set (required)")
+	cmd.MarkFlagRequired("mode")
+	return cmd
+}
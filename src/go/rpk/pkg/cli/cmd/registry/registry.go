@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	var (
+		configFile     string
+		user           string
+		password       string
+		registryURLs   []string
+		enableTLS      bool
+		certFile       string
+		keyFile        string
+		truststoreFile string
+	)
+	command := &cobra.Command{
+		Use:   "registry",
+		Short: "Interact with the Schema Registry",
+		Long: `Interact with the Schema Registry.
+
+The schema registry stores schemas under subjects. Each subject has one or more
+versions of a schema, and each distinct schema has a unique ID across the
+registry. Producers and consumers use these IDs to encode and decode records.
+
+By default, rpk talks to the schema registry listener in your redpanda.yaml, or
+localhost:8081 if none is configured. You can specify the addresses to talk to
+with --registry-urls, or rpk.schema_registry_api.addresses in redpanda.yaml.
+
+If the registry requires basic authentication, rpk uses the same --user and
+--password as for the Kafka API.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+
+	command.PersistentFlags().StringVar(
+		&configFile,
+		config.FlagConfig,
+		"",
+		"Redpanda config file, if not set the file will be searched for in the default locations",
+	)
+	command.PersistentFlags().StringVar(
+		&user,
+		config.FlagSASLUser,
+		"",
+		"Username for basic authentication with the Schema Registry",
+	)
+	command.PersistentFlags().StringVar(
+		&password,
+		config.FlagSASLPass,
+		"",
+		"Password for basic authentication with the Schema Registry",
+	)
+	command.PersistentFlags().StringSliceVar(
+		&registryURLs,
+		config.FlagRegistryHosts,
+		[]string{},
+		"Comma-separated list of Schema Registry addresses (<IP>:<port>)",
+	)
+	common.AddSchemaRegistryTLSFlags(
+		command,
+		&enableTLS,
+		&certFile,
+		&keyFile,
+		&truststoreFile,
+	)
+
+	command.AddCommand(
+		newSubjectCommand(fs),
+		newSchemaCommand(fs),
+		newCompatibilityCommand(fs),
+		newModeCommand(fs),
+	)
+	return command
+}
+
+// newClient loads the config from the command's flags and returns a schema
+// registry client, exiting on failure.
+func newClient(fs afero.Fs, cmd *cobra.Command) *schemaregistry.Client {
+	p := config.ParamsFromCommand(cmd)
+	cfg, err := p.Load(fs)
+	out.MaybeDie(err, "unable to load config: %v", err)
+
+	cl, err := schemaregistry.NewClient(fs, cfg)
+	out.MaybeDie(err, "unable to initialize schema registry client: %v", err)
+	return cl
+}
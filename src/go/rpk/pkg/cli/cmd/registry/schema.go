@@ -0,0 +1,264 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Create, get, or delete schemas",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newSchemaCreateCommand(fs),
+		newSchemaGetCommand(fs),
+		newSchemaDeleteCommand(fs),
+		newSchemaReferencesCommand(fs),
+	)
+	return cmd
+}
+
+func newSchemaCreateCommand(fs afero.Fs) *cobra.Command {
+	var (
+		file       string
+		schemaType string
+		references []string
+	)
+	cmd := &cobra.Command{
+		Use:   "create [SUBJECT] --schema [FILE]",
+		Short: "Register a schema under a subject",
+		Long: `Register a schema under a subject.
+
+The schema type is determined from the file extension: .avsc or .avro for Avro,
+.proto for Protobuf, and .json for JSON Schema. You can override the type with
+--type.
+
+Schemas that reference other schemas (for example, a Protobuf import) must
+specify each reference with --references NAME:SUBJECT:VERSION.
+
+If the schema is already registered under the subject, this returns the
+existing schema ID.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			subject := args[0]
+			s, err := readSchema(fs, file, schemaType, references)
+			out.MaybeDieErr(err)
+
+			cl := newClient(fs, cmd)
+			id, err := cl.CreateSchema(cmd.Context(), subject, s)
+			out.MaybeDie(err, "unable to create schema: %v", err)
+
+			ss, err := cl.LookupSchema(cmd.Context(), subject, s)
+			out.MaybeDie(err, "created schema %d, but unable to look up its version: %v", id, err)
+
+			tw := out.NewTable("subject", "version", "id", "type")
+			defer tw.Flush()
+			tw.Print(ss.Subject, ss.Version, id, s.SchemaType())
+		},
+	}
+	cmd.Flags().StringVar(&file, "schema", "", "Schema file to register (required)")
+	cmd.Flags().StringVar(&schemaType, "type", "", "Schema type (avro, protobuf, json); overrides the file extension")
+	cmd.Flags().StringSliceVar(&references, "references", nil, "Comma-separated list of references to other schemas (NAME:SUBJECT:VERSION)")
+	cmd.MarkFlagRequired("schema")
+	return cmd
+}
+
+func newSchemaGetCommand(fs afero.Fs) *cobra.Command {
+	var (
+		id          int
+		version     string
+		printSchema bool
+	)
+	cmd := &cobra.Command{
+		Use:   "get [SUBJECT]",
+		Short: "Get a schema by subject and version or by ID",
+		Long: `Get a schema by subject and version or by ID.
+
+To get a schema registered under a subject, pass the subject and optionally
+--schema-version (which defaults to the latest version). To get a schema by
+ID, pass --id and no subject.
+
+By default this prints the schema's metadata. Use --print-schema to print only
+the schema itself.
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if (len(args) == 0) == (id == 0) {
+				out.Die("exactly one of a subject or --id must be specified")
+			}
+			cl := newClient(fs, cmd)
+
+			var ss schemaregistry.SubjectSchema
+			if id != 0 {
+				s, err := cl.SchemaByID(cmd.Context(), id)
+				out.MaybeDie(err, "unable to get schema %d: %v", id, err)
+				ss = schemaregistry.SubjectSchema{ID: id, Schema: s}
+			} else {
+				var err error
+				ss, err = cl.SchemaByVersion(cmd.Context(), args[0], version)
+				out.MaybeDie(err, "unable to get schema: %v", err)
+			}
+
+			if printSchema {
+				fmt.Println(ss.Schema.Schema)
+				return
+			}
+
+			tw := out.NewTable("subject", "version", "id", "type", "references")
+			defer tw.Flush()
+			tw.Print(ss.Subject, ss.Version, ss.ID, ss.SchemaType(), formatReferences(ss.References))
+		},
+	}
+	cmd.Flags().IntVar(&id, "id", 0, "Schema ID to get")
+	cmd.Flags().StringVar(&version, "schema-version", schemaregistry.VersionLatest, "Version of the schema to get (a number or \"latest\")")
+	cmd.Flags().BoolVar(&printSchema, "print-schema", false, "Print only the schema itself")
+	return cmd
+}
+
+func newSchemaDeleteCommand(fs afero.Fs) *cobra.Command {
+	var (
+		version   string
+		permanent bool
+	)
+	cmd := &cobra.Command{
+		Use:   "delete [SUBJECT] --schema-version [VERSION]",
+		Short: "Delete a version of a schema under a subject",
+		Long: `Delete a version of a schema under a subject.
+
+As with deleting subjects, this soft deletes by default. A version must be soft
+deleted before it can be permanently deleted with --permanent.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			subject := args[0]
+			cl := newClient(fs, cmd)
+			deleted, err := cl.DeleteSubjectVersion(cmd.Context(), subject, version, permanent)
+			out.MaybeDie(err, "unable to delete schema: %v", err)
+			fmt.Printf("Deleted version %d of subject %q.\n", deleted, subject)
+		},
+	}
+	cmd.Flags().StringVar(&version, "schema-version", "", "Version of the schema to delete (a number or \"latest\") (required)")
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "Permanently delete a previously soft-deleted version")
+	cmd.MarkFlagRequired("schema-version")
+	return cmd
+}
+
+func newSchemaReferencesCommand(fs afero.Fs) *cobra.Command {
+	var version string
+	cmd := &cobra.Command{
+		Use:   "references [SUBJECT]",
+		Short: "List the schemas that reference a schema",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			subject := args[0]
+			cl := newClient(fs, cmd)
+			ids, err := cl.SchemaReferencedBy(cmd.Context(), subject, version)
+			out.MaybeDie(err, "unable to list references: %v", err)
+
+			tw := out.NewTable("id", "type")
+			defer tw.Flush()
+			for _, id := range ids {
+				s, err := cl.SchemaByID(cmd.Context(), id)
+				if err != nil {
+					tw.Print(id, fmt.Sprintf("error: %v", err))
+					continue
+				}
+				tw.Print(id, s.SchemaType())
+			}
+		},
+	}
+	cmd.Flags().StringVar(&version, "schema-version", schemaregistry.VersionLatest, "Version of the referenced schema (a number or \"latest\")")
+	return cmd
+}
+
+// readSchema reads a schema file, determining the schema type from typ if
+// non-empty or from the file extension otherwise.
+func readSchema(
+	fs afero.Fs, file, typ string, references []string,
+) (schemaregistry.Schema, error) {
+	var s schemaregistry.Schema
+	raw, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return s, fmt.Errorf("unable to read schema file: %v", err)
+	}
+	s.Schema = string(raw)
+
+	if typ == "" {
+		typ = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	if s.Type, err = parseSchemaType(typ); err != nil {
+		return s, err
+	}
+	if s.References, err = parseReferences(references); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+func parseSchemaType(typ string) (string, error) {
+	switch strings.ToLower(typ) {
+	case "avro", "avsc":
+		return schemaregistry.TypeAvro, nil
+	case "protobuf", "proto":
+		return schemaregistry.TypeProtobuf, nil
+	case "json":
+		return schemaregistry.TypeJSON, nil
+	case "":
+		return "", fmt.Errorf("unable to determine schema type: use --type to specify one of avro, protobuf, or json")
+	default:
+		return "", fmt.Errorf("unknown schema type %q: must be one of avro, protobuf, or json", typ)
+	}
+}
+
+// parseReferences parses references in the form NAME:SUBJECT:VERSION.
+// Reference names can contain colons, so we split from the right.
+func parseReferences(in []string) ([]schemaregistry.SchemaReference, error) {
+	var refs []schemaregistry.SchemaReference
+	for _, r := range in {
+		vidx := strings.LastIndexByte(r, ':')
+		if vidx < 0 {
+			return nil, fmt.Errorf("invalid reference %q: expected NAME:SUBJECT:VERSION", r)
+		}
+		sidx := strings.LastIndexByte(r[:vidx], ':')
+		if sidx <= 0 || sidx+1 == vidx {
+			return nil, fmt.Errorf("invalid reference %q: expected NAME:SUBJECT:VERSION", r)
+		}
+		version, err := strconv.Atoi(r[vidx+1:])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid reference %q: version must be a positive number", r)
+		}
+		refs = append(refs, schemaregistry.SchemaReference{
+			Name:    r[:sidx],
+			Subject: r[sidx+1 : vidx],
+			Version: version,
+		})
+	}
+	return refs, nil
+}
+
+func formatReferences(refs []schemaregistry.SchemaReference) string {
+	var s []string
+	for _, r := range refs {
+		s = append(s, fmt.Sprintf("%s:%s:%d", r.Name, r.Subject, r.Version))
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReferences(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		in     []string
+		exp    []schemaregistry.SchemaReference
+		expErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "multiple",
+			in:   []string{"foo.proto:foo-value:1", "bar:bar:2"},
+			exp: []schemaregistry.SchemaReference{
+				{Name: "foo.proto", Subject: "foo-value", Version: 1},
+				{Name: "bar", Subject: "bar", Version: 2},
+			},
+		},
+		{
+			name: "name with colons",
+			in:   []string{"a:b:subj:3"},
+			exp:  []schemaregistry.SchemaReference{{Name: "a:b", Subject: "subj", Version: 3}},
+		},
+		{name: "missing version", in: []string{"foo:bar"}, expErr: true},
+		{name: "missing name", in: []string{":bar:1"}, expErr: true},
+		{name: "empty subject", in: []string{"foo::1"}, expErr: true},
+		{name: "bad version", in: []string{"foo:bar:latest"}, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseReferences(test.in)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+func TestReadSchema(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/s.proto", []byte("syntax = \"proto3\";"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/s.txt", []byte("{}"), 0o644))
+
+	s, err := readSchema(fs, "/s.proto", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, schemaregistry.TypeProtobuf, s.Type)
+
+	_, err = readSchema(fs, "/s.txt", "", nil)
+	require.Error(t, err)
+
+	s, err = readSchema(fs, "/s.txt", "JSON", nil)
+	require.NoError(t, err)
+	require.Equal(t, schemaregistry.TypeJSON, s.Type)
+}
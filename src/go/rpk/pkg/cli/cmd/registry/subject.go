@@ -0,0 +1,94 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package registry
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newSubjectCommand(fs afero.Fs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subject",
+		Short: "List or delete schema registry subjects",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newSubjectListCommand(fs),
+		newSubjectDeleteCommand(fs),
+	)
+	return cmd
+}
+
+func newSubjectListCommand(fs afero.Fs) *cobra.Command {
+	var deleted bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List subjects and their versions",
+		Args:    cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cl := newClient(fs, cmd)
+
+			subjects, err := cl.Subjects(cmd.Context(), deleted)
+			out.MaybeDie(err, "unable to list subjects: %v", err)
+			sort.Strings(subjects)
+
+			tw := out.NewTable("subject", "versions")
+			defer tw.Flush()
+			for _, s := range subjects {
+				versions, err := cl.SubjectVersions(cmd.Context(), s, deleted)
+				if err != nil {
+					tw.Print(s, fmt.Sprintf("error: %v", err))
+					continue
+				}
+				tw.Print(s, versions)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&deleted, "deleted", false, "Include soft-deleted subjects and versions")
+	return cmd
+}
+
+func newSubjectDeleteCommand(fs afero.Fs) *cobra.Command {
+	var permanent bool
+	cmd := &cobra.Command{
+		Use:   "delete [SUBJECTS...]",
+		Short: "Delete subjects",
+		Long: `Delete subjects.
+
+By default, this soft deletes the subjects: the schemas remain in the registry
+and can be looked up by ID, but the subjects are no longer listed and cannot be
+used for new schemas. To permanently delete subjects, they must first be soft
+deleted and then deleted again with --permanent.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, subjects []string) {
+			cl := newClient(fs, cmd)
+
+			tw := out.NewTable("subject", "deleted-versions", "error")
+			defer tw.Flush()
+			for _, s := range subjects {
+				versions, err := cl.DeleteSubject(cmd.Context(), s, permanent)
+				var msg string
+				if err != nil {
+					msg = err.Error()
+				}
+				tw.Print(s, versions, msg)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "Permanently delete previously soft-deleted subjects")
+	return cmd
+}
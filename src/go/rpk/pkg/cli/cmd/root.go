@@ -30,7 +30,10 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/generate"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/group"
 	plugincmd "github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/plugin"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/profile"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/registry"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/topic"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/transaction"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/version"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/wasm"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
@@ -72,6 +75,7 @@ func Execute() {
 	}
 	root.PersistentFlags().BoolVarP(&verbose, config.FlagVerbose,
 		"v", false, "Enable verbose logging (default: false)")
+	root.PersistentFlags().String(config.FlagProfile, "", "rpk profile to use, overriding RPK_PROFILE and the current profile")
 
 	root.AddCommand(
 		acl.NewCommand(fs),
@@ -81,7 +85,10 @@ func Execute() {
 		generate.NewCommand(fs),
 		group.NewCommand(fs),
 		plugincmd.NewCommand(fs),
+		profile.NewCommand(fs),
+		registry.NewCommand(fs),
 		topic.NewCommand(fs),
+		transaction.NewCommand(fs),
 		version.NewCommand(),
 		wasm.NewCommand(fs),
 	)
@@ -0,0 +1,357 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"gopkg.in/yaml.v3"
+)
+
+// The changes that apply makes to reconcile a topic with a layout file. A
+// conflict is a difference that cannot be applied.
+const (
+	actionCreate        = "create"
+	actionAddPartitions = "add-partitions"
+	actionSetConfig     = "set-config"
+	actionDeleteConfig  = "delete-config"
+	actionConflict      = "conflict"
+)
+
+// topicChange is one change to a topic. For config changes, key and value are
+// the config to set or delete.
+type topicChange struct {
+	topic  string
+	action string
+	detail string
+
+	partitions int32
+	replicas   int16
+	configs    map[string]string
+	key        string
+	value      string
+}
+
+func newApplyCommand(fs afero.Fs) *cobra.Command {
+	var dry bool
+	cmd := &cobra.Command{
+		Use:   "apply [FILE]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Create and reconcile topics from a YAML file",
+		Long: `Create and reconcile topics from a YAML file.
+
+This command reads a file written by 'rpk topic export' and makes the cluster's
+topics match it:
+
+    create:          Topics in the file that do not exist are created.
+    add-partitions:  Partitions are added to topics that have fewer
+                     partitions than in the file.
+    set-config:      Configs in the file that differ from the topic's
+                     configs are set.
+    delete-config:   Configs that are set on the topic but are not in the
+                     file are deleted, reverting to the cluster's default.
+
+Partitions cannot be removed and the replication factor of an existing topic
+cannot be changed with this command; if a topic has more partitions or a
+different replication factor than in the file, the difference is reported as
+a conflict and nothing is applied. Topics that are not in the file are not
+modified.
+
+The changes are printed before they are applied. With --dry, every change is
+validated by the cluster, but nothing is changed.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			raw, err := afero.ReadFile(fs, args[0])
+			out.MaybeDie(err, "unable to read %q: %v", args[0], err)
+			layout, err := parseLayout(raw)
+			out.MaybeDie(err, "unable to parse %q: %v", args[0], err)
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			names := make([]string, 0, len(layout.Topics))
+			for _, t := range layout.Topics {
+				names = append(names, t.Name)
+			}
+			listed, err := adm.ListTopics(cmd.Context(), names...)
+			out.MaybeDie(err, "unable to request metadata: %v", err)
+
+			var existing []string
+			for _, t := range listed.Sorted() {
+				if errors.Is(t.Err, kerr.UnknownTopicOrPartition) {
+					delete(listed, t.Topic)
+					continue
+				}
+				out.MaybeDie(t.Err, "unable to describe topic %q: %v", t.Topic, t.Err)
+				existing = append(existing, t.Topic)
+			}
+			configs := make(map[string]map[string]string)
+			if len(existing) > 0 {
+				rcs, err := adm.DescribeTopicConfigs(cmd.Context(), existing...)
+				out.MaybeDie(err, "unable to describe topic configs: %v", err)
+				for _, rc := range rcs {
+					out.MaybeDie(rc.Err, "unable to describe configs of topic %q: %v", rc.Name, rc.Err)
+					configs[rc.Name] = topicConfigs(rc)
+				}
+			}
+
+			changes := planLayout(layout, listed, configs)
+			if len(changes) == 0 {
+				out.Exit("All topics are up to date.")
+			}
+			for _, c := range changes {
+				if c.action == actionConflict {
+					printChanges(changes, nil)
+					out.Die("\nUnable to apply %s: the file has conflicts with existing topics.", args[0])
+				}
+			}
+
+			statuses := applyChanges(cmd.Context(), adm, changes, dry)
+			printChanges(changes, statuses)
+			if dry {
+				fmt.Println("\nDry run, no changes were made.")
+			}
+			for _, s := range statuses {
+				if s != "OK" {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&dry, "dry", false, "Dry run: validate the changes, but do not apply them")
+	return cmd
+}
+
+func parseLayout(raw []byte) (topicLayout, error) {
+	var layout topicLayout
+	if err := yaml.Unmarshal(raw, &layout); err != nil {
+		return layout, err
+	}
+	if layout.Version != 0 && layout.Version != 1 {
+		return layout, fmt.Errorf("unsupported version %d", layout.Version)
+	}
+	if len(layout.Topics) == 0 {
+		return layout, errors.New("no topics")
+	}
+	seen := make(map[string]bool)
+	for i, t := range layout.Topics {
+		if t.Name == "" {
+			return layout, fmt.Errorf("topic %d is missing a name", i)
+		}
+		if seen[t.Name] {
+			return layout, fmt.Errorf("topic %q is specified more than once", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Partitions == 0 {
+			layout.Topics[i].Partitions = -1
+		}
+		if t.ReplicationFactor == 0 {
+			layout.Topics[i].ReplicationFactor = -1
+		}
+	}
+	return layout, nil
+}
+
+// planLayout returns the changes that make the existing topics match the
+// layout. Configs are the configs that are set on each existing topic.
+func planLayout(
+	layout topicLayout, existing kadm.TopicDetails, configs map[string]map[string]string,
+) []topicChange {
+	var changes []topicChange
+	for _, t := range layout.Topics {
+		td, exists := existing[t.Name]
+		if !exists {
+			var kvs []string
+			for _, k := range sortedKeys(t.Configs) {
+				kvs = append(kvs, k+"="+t.Configs[k])
+			}
+			detail := fmt.Sprintf("partitions=%s replication_factor=%s", orDefault(int64(t.Partitions)), orDefault(int64(t.ReplicationFactor)))
+			if len(kvs) > 0 {
+				detail += " " + strings.Join(kvs, " ")
+			}
+			changes = append(changes, topicChange{
+				topic:      t.Name,
+				action:     actionCreate,
+				detail:     detail,
+				partitions: t.Partitions,
+				replicas:   t.ReplicationFactor,
+				configs:    t.Configs,
+			})
+			continue
+		}
+
+		partitions := int32(len(td.Partitions))
+		switch {
+		case t.Partitions < 0 || t.Partitions == partitions:
+		case t.Partitions > partitions:
+			changes = append(changes, topicChange{
+				topic:      t.Name,
+				action:     actionAddPartitions,
+				detail:     fmt.Sprintf("%d -> %d", partitions, t.Partitions),
+				partitions: t.Partitions,
+			})
+		default:
+			changes = append(changes, topicChange{
+				topic:  t.Name,
+				action: actionConflict,
+				detail: fmt.Sprintf("topic has %d partitions, more than %d", partitions, t.Partitions),
+			})
+		}
+		if rf := int16(td.Partitions.NumReplicas()); t.ReplicationFactor > 0 && t.ReplicationFactor != rf {
+			changes = append(changes, topicChange{
+				topic:  t.Name,
+				action: actionConflict,
+				detail: fmt.Sprintf("replication factor is %d, not %d", rf, t.ReplicationFactor),
+			})
+		}
+
+		current := configs[t.Name]
+		for _, k := range sortedKeys(t.Configs) {
+			v := t.Configs[k]
+			prior, isSet := current[k]
+			if isSet && prior == v {
+				continue
+			}
+			if !isSet {
+				prior = "(default)"
+			}
+			changes = append(changes, topicChange{
+				topic:  t.Name,
+				action: actionSetConfig,
+				detail: fmt.Sprintf("%s: %s -> %s", k, prior, v),
+				key:    k,
+				value:  v,
+			})
+		}
+		for _, k := range sortedKeys(current) {
+			if _, keep := t.Configs[k]; keep {
+				continue
+			}
+			changes = append(changes, topicChange{
+				topic:  t.Name,
+				action: actionDeleteConfig,
+				detail: fmt.Sprintf("%s: %s -> (default)", k, current[k]),
+				key:    k,
+			})
+		}
+	}
+	return changes
+}
+
+func orDefault(n int64) string {
+	if n < 0 {
+		return "(default)"
+	}
+	return fmt.Sprint(n)
+}
+
+// applyChanges applies (or with dry, validates) the changes, returning the
+// status of each change.
+func applyChanges(
+	ctx context.Context, adm *kadm.Client, changes []topicChange, dry bool,
+) []string {
+	var (
+		createTopics     = adm.CreateTopics
+		updatePartitions = adm.UpdatePartitions
+		alterConfigs     = adm.AlterTopicConfigs
+	)
+	if dry {
+		createTopics = adm.ValidateCreateTopics
+		updatePartitions = adm.ValidateUpdatePartitions
+		alterConfigs = adm.ValidateAlterTopicConfigs
+	}
+
+	// Config changes are applied in one request per topic; we gather
+	// them first so that every change of a topic has the same status.
+	alters := make(map[string][]kadm.AlterConfig)
+	for _, c := range changes {
+		switch c.action {
+		case actionSetConfig:
+			alters[c.topic] = append(alters[c.topic], kadm.AlterConfig{Op: kadm.SetConfig, Name: c.key, Value: kadm.StringPtr(c.value)})
+		case actionDeleteConfig:
+			alters[c.topic] = append(alters[c.topic], kadm.AlterConfig{Op: kadm.DeleteConfig, Name: c.key})
+		}
+	}
+	alterStatus := make(map[string]string)
+
+	statuses := make([]string, len(changes))
+	for i, c := range changes {
+		var err error
+		switch c.action {
+		case actionCreate:
+			configs := make(map[string]*string, len(c.configs))
+			for k, v := range c.configs {
+				configs[k] = kadm.StringPtr(v)
+			}
+			var resps kadm.CreateTopicResponses
+			if resps, err = createTopics(ctx, c.partitions, c.replicas, configs, c.topic); err == nil {
+				_, err = resps.On(c.topic, func(r *kadm.CreateTopicResponse) error { return r.Err })
+			}
+		case actionAddPartitions:
+			var resps kadm.CreatePartitionsResponses
+			if resps, err = updatePartitions(ctx, int(c.partitions), c.topic); err == nil {
+				_, err = resps.On(c.topic, func(r *kadm.CreatePartitionsResponse) error { return r.Err })
+			}
+		case actionSetConfig, actionDeleteConfig:
+			status, done := alterStatus[c.topic]
+			if !done {
+				status = "OK"
+				resps, err := alterConfigs(ctx, alters[c.topic], c.topic)
+				if err == nil {
+					_, err = resps.On(c.topic, func(r *kadm.AlterConfigsResponse) error { return r.Err })
+				}
+				if err != nil {
+					status = err.Error()
+				}
+				alterStatus[c.topic] = status
+			}
+			statuses[i] = status
+			continue
+		}
+		statuses[i] = "OK"
+		if err != nil {
+			statuses[i] = err.Error()
+		}
+	}
+	return statuses
+}
+
+// printChanges prints the changes, with their statuses if they were applied.
+func printChanges(changes []topicChange, statuses []string) {
+	headers := []string{"topic", "action", "detail"}
+	if statuses != nil {
+		headers = append(headers, "status")
+	}
+	tw := out.NewTable(headers...)
+	defer tw.Flush()
+	for i, c := range changes {
+		row := []interface{}{c.topic, c.action, c.detail}
+		if statuses != nil {
+			row = append(row, statuses[i])
+		}
+		tw.Print(row...)
+	}
+}
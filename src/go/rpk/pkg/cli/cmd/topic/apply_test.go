@@ -0,0 +1,118 @@
+package topic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func partitionDetails(n int, replicas ...int32) kadm.PartitionDetails {
+	ps := make(kadm.PartitionDetails)
+	for i := 0; i < n; i++ {
+		ps[int32(i)] = kadm.PartitionDetail{Partition: int32(i), Replicas: replicas}
+	}
+	return ps
+}
+
+func TestBuildLayout(t *testing.T) {
+	listed := kadm.TopicDetails{
+		"foo": {Topic: "foo", Partitions: partitionDetails(3, 1, 2, 3)},
+		"bar": {Topic: "bar", Partitions: partitionDetails(1, 1)},
+	}
+	configs := kadm.ResourceConfigs{
+		{Name: "foo", Configs: []kadm.Config{
+			{Key: "cleanup.policy", Value: kadm.StringPtr("compact"), Source: kmsg.ConfigSourceDynamicTopicConfig},
+			{Key: "retention.ms", Value: kadm.StringPtr("604800000"), Source: kmsg.ConfigSourceDefaultConfig},
+			{Key: "secret", Sensitive: true, Source: kmsg.ConfigSourceDynamicTopicConfig},
+		}},
+		{Name: "bar", Configs: []kadm.Config{
+			{Key: "retention.ms", Value: kadm.StringPtr("604800000"), Source: kmsg.ConfigSourceDefaultConfig},
+		}},
+	}
+	layout, err := buildLayout(listed, configs)
+	require.NoError(t, err)
+	require.Equal(t, topicLayout{Version: 1, Topics: []layoutTopic{
+		{Name: "bar", Partitions: 1, ReplicationFactor: 1},
+		{Name: "foo", Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"cleanup.policy": "compact"}},
+	}}, layout)
+
+	b, err := marshalLayout(layout)
+	require.NoError(t, err)
+	require.Equal(t, `version: 1
+topics:
+  - name: bar
+    partitions: 1
+    replication_factor: 1
+  - name: foo
+    partitions: 3
+    replication_factor: 3
+    configs:
+      cleanup.policy: compact
+`, string(b))
+
+	// The exported file can be applied.
+	parsed, err := parseLayout(b)
+	require.NoError(t, err)
+	require.Equal(t, layout, parsed)
+
+	_, err = buildLayout(listed, configs[:1])
+	require.Error(t, err)
+}
+
+func TestParseLayout(t *testing.T) {
+	layout, err := parseLayout([]byte(`topics: [{name: foo}]`))
+	require.NoError(t, err)
+	require.Equal(t, []layoutTopic{{Name: "foo", Partitions: -1, ReplicationFactor: -1}}, layout.Topics)
+
+	for _, bad := range []string{
+		`{`,
+		`version: 2
+topics: [{name: foo}]`,
+		`topics: []`,
+		`topics: [{partitions: 1}]`,
+		`topics: [{name: foo}, {name: foo}]`,
+	} {
+		_, err := parseLayout([]byte(bad))
+		require.Error(t, err, "layout %s", bad)
+	}
+}
+
+func TestPlanLayout(t *testing.T) {
+	layout := topicLayout{Topics: []layoutTopic{
+		{Name: "new", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"cleanup.policy": "compact", "retention.ms": "1000"}},
+		{Name: "new-defaults", Partitions: -1, ReplicationFactor: -1},
+		{Name: "grow", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "2000", "segment.bytes": "1024"}},
+		{Name: "same", Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"cleanup.policy": "compact"}},
+		{Name: "shrink", Partitions: 2, ReplicationFactor: 1},
+	}}
+	existing := kadm.TopicDetails{
+		"grow":   {Topic: "grow", Partitions: partitionDetails(3, 1, 2, 3)},
+		"same":   {Topic: "same", Partitions: partitionDetails(3, 1, 2, 3)},
+		"shrink": {Topic: "shrink", Partitions: partitionDetails(3, 1, 2, 3)},
+	}
+	configs := map[string]map[string]string{
+		"grow": {"retention.ms": "1000", "cleanup.policy": "delete"},
+		"same": {"cleanup.policy": "compact"},
+	}
+
+	require.Equal(t, []topicChange{
+		{
+			topic: "new", action: actionCreate,
+			detail:     "partitions=6 replication_factor=3 cleanup.policy=compact retention.ms=1000",
+			partitions: 6, replicas: 3, configs: map[string]string{"cleanup.policy": "compact", "retention.ms": "1000"},
+		},
+		{
+			topic: "new-defaults", action: actionCreate,
+			detail:     "partitions=(default) replication_factor=(default)",
+			partitions: -1, replicas: -1,
+		},
+		{topic: "grow", action: actionAddPartitions, detail: "3 -> 6", partitions: 6},
+		{topic: "grow", action: actionSetConfig, detail: "retention.ms: 1000 -> 2000", key: "retention.ms", value: "2000"},
+		{topic: "grow", action: actionSetConfig, detail: "segment.bytes: (default) -> 1024", key: "segment.bytes", value: "1024"},
+		{topic: "grow", action: actionDeleteConfig, detail: "cleanup.policy: delete -> (default)", key: "cleanup.policy"},
+		{topic: "shrink", action: actionConflict, detail: "topic has 3 partitions, more than 2"},
+		{topic: "shrink", action: actionConflict, detail: "replication factor is 3, not 1"},
+	}, planLayout(layout, existing, configs))
+}
@@ -22,9 +22,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/serde"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kadm"
@@ -43,12 +45,20 @@ type consumer struct {
 	readCommitted bool
 
 	f        *kgo.RecordFormatter // if not json
+	write    func(*kgo.Record)    // if non-nil, used rather than printing
 	num      int
+	filters  recordFilters
 	pretty   bool // specific to -f json
 	metaOnly bool // specific to -f json
 
 	resetOffset kgo.Offset // defaults to NoResetOffset, can be start or end
 
+	// If non-nil, we decode keys and / or values with schemas from the
+	// schema registry.
+	dec         *serde.Decoder
+	decodeKey   bool
+	decodeValue bool
+
 	// If an end offset is specified, we immediately look up where we will
 	// end and quit rpk when we hit the end.
 	partEnds   map[string]map[int32]int64
@@ -59,9 +69,11 @@ type consumer struct {
 
 func newConsumeCommand(fs afero.Fs) *cobra.Command {
 	var (
-		c      consumer
-		offset string
-		format string
+		c         consumer
+		offset    string
+		format    string
+		useSchema []string
+		filters   []string
 	)
 
 	cmd := &cobra.Command{
@@ -74,6 +86,9 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 			cfg, err := p.Load(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
+			c.filters, err = parseFilters(filters)
+			out.MaybeDie(err, "invalid --filter: %v", err)
+
 			adm, err := kafka.NewAdmin(fs, p, cfg)
 			out.MaybeDie(err, "unable to initialize admin kafka client: %v", err)
 
@@ -91,6 +106,14 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 				out.MaybeDie(err, "invalid --format: %v", err)
 			}
 
+			if len(useSchema) > 0 {
+				err = c.parseUseSchema(useSchema)
+				out.MaybeDieErr(err)
+				srCl, err := schemaregistry.NewClient(fs, cfg)
+				out.MaybeDie(err, "unable to initialize schema registry client: %v", err)
+				c.dec = serde.NewDecoder(srCl)
+			}
+
 			sigs := make(chan os.Signal, 2)
 			signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
@@ -134,10 +157,14 @@ func newConsumeCommand(fs afero.Fs) *cobra.Command {
 	cmd.Flags().BoolVar(&c.readCommitted, "read-committed", false, "Opt in to reading only committed offsets")
 
 	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format (see --help for details)")
-	cmd.Flags().IntVarP(&c.num, "num", "n", 0, "Quit after consuming this number of records (0 is unbounded)")
+	cmd.Flags().IntVarP(&c.num, "num", "n", 0, "Quit after consuming this number of records, or with --filter, this number of matching records (0 is unbounded)")
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "Only print records matching a filter expression, FIELD OP VALUE (repeatable; see --help for details)")
 	cmd.Flags().BoolVar(&c.pretty, "pretty-print", true, "Pretty print each record over multiple lines (for -f json)")
 	cmd.Flags().BoolVar(&c.metaOnly, "meta-only", false, "Print all record info except the record value (for -f json)")
 
+	cmd.Flags().StringSliceVar(&useSchema, "use-schema-registry", nil, "Decode record values (and optionally keys) with schemas from the schema registry (value, key)")
+	cmd.Flags().Lookup("use-schema-registry").NoOptDefVal = "value"
+
 	// Deprecated.
 	cmd.Flags().BoolVar(new(bool), "commit", false, "")
 	cmd.Flags().MarkDeprecated("commit", "Group consuming always commits")
@@ -176,19 +203,29 @@ func (c *consumer) consume() {
 			}
 
 			for _, r := range p.Records {
+				// With filters, only matching records count
+				// towards --num.
+				counted := len(c.filters) == 0
 				if !r.Attrs.IsControl() {
-					if c.f == nil {
-						c.writeRecordJSON(r)
-					} else {
-						buf = c.f.AppendPartitionRecord(buf[:0], &p.FetchPartition, r)
-						os.Stdout.Write(buf)
+					keyDecoded, valueDecoded := c.decodeRecord(r)
+					if counted = c.filters.match(r); counted {
+						if c.write != nil {
+							c.write(r)
+						} else if c.f == nil {
+							c.writeRecordJSON(r, keyDecoded, valueDecoded)
+						} else {
+							buf = c.f.AppendPartitionRecord(buf[:0], &p.FetchPartition, r)
+							os.Stdout.Write(buf)
+						}
 					}
 				}
 
 				// Track this record to be "marked" once this loop
 				// is over.
 				marks = append(marks, r)
-				n++
+				if counted {
+					n++
+				}
 
 				if done = c.num > 0 && n >= c.num; done {
 					return
@@ -207,26 +244,71 @@ func (c *consumer) consume() {
 	}
 }
 
-func (c *consumer) writeRecordJSON(r *kgo.Record) {
+func (c *consumer) parseUseSchema(use []string) error {
+	for _, u := range use {
+		switch strings.ToLower(u) {
+		case "key":
+			c.decodeKey = true
+		case "value":
+			c.decodeValue = true
+		default:
+			return fmt.Errorf("invalid --use-schema-registry %q: must be key or value", u)
+		}
+	}
+	return nil
+}
+
+// decodeRecord decodes the record key and value in place if requested,
+// returning which were decoded. Fields that fail to decode are left as is,
+// with the error printed to stderr.
+func (c *consumer) decodeRecord(r *kgo.Record) (keyDecoded, valueDecoded bool) {
+	if c.dec == nil {
+		return false, false
+	}
+	decode := func(field string, b *[]byte) bool {
+		if len(*b) == 0 {
+			return false
+		}
+		decoded, err := c.dec.Decode(context.Background(), *b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERR: topic %s partition %d offset %d: unable to decode %s: %v\n", r.Topic, r.Partition, r.Offset, field, err)
+			return false
+		}
+		*b = decoded
+		return true
+	}
+	if c.decodeKey {
+		keyDecoded = decode("key", &r.Key)
+	}
+	if c.decodeValue {
+		valueDecoded = decode("value", &r.Value)
+	}
+	return keyDecoded, valueDecoded
+}
+
+// writeRecordJSON writes the record as JSON. Keys and values that were decoded
+// with a schema are already JSON and are embedded as is, rather than as
+// strings.
+func (c *consumer) writeRecordJSON(r *kgo.Record, keyDecoded, valueDecoded bool) {
 	type Header struct {
 		Key   string `json:"key"`
 		Value string `json:"value"`
 	}
 
 	m := struct {
-		Topic     string   `json:"topic"`
-		Key       string   `json:"key,omitempty"`
-		Value     string   `json:"value,omitempty"`
-		ValueSize *int     `json:"value_size,omitempty"` // non-nil if --meta-only
-		Headers   []Header `json:"headers,omitempty"`
-		Timestamp int64    `json:"timestamp"` // millis
+		Topic     string      `json:"topic"`
+		Key       interface{} `json:"key,omitempty"`
+		Value     interface{} `json:"value,omitempty"`
+		ValueSize *int        `json:"value_size,omitempty"` // non-nil if --meta-only
+		Headers   []Header    `json:"headers,omitempty"`
+		Timestamp int64       `json:"timestamp"` // millis
 
 		Partition int32 `json:"partition"`
 		Offset    int64 `json:"offset"`
 	}{
 		Topic:     r.Topic,
-		Key:       string(r.Key),
-		Value:     string(r.Value),
+		Key:       jsonField(r.Key, keyDecoded),
+		Value:     jsonField(r.Value, valueDecoded),
 		Headers:   make([]Header, 0, len(r.Headers)),
 		Timestamp: r.Timestamp.UnixNano() / 1e6,
 
@@ -235,8 +317,8 @@ func (c *consumer) writeRecordJSON(r *kgo.Record) {
 	}
 
 	if c.metaOnly {
-		size := len(m.Value)
-		m.Value = ""
+		size := len(r.Value)
+		m.Value = nil
 		m.ValueSize = &size
 	}
 
@@ -261,6 +343,19 @@ func (c *consumer) writeRecordJSON(r *kgo.Record) {
 
 var newline = []byte("\n")
 
+// jsonField returns nil for empty fields so that they are omitted, the raw
+// JSON for decoded fields, and a string otherwise.
+func jsonField(b []byte, decoded bool) interface{} {
+	switch {
+	case len(b) == 0:
+		return nil
+	case decoded:
+		return json.RawMessage(b)
+	default:
+		return string(b)
+	}
+}
+
 func (c *consumer) parseOffset(
 	offset string, topics []string, adm *kadm.Client,
 ) error {
@@ -857,6 +952,51 @@ equals sign between the key and value, and with the value hex encoded. Header
 formatting actually just parses the internal format as a record format, so all
 of the above rules about %K, %V, text, and numbers apply.
 
+SCHEMAS
+
+With --use-schema-registry, record values (or keys and values with
+--use-schema-registry=key,value) that are framed with a schema registry schema
+ID are decoded into JSON before formatting. Schemas are looked up by ID from
+the schema registry and cached. Avro values are printed in Avro's JSON encoding
+and Protobuf values in the canonical protobuf JSON mapping. Keys or values that
+cannot be decoded are printed as is, and the error is printed to STDERR.
+
+With the default "--format json", decoded keys and values are embedded as JSON
+rather than as strings. With other formats, %v prints the decoded JSON and %V
+prints its length.
+
+FILTERS
+
+With --filter, only records that match every filter expression are printed,
+and --num counts matching records rather than consumed records. Expressions
+are FIELD OP VALUE, with the following fields:
+
+    key            the record key
+    value          the record value
+    value.PATH     a field of a JSON value, with dots separating object keys
+                   and array indices (value.items.0.sku)
+    header.KEY     the value of any header with the key
+    timestamp      the record timestamp, in any timestamp format described
+                   in OFFSETS below
+    partition      the record partition
+
+and the following operators:
+
+    =  !=          equal, not equal
+    ~  !~          matches, does not match a regular expression
+    <  <=  >  >=   less than, greater than; text compares numerically if
+                   both sides are numbers
+
+Timestamps and partitions do not support regular expressions. A JSON path of a
+value that is not JSON or does not have the path, and a header that does not
+exist, do not match any operator. Filters are evaluated after schema registry
+decoding, so JSON paths can be used with decoded values.
+
+    --filter 'value.order.id=1234'    the order with ID 1234
+    --filter 'key~^user-'             keys starting with "user-"
+    --filter 'header.source!=test'    a "source" header that is not "test"
+    --filter 'timestamp>=-1h'         records from the last hour
+
 EXAMPLES
 
 A key and value, separated by a space and ending in newline:
@@ -0,0 +1,254 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// filterOps are the operators of a filter expression; two character
+// operators come first so that they are preferred when splitting.
+var filterOps = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+// recordFilter is a parsed --filter expression, FIELD OP VALUE.
+type recordFilter struct {
+	expr string
+
+	field  string   // key, value, header, timestamp, or partition
+	path   []string // for value, the JSON path into the value
+	header string   // for header, the header key
+	op     string
+	value  string
+
+	re        *regexp.Regexp // for ~ and !~
+	at        time.Time      // for timestamp
+	partition int64          // for partition
+}
+
+// recordFilters are all --filter expressions; a record must match every
+// filter to be printed.
+type recordFilters []recordFilter
+
+func parseFilters(exprs []string) (recordFilters, error) {
+	var fs recordFilters
+	for _, expr := range exprs {
+		f, err := parseFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+func parseFilter(expr string) (recordFilter, error) {
+	f := recordFilter{expr: expr}
+
+	at := -1
+	for _, op := range filterOps {
+		if i := strings.Index(expr, op); i != -1 && (at == -1 || i < at) {
+			at, f.op = i, op
+		}
+	}
+	if at == -1 {
+		return f, fmt.Errorf("filter %q is missing an operator (%s)", expr, strings.Join(filterOps, " "))
+	}
+	field := expr[:at]
+	f.value = expr[at+len(f.op):]
+
+	switch {
+	case field == "key", field == "value":
+		f.field = field
+	case strings.HasPrefix(field, "value."):
+		f.field = "value"
+		f.path = strings.Split(strings.TrimPrefix(field, "value."), ".")
+	case strings.HasPrefix(field, "header."):
+		f.field = "header"
+		f.header = strings.TrimPrefix(field, "header.")
+	case field == "timestamp", field == "partition":
+		f.field = field
+	default:
+		return f, fmt.Errorf("filter %q has unknown field %q (key, value, value.PATH, header.KEY, timestamp, partition)", expr, field)
+	}
+
+	switch f.op {
+	case "~", "!~":
+		if f.field == "timestamp" || f.field == "partition" {
+			return f, fmt.Errorf("filter %q: %s does not support %s", expr, f.field, f.op)
+		}
+		re, err := regexp.Compile(f.value)
+		if err != nil {
+			return f, fmt.Errorf("filter %q has an invalid regular expression: %v", expr, err)
+		}
+		f.re = re
+	}
+
+	switch f.field {
+	case "timestamp":
+		length, at, end, err := parseConsumeTimestamp(f.value)
+		if err != nil || end || length != len(f.value) {
+			return f, fmt.Errorf("filter %q has an invalid timestamp %q", expr, f.value)
+		}
+		f.at = at
+	case "partition":
+		p, err := strconv.ParseInt(f.value, 10, 32)
+		if err != nil {
+			return f, fmt.Errorf("filter %q has an invalid partition %q", expr, f.value)
+		}
+		f.partition = p
+	}
+	return f, nil
+}
+
+// match returns whether the record matches every filter. Record values are
+// parsed as JSON at most once, and only if a filter uses a JSON path.
+func (fs recordFilters) match(r *kgo.Record) bool {
+	var (
+		parsed    bool
+		jsonValue interface{}
+		jsonErr   error
+	)
+	for i := range fs {
+		f := &fs[i]
+		var ok bool
+		switch f.field {
+		case "key":
+			ok = f.matchText(string(r.Key))
+		case "value":
+			if len(f.path) == 0 {
+				ok = f.matchText(string(r.Value))
+				break
+			}
+			if !parsed {
+				parsed = true
+				dec := json.NewDecoder(bytes.NewReader(r.Value))
+				dec.UseNumber()
+				jsonErr = dec.Decode(&jsonValue)
+			}
+			if jsonErr != nil {
+				break
+			}
+			if v, exists := jsonPath(jsonValue, f.path); exists {
+				ok = f.matchText(v)
+			}
+		case "header":
+			for _, h := range r.Headers {
+				if h.Key == f.header && f.matchText(string(h.Value)) {
+					ok = true
+					break
+				}
+			}
+		case "timestamp":
+			ok = f.matchCompare(compareInt(r.Timestamp.UnixNano()/1e6, f.at.UnixNano()/1e6))
+		case "partition":
+			ok = f.matchCompare(compareInt(int64(r.Partition), f.partition))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchText matches text fields. Ordering operators compare numerically if
+// both sides are numbers, and lexically otherwise.
+func (f *recordFilter) matchText(s string) bool {
+	switch f.op {
+	case "~":
+		return f.re.MatchString(s)
+	case "!~":
+		return !f.re.MatchString(s)
+	case "=", "!=":
+		return f.matchCompare(strings.Compare(s, f.value))
+	}
+	l, lerr := strconv.ParseFloat(s, 64)
+	r, rerr := strconv.ParseFloat(f.value, 64)
+	if lerr == nil && rerr == nil {
+		switch {
+		case l < r:
+			return f.matchCompare(-1)
+		case l > r:
+			return f.matchCompare(1)
+		default:
+			return f.matchCompare(0)
+		}
+	}
+	return f.matchCompare(strings.Compare(s, f.value))
+}
+
+// matchCompare returns whether a comparison result, -1, 0, or 1, satisfies
+// the filter's operator.
+func (f *recordFilter) matchCompare(cmp int) bool {
+	switch f.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareInt(l, r int64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// jsonPath returns the value at the path in v as text: strings as is, and
+// anything else as compact JSON. Numeric path elements index into arrays.
+func jsonPath(v interface{}, path []string) (string, bool) {
+	for _, p := range path {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			next, exists := t[p]
+			if !exists {
+				return "", false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(t) {
+				return "", false
+			}
+			v = t[i]
+		default:
+			return "", false
+		}
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
@@ -0,0 +1,86 @@
+package topic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := parseFilter("value.order.items.0=a=b")
+	require.NoError(t, err)
+	require.Equal(t, "value", f.field)
+	require.Equal(t, []string{"order", "items", "0"}, f.path)
+	require.Equal(t, "=", f.op)
+	require.Equal(t, "a=b", f.value)
+
+	f, err = parseFilter("partition<=3")
+	require.NoError(t, err)
+	require.Equal(t, "<=", f.op)
+	require.Equal(t, int64(3), f.partition)
+
+	f, err = parseFilter("header.trace-id!~^x")
+	require.NoError(t, err)
+	require.Equal(t, "trace-id", f.header)
+	require.Equal(t, "!~", f.op)
+
+	for _, bad := range []string{
+		"key",
+		"offset=3",
+		"key~(",
+		"partition=one",
+		"partition~1",
+		"timestamp>=yesterday",
+		"timestamp<end",
+	} {
+		_, err := parseFilter(bad)
+		require.Error(t, err, "filter %q", bad)
+	}
+}
+
+func TestRecordFiltersMatch(t *testing.T) {
+	r := &kgo.Record{
+		Key:       []byte("user-1"),
+		Value:     []byte(`{"order":{"id":1234,"items":[{"sku":"abc"}],"paid":true}}`),
+		Headers:   []kgo.RecordHeader{{Key: "source", Value: []byte("web")}, {Key: "source", Value: []byte("api")}},
+		Timestamp: time.Unix(1644796800, 0), // 2022-02-14
+		Partition: 7,
+	}
+	for _, test := range []struct {
+		exprs []string
+		exp   bool
+	}{
+		{nil, true},
+		{[]string{"key=user-1"}, true},
+		{[]string{"key!=user-1"}, false},
+		{[]string{"key~^user-"}, true},
+		{[]string{"key!~^user-"}, false},
+		{[]string{"value~1234"}, true},
+		{[]string{"value.order.id=1234"}, true},
+		{[]string{"value.order.id>999"}, true},
+		{[]string{"value.order.id<999"}, false},
+		{[]string{"value.order.items.0.sku=abc"}, true},
+		{[]string{"value.order.items.1.sku=abc"}, false},
+		{[]string{"value.order.paid=true"}, true},
+		{[]string{"value.order.missing!=x"}, false},
+		{[]string{"header.source=api"}, true},
+		{[]string{"header.source=mobile"}, false},
+		{[]string{"header.missing!=x"}, false},
+		{[]string{"timestamp>=2022-02-14"}, true},
+		{[]string{"timestamp<2022-02-14"}, false},
+		{[]string{"partition=7", "key~user"}, true},
+		{[]string{"partition=7", "key~admin"}, false},
+		{[]string{"partition>7"}, false},
+	} {
+		fs, err := parseFilters(test.exprs)
+		require.NoError(t, err)
+		require.Equal(t, test.exp, fs.match(r), "filters %v", test.exprs)
+	}
+
+	// A value that is not JSON does not match any path.
+	fs, err := parseFilters([]string{"value.order!=x"})
+	require.NoError(t, err)
+	require.False(t, fs.match(&kgo.Record{Value: []byte("not json")}))
+}
@@ -33,6 +33,7 @@ func newDescribeCommand(fs afero.Fs) *cobra.Command {
 		summary    bool
 		configs    bool
 		partitions bool
+		format     out.Format
 	)
 	cmd := &cobra.Command{
 		Use:     "describe [TOPIC]",
@@ -43,6 +44,9 @@ func newDescribeCommand(fs afero.Fs) *cobra.Command {
 This command prints detailed information about a topic. There are three
 potential sections: a summary of the topic, the topic configs, and a detailed
 partitions section. By default, the summary and configs sections are printed.
+
+With --format json or yaml, the requested sections are printed as one object
+with the keys summary, configs, and partitions.
 `,
 
 		Args: cobra.ExactArgs(1),
@@ -99,6 +103,48 @@ partitions section. By default, the summary and configs sections are printed.
 				t = resp.Topics[0]
 			}
 
+			var (
+				configResp *kmsg.DescribeConfigsResponse
+				offsets    []startStableEndOffset
+			)
+			if configs {
+				req := kmsg.NewPtrDescribeConfigsRequest()
+				reqResource := kmsg.NewDescribeConfigsRequestResource()
+				reqResource.ResourceType = kmsg.ConfigResourceTypeTopic
+				reqResource.ResourceName = topic
+				req.Resources = append(req.Resources, reqResource)
+
+				configResp, err = req.RequestWith(context.Background(), cl)
+				out.MaybeDie(err, "unable to request configs: %v", err)
+				if len(configResp.Resources) != 1 {
+					out.Die("config response returned %d resources when we asked for 1", len(configResp.Resources))
+				}
+				err = kerr.ErrorForCode(configResp.Resources[0].ErrorCode)
+				out.MaybeDie(err, "config response contained error: %v", err)
+				types.Sort(configResp)
+			}
+			// Everything below here is related to partitions: we
+			// list start, stable, and end offsets, and then we
+			// format everything.
+			if partitions {
+				offsets = listStartEndOffsets(cl, topic, len(t.Partitions))
+			}
+
+			if !format.IsText() {
+				var d topicDescription
+				if summary {
+					d.Summary = describeSummary(t)
+				}
+				if configs {
+					d.Configs = describeConfigs(configResp.Resources[0].Configs)
+				}
+				if partitions {
+					d.Partitions = describePartitions(t.Partitions, offsets)
+				}
+				format.Print(d, nil)
+				return
+			}
+
 			header("SUMMARY", summary, func() {
 				tw := out.NewTabWriter()
 				defer tw.Flush()
@@ -117,24 +163,9 @@ partitions section. By default, the summary and configs sections are printed.
 			})
 
 			header("CONFIGS", configs, func() {
-				req := kmsg.NewPtrDescribeConfigsRequest()
-				reqResource := kmsg.NewDescribeConfigsRequestResource()
-				reqResource.ResourceType = kmsg.ConfigResourceTypeTopic
-				reqResource.ResourceName = topic
-				req.Resources = append(req.Resources, reqResource)
-
-				resp, err := req.RequestWith(context.Background(), cl)
-				out.MaybeDie(err, "unable to request configs: %v", err)
-				if len(resp.Resources) != 1 {
-					out.Die("config response returned %d resources when we asked for 1", len(resp.Resources))
-				}
-				err = kerr.ErrorForCode(resp.Resources[0].ErrorCode)
-				out.MaybeDie(err, "config response contained error: %v", err)
-
 				tw := out.NewTable("KEY", "VALUE", "SOURCE")
 				defer tw.Flush()
-				types.Sort(resp)
-				for _, config := range resp.Resources[0].Configs {
+				for _, config := range configResp.Resources[0].Configs {
 					var val string
 					if config.IsSensitive {
 						val = "(sensitive)"
@@ -145,16 +176,7 @@ partitions section. By default, the summary and configs sections are printed.
 				}
 			})
 
-			// Everything below here is related to partitions: we
-			// list start, stable, and end offsets, and then we
-			// format everything.
-			if !partitions {
-				return
-			}
-
 			header("PARTITIONS", partitions, func() {
-				offsets := listStartEndOffsets(cl, topic, len(t.Partitions))
-
 				tw := out.NewTable(describePartitionsHeaders(
 					t.Partitions,
 					offsets,
@@ -183,6 +205,7 @@ partitions section. By default, the summary and configs sections are printed.
 	cmd.Flags().BoolVarP(&configs, "print-configs", "c", false, "Print the config section")
 	cmd.Flags().BoolVarP(&partitions, "print-partitions", "p", false, "Print the detailed partitions section")
 	cmd.Flags().BoolVarP(&all, "print-all", "a", false, "Print all sections")
+	out.AddFormatFlag(cmd, &format)
 
 	return cmd
 }
@@ -299,6 +322,102 @@ func describePartitionsRows(
 	return rows
 }
 
+// topicDescription is the structured output of the describe command.
+type topicDescription struct {
+	Summary    *topicSummary        `json:"summary,omitempty"`
+	Configs    []topicConfig        `json:"configs,omitempty"`
+	Partitions []describedPartition `json:"partitions,omitempty"`
+}
+
+type topicSummary struct {
+	Name       string `json:"name"`
+	Internal   bool   `json:"internal,omitempty"`
+	Partitions int    `json:"partitions"`
+	Replicas   int    `json:"replicas"`
+	Error      string `json:"error,omitempty"`
+}
+
+type topicConfig struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Source    string `json:"source"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+}
+
+// describedPartition is a partition in the structured output. Offsets that
+// could not be listed are -1, with the error in the corresponding error
+// field.
+type describedPartition struct {
+	Partition        int32   `json:"partition"`
+	Leader           int32   `json:"leader"`
+	LeaderEpoch      int32   `json:"leader_epoch"`
+	Replicas         []int32 `json:"replicas"`
+	OfflineReplicas  []int32 `json:"offline_replicas,omitempty"`
+	LoadError        string  `json:"load_error,omitempty"`
+	LogStartOffset   int64   `json:"log_start_offset"`
+	LastStableOffset int64   `json:"last_stable_offset"`
+	HighWatermark    int64   `json:"high_watermark"`
+	OffsetsError     string  `json:"offsets_error,omitempty"`
+}
+
+func describeSummary(t kmsg.MetadataResponseTopic) *topicSummary {
+	s := &topicSummary{
+		Name:       *t.Topic,
+		Internal:   t.IsInternal,
+		Partitions: len(t.Partitions),
+	}
+	if len(t.Partitions) > 0 {
+		s.Replicas = len(t.Partitions[0].Replicas)
+	}
+	if err := kerr.ErrorForCode(t.ErrorCode); err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+func describeConfigs(configs []kmsg.DescribeConfigsResponseResourceConfig) []topicConfig {
+	cs := []topicConfig{}
+	for _, config := range configs {
+		c := topicConfig{Key: config.Name, Source: config.Source.String(), Sensitive: config.IsSensitive}
+		if !config.IsSensitive && config.Value != nil {
+			c.Value = *config.Value
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+func describePartitions(
+	partitions []kmsg.MetadataResponseTopicPartition,
+	offsets []startStableEndOffset,
+) []describedPartition {
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].Partition < partitions[j].Partition
+	})
+	ps := []describedPartition{}
+	for _, p := range partitions {
+		d := describedPartition{
+			Partition:       p.Partition,
+			Leader:          p.Leader,
+			LeaderEpoch:     p.LeaderEpoch,
+			Replicas:        int32s(p.Replicas).sort(),
+			OfflineReplicas: int32s(p.OfflineReplicas).sort(),
+		}
+		if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+			d.LoadError = err.Error()
+		}
+		o := offsets[p.Partition]
+		d.LogStartOffset, d.LastStableOffset, d.HighWatermark = o.start, o.stable, o.end
+		for _, err := range []error{o.startErr, o.stableErr, o.endErr} {
+			if err != nil && d.OffsetsError == "" {
+				d.OffsetsError = err.Error()
+			}
+		}
+		ps = append(ps, d)
+	}
+	return ps
+}
+
 type startStableEndOffset struct {
 	start     int64
 	startErr  error
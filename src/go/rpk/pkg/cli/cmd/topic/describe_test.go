@@ -132,3 +132,25 @@ func TestDescribePartitions(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribePartitionsStructured(t *testing.T) {
+	got := describePartitions(
+		[]kmsg.MetadataResponseTopicPartition{
+			{Partition: 1, Leader: 0, LeaderEpoch: 2, Replicas: []int32{1, 0}, ErrorCode: 9},
+			{Partition: 0, Leader: 1, LeaderEpoch: -1, Replicas: []int32{0, 1}},
+		},
+		[]startStableEndOffset{
+			{start: 0, stable: 5, end: 5},
+			{start: -1, startErr: errUnlisted, stable: -1, stableErr: errUnlisted, end: -1, endErr: errUnlisted},
+		},
+	)
+	require.Equal(t, []describedPartition{
+		{Partition: 0, Leader: 1, LeaderEpoch: -1, Replicas: []int32{0, 1}, OfflineReplicas: []int32{}, LogStartOffset: 0, LastStableOffset: 5, HighWatermark: 5},
+		{
+			Partition: 1, Leader: 0, LeaderEpoch: 2, Replicas: []int32{0, 1}, OfflineReplicas: []int32{},
+			LoadError:      kerr.ErrorForCode(9).Error(),
+			LogStartOffset: -1, LastStableOffset: -1, HighWatermark: -1,
+			OffsetsError: errUnlisted.Error(),
+		},
+	}, got)
+}
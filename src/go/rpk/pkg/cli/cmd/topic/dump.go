@@ -0,0 +1,453 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	// dumpMagic starts every (uncompressed) dump file.
+	dumpMagic        = "RPKDUMP1"
+	dumpManifestName = "manifest.json"
+
+	// maxDumpRecordBytes caps the size of a single record read from a dump,
+	// well above what any broker accepts, so that a corrupt length does not
+	// make us allocate an arbitrary amount of memory.
+	maxDumpRecordBytes = 128 << 20
+)
+
+// dumpManifest describes a dump directory; files are in the order they were
+// written.
+type dumpManifest struct {
+	Version     int        `json:"version"`
+	Topic       string     `json:"topic"`
+	Partitions  int32      `json:"partitions"`
+	Compression string     `json:"compression"`
+	Files       []dumpFile `json:"files"`
+}
+
+type dumpFile struct {
+	Name    string `json:"name"`
+	Records int64  `json:"records"`
+}
+
+func newDumpCommand(fs afero.Fs) *cobra.Command {
+	var (
+		c           consumer
+		to          string
+		offset      string
+		compression string
+		maxBytes    int64
+	)
+	cmd := &cobra.Command{
+		Use:   "dump [TOPIC] --to [DIR]",
+		Short: "Dump the records of a topic to local files",
+		Long: `Dump the records of a topic to local files.
+
+This command consumes a topic and writes the key, value, headers, timestamp,
+partition, and offset of every record to files in a directory, which can be
+replayed into a topic with 'rpk topic restore'. The directory must not
+already contain a dump.
+
+Records are written in a compact binary format to files that are rolled once
+they reach --max-file-bytes (before compression). Files can be compressed
+with gzip or zstd. A manifest.json file in the directory lists the files and
+describes the dumped topic.
+
+By default, the topic is dumped from the start until the current end of each
+partition. The --offset and --partitions flags work as in 'rpk topic consume',
+but an end offset is required.
+
+EXAMPLES
+
+Dump a compacted config topic, compressed with zstd:
+    rpk topic dump _schemas --to schemas-backup --compression zstd
+
+Dump the last hour of partition 0:
+    rpk topic dump orders --to orders-dump -p 0 -o @-1h:end
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			topic := args[0]
+			if to == "" {
+				out.Die("missing required --to directory")
+			}
+			if maxBytes <= 0 {
+				out.Die("invalid --max-file-bytes %d, must be positive", maxBytes)
+			}
+			if _, err := compressionExt(compression); err != nil {
+				out.Die("invalid --compression: %v", err)
+			}
+			if exists, _ := afero.Exists(fs, filepath.Join(to, dumpManifestName)); exists {
+				out.Die("%s already contains a dump", to)
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize admin kafka client: %v", err)
+			defer adm.Close()
+
+			listed, err := adm.ListTopics(cmd.Context(), topic)
+			out.MaybeDie(err, "unable to request metadata: %v", err)
+			td, exists := listed[topic]
+			if !exists {
+				out.Die("topic %q was not returned in metadata", topic)
+			}
+			out.MaybeDie(td.Err, "unable to describe topic %q: %v", topic, td.Err)
+
+			err = c.parseOffset(offset, []string{topic}, adm)
+			out.MaybeDie(err, "invalid --offset %q: %v", offset, err)
+			if c.partEnds == nil {
+				out.Die("invalid --offset %q: dumping requires an end offset", offset)
+			}
+
+			w := &dumpWriter{
+				fs:       fs,
+				dir:      to,
+				maxBytes: maxBytes,
+				manifest: dumpManifest{
+					Version:     1,
+					Topic:       topic,
+					Partitions:  int32(len(td.Partitions)),
+					Compression: compression,
+					Files:       []dumpFile{},
+				},
+			}
+			err = fs.MkdirAll(to, 0o755)
+			out.MaybeDie(err, "unable to create %q: %v", to, err)
+
+			if allEmpty := c.filterEmptyPartitions(); !allEmpty {
+				c.balancer = "cooperative-sticky"
+				c.fetchMaxBytes = 1 << 20
+				c.fetchMaxWait = 5 * time.Second
+				opts, err := c.intoOptions([]string{topic})
+				out.MaybeDieErr(err)
+				c.cl, err = kafka.NewFranzClient(fs, p, cfg, opts...)
+				out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+				c.write = func(r *kgo.Record) {
+					err := w.write(r)
+					out.MaybeDie(err, "unable to write record: %v", err)
+				}
+				c.consume()
+				c.cl.Close()
+			}
+
+			err = w.close()
+			out.MaybeDie(err, "unable to finish dump: %v", err)
+			var records int64
+			for _, f := range w.manifest.Files {
+				records += f.Records
+			}
+			fmt.Printf("Dumped %d record(s) from topic %q to %d file(s) in %s.\n", records, topic, len(w.manifest.Files), to)
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", "", "Directory to write the dump to")
+	cmd.Flags().StringVarP(&offset, "offset", "o", ":end", "Offsets to dump from / to, as in 'rpk topic consume' (an end is required)")
+	cmd.Flags().Int32SliceVarP(&c.partitions, "partitions", "p", nil, "Comma delimited list of specific partitions to dump")
+	cmd.Flags().StringVar(&compression, "compression", "none", "Compression of the dump files (none, gzip, zstd)")
+	cmd.Flags().Int64Var(&maxBytes, "max-file-bytes", 128<<20, "Size at which to roll to a new file, before compression")
+	return cmd
+}
+
+func compressionExt(compression string) (string, error) {
+	switch compression {
+	case "none":
+		return "", nil
+	case "gzip":
+		return ".gz", nil
+	case "zstd":
+		return ".zst", nil
+	default:
+		return "", fmt.Errorf("unsupported compression %q (none, gzip, zstd)", compression)
+	}
+}
+
+// dumpWriter writes records to rolling dump files, and on close, the
+// manifest.
+type dumpWriter struct {
+	fs       afero.Fs
+	dir      string
+	maxBytes int64
+	manifest dumpManifest
+
+	f       afero.File
+	bw      *bufio.Writer
+	zw      io.WriteCloser // compressor, if any
+	written int64
+	buf     []byte
+}
+
+func (w *dumpWriter) write(r *kgo.Record) error {
+	if w.f != nil && w.written >= w.maxBytes {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+	}
+	if w.f == nil {
+		if err := w.openFile(); err != nil {
+			return err
+		}
+	}
+	w.buf = appendDumpRecord(w.buf[:0], r)
+	if _, err := w.writer().Write(w.buf); err != nil {
+		return err
+	}
+	w.written += int64(len(w.buf))
+	w.manifest.Files[len(w.manifest.Files)-1].Records++
+	return nil
+}
+
+func (w *dumpWriter) writer() io.Writer {
+	if w.zw != nil {
+		return w.zw
+	}
+	return w.bw
+}
+
+func (w *dumpWriter) openFile() error {
+	ext, err := compressionExt(w.manifest.Compression)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%06d.rpkdump%s", len(w.manifest.Files), ext)
+	f, err := w.fs.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	w.f = f
+	w.bw = bufio.NewWriter(f)
+	switch w.manifest.Compression {
+	case "gzip":
+		w.zw = gzip.NewWriter(w.bw)
+	case "zstd":
+		if w.zw, err = zstd.NewWriter(w.bw); err != nil {
+			return err
+		}
+	}
+	w.manifest.Files = append(w.manifest.Files, dumpFile{Name: name})
+	_, err = io.WriteString(w.writer(), dumpMagic)
+	w.written = int64(len(dumpMagic))
+	return err
+}
+
+func (w *dumpWriter) closeFile() error {
+	var err error
+	if w.zw != nil {
+		err = w.zw.Close()
+	}
+	if ferr := w.bw.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	w.f, w.bw, w.zw = nil, nil, nil
+	return err
+}
+
+// close closes the current file, if any, and writes the manifest.
+func (w *dumpWriter) close() error {
+	if w.f != nil {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(w.fs, filepath.Join(w.dir, dumpManifestName), append(b, '\n'), 0o644)
+}
+
+// appendDumpRecord appends a record to dst. Records are prefixed with their
+// uvarint length, followed by the varint partition, offset, and millisecond
+// timestamp, the key and value, and the headers. Bytes fields are a varint
+// length (-1 for null) followed by the bytes.
+func appendDumpRecord(dst []byte, r *kgo.Record) []byte {
+	appendBytes := func(dst, b []byte) []byte {
+		if b == nil {
+			return binary.AppendVarint(dst, -1)
+		}
+		dst = binary.AppendVarint(dst, int64(len(b)))
+		return append(dst, b...)
+	}
+	var body []byte
+	body = binary.AppendVarint(body, int64(r.Partition))
+	body = binary.AppendVarint(body, r.Offset)
+	body = binary.AppendVarint(body, r.Timestamp.UnixNano()/1e6)
+	body = appendBytes(body, r.Key)
+	body = appendBytes(body, r.Value)
+	body = binary.AppendUvarint(body, uint64(len(r.Headers)))
+	for _, h := range r.Headers {
+		body = appendBytes(body, []byte(h.Key))
+		body = appendBytes(body, h.Value)
+	}
+	dst = binary.AppendUvarint(dst, uint64(len(body)))
+	return append(dst, body...)
+}
+
+var errCorruptDump = errors.New("corrupt dump record")
+
+// readDumpRecord reads the next record, returning io.EOF if there are no
+// more records.
+func readDumpRecord(br *bufio.Reader) (*kgo.Record, error) {
+	size, err := binary.ReadUvarint(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, errCorruptDump
+	}
+	if size > maxDumpRecordBytes {
+		return nil, errCorruptDump
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(br, body); err != nil {
+		return nil, errCorruptDump
+	}
+
+	var bad bool
+	varint := func() int64 {
+		v, n := binary.Varint(body)
+		if n <= 0 {
+			bad = true
+			return 0
+		}
+		body = body[n:]
+		return v
+	}
+	readBytes := func() []byte {
+		l := varint()
+		if bad || l < 0 {
+			return nil
+		}
+		if l > int64(len(body)) {
+			bad = true
+			return nil
+		}
+		b := body[:l:l]
+		body = body[l:]
+		return b
+	}
+
+	r := &kgo.Record{
+		Partition: int32(varint()),
+		Offset:    varint(),
+		Timestamp: time.Unix(0, varint()*1e6),
+	}
+	r.Key = readBytes()
+	r.Value = readBytes()
+	nheaders, n := binary.Uvarint(body)
+	if n <= 0 {
+		return nil, errCorruptDump
+	}
+	body = body[n:]
+	for i := uint64(0); i < nheaders && !bad; i++ {
+		r.Headers = append(r.Headers, kgo.RecordHeader{Key: string(readBytes()), Value: readBytes()})
+	}
+	if bad || len(body) != 0 {
+		return nil, errCorruptDump
+	}
+	return r, nil
+}
+
+// readDumpManifest reads the manifest of a dump directory.
+func readDumpManifest(fs afero.Fs, dir string) (dumpManifest, error) {
+	var m dumpManifest
+	raw, err := afero.ReadFile(fs, filepath.Join(dir, dumpManifestName))
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return m, fmt.Errorf("unable to decode %s: %v", dumpManifestName, err)
+	}
+	if m.Version != 1 {
+		return m, fmt.Errorf("unsupported dump version %d", m.Version)
+	}
+	if _, err := compressionExt(m.Compression); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// readDumpFile calls fn for every record in a dump file, checking that the
+// file has as many records as the manifest says.
+func readDumpFile(
+	fs afero.Fs, dir string, m dumpManifest, file dumpFile, fn func(*kgo.Record) error,
+) error {
+	f, err := fs.Open(filepath.Join(dir, file.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	switch m.Compression {
+	case "gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file.Name, err)
+		}
+		defer zr.Close()
+		r = zr
+	case "zstd":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file.Name, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(dumpMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != dumpMagic {
+		return fmt.Errorf("%s is not a dump file", file.Name)
+	}
+	var n int64
+	for {
+		rec, err := readDumpRecord(br)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: record %d: %v", file.Name, n, err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+		n++
+	}
+	if n != file.Records {
+		return fmt.Errorf("%s has %d records, but the manifest lists %d", file.Name, n, file.Records)
+	}
+	return nil
+}
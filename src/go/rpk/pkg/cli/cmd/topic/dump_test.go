@@ -0,0 +1,112 @@
+package topic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDumpRecordEncoding(t *testing.T) {
+	in := []*kgo.Record{
+		{
+			Key:       []byte("k"),
+			Value:     []byte("v"),
+			Headers:   []kgo.RecordHeader{{Key: "h", Value: []byte("hv")}, {Key: "null"}},
+			Timestamp: time.Unix(1644796800, 123e6),
+			Partition: 3,
+			Offset:    42,
+		},
+		{Key: []byte{}, Timestamp: time.Unix(0, 0)}, // empty key, null value
+	}
+	var buf []byte
+	for _, r := range in {
+		buf = appendDumpRecord(buf, r)
+	}
+	br := bufio.NewReader(bytes.NewReader(buf))
+	for _, exp := range in {
+		r, err := readDumpRecord(br)
+		require.NoError(t, err)
+		require.Equal(t, exp.Key, r.Key)
+		require.Equal(t, exp.Value, r.Value)
+		require.Equal(t, exp.Headers, r.Headers)
+		require.True(t, exp.Timestamp.Equal(r.Timestamp))
+		require.Equal(t, exp.Partition, r.Partition)
+		require.Equal(t, exp.Offset, r.Offset)
+	}
+	_, err := readDumpRecord(br)
+	require.ErrorIs(t, err, io.EOF)
+
+	// A truncated record is corrupt.
+	br = bufio.NewReader(bytes.NewReader(buf[:len(buf)-1]))
+	_, err = readDumpRecord(br)
+	require.NoError(t, err)
+	_, err = readDumpRecord(br)
+	require.ErrorIs(t, err, errCorruptDump)
+
+	// A corrupt length is rejected before allocating the record.
+	for _, size := range []uint64{maxDumpRecordBytes + 1, math.MaxUint64} {
+		br = bufio.NewReader(bytes.NewReader(binary.AppendUvarint(nil, size)))
+		_, err = readDumpRecord(br)
+		require.ErrorIs(t, err, errCorruptDump)
+	}
+}
+
+func TestDumpWriterRolling(t *testing.T) {
+	for _, compression := range []string{"none", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			w := &dumpWriter{
+				fs:       fs,
+				dir:      "dump",
+				maxBytes: 64,
+				manifest: dumpManifest{Version: 1, Topic: "foo", Partitions: 2, Compression: compression, Files: []dumpFile{}},
+			}
+			require.NoError(t, fs.MkdirAll("dump", 0o755))
+			var in []*kgo.Record
+			for i := 0; i < 10; i++ {
+				r := &kgo.Record{
+					Key:       []byte{byte('a' + i)},
+					Value:     bytes.Repeat([]byte{'x'}, 20),
+					Partition: int32(i % 2),
+					Offset:    int64(i / 2),
+					Timestamp: time.Unix(int64(i), 0),
+				}
+				in = append(in, r)
+				require.NoError(t, w.write(r))
+			}
+			require.NoError(t, w.close())
+
+			m, err := readDumpManifest(fs, "dump")
+			require.NoError(t, err)
+			require.Equal(t, w.manifest, m)
+			require.Greater(t, len(m.Files), 1)
+
+			var got []*kgo.Record
+			for _, f := range m.Files {
+				err := readDumpFile(fs, "dump", m, f, func(r *kgo.Record) error {
+					got = append(got, r)
+					return nil
+				})
+				require.NoError(t, err)
+			}
+			require.Len(t, got, len(in))
+			for i := range in {
+				require.Equal(t, in[i].Key, got[i].Key)
+				require.Equal(t, in[i].Partition, got[i].Partition)
+			}
+
+			// A manifest that disagrees with a file is an error.
+			m.Files[0].Records++
+			err = readDumpFile(fs, "dump", m, m.Files[0], func(*kgo.Record) error { return nil })
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
+)
+
+// topicLayout is the file written by export and read by apply.
+type topicLayout struct {
+	Version int           `yaml:"version"`
+	Topics  []layoutTopic `yaml:"topics"`
+}
+
+// layoutTopic is a topic in a layout file. A partition count or replication
+// factor of -1 (or unset) uses the cluster's default when creating the topic,
+// and is not compared against existing topics.
+type layoutTopic struct {
+	Name              string            `yaml:"name"`
+	Partitions        int32             `yaml:"partitions"`
+	ReplicationFactor int16             `yaml:"replication_factor"`
+	Configs           map[string]string `yaml:"configs,omitempty"`
+}
+
+func newExportCommand(fs afero.Fs) *cobra.Command {
+	var (
+		re     bool
+		output string
+	)
+	cmd := &cobra.Command{
+		Use:   "export [TOPICS...]",
+		Short: "Export the layout and configs of topics to a YAML file",
+		Long: `Export the layout and configs of topics to a YAML file.
+
+This command writes the partition count, replication factor, and non-default
+configs of topics to a YAML file that can be applied to another cluster with
+'rpk topic apply'. Only configs that are set on the topic itself are exported;
+configs that inherit the cluster's defaults are not. Sensitive configs cannot
+be read, and are skipped with a warning.
+
+By default, all non-internal topics are exported. Topics can be specified as
+arguments; with --regex (-r), the arguments are parsed as regular expressions,
+which must match the whole topic name.
+
+The file looks like:
+
+    version: 1
+    topics:
+      - name: orders
+        partitions: 12
+        replication_factor: 3
+        configs:
+          cleanup.policy: compact
+
+EXAMPLES
+
+Export all topics starting with "orders" to a file:
+    rpk topic export -r 'orders.*' -o orders.yaml
+`,
+		Run: func(cmd *cobra.Command, topics []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer adm.Close()
+
+			if re {
+				topics, err = regexTopics(adm, topics)
+				out.MaybeDie(err, "unable to filter topics by regex: %v", err)
+				if len(topics) == 0 {
+					out.Exit("No topics match the expressions.")
+				}
+			}
+
+			layout, err := exportLayout(cmd.Context(), adm, topics)
+			out.MaybeDieErr(err)
+
+			b, err := marshalLayout(layout)
+			out.MaybeDie(err, "unable to encode topics: %v", err)
+			if output == "" {
+				os.Stdout.Write(b)
+				return
+			}
+			err = afero.WriteFile(fs, output, b, 0o644)
+			out.MaybeDie(err, "unable to write %q: %v", output, err)
+			fmt.Printf("Exported %d topic(s) to %s.\n", len(layout.Topics), output)
+		},
+	}
+	cmd.Flags().BoolVarP(&re, "regex", "r", false, "Parse topics as regex; export any topic that matches any input topic expression")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the topics to (default stdout)")
+	return cmd
+}
+
+// exportLayout describes the topics, or all non-internal topics if none are
+// requested.
+func exportLayout(
+	ctx context.Context, adm *kadm.Client, topics []string,
+) (topicLayout, error) {
+	listed, err := adm.ListTopics(ctx, topics...)
+	if err != nil {
+		return topicLayout{}, fmt.Errorf("unable to request metadata: %v", err)
+	}
+	for _, t := range listed.Sorted() {
+		if t.Err != nil {
+			return topicLayout{}, fmt.Errorf("unable to describe topic %q: %v", t.Topic, t.Err)
+		}
+	}
+	var configs kadm.ResourceConfigs
+	if names := listed.Names(); len(names) > 0 {
+		configs, err = adm.DescribeTopicConfigs(ctx, names...)
+		if err != nil {
+			return topicLayout{}, fmt.Errorf("unable to describe topic configs: %v", err)
+		}
+	}
+	return buildLayout(listed, configs)
+}
+
+func buildLayout(listed kadm.TopicDetails, configs kadm.ResourceConfigs) (topicLayout, error) {
+	layout := topicLayout{Version: 1, Topics: []layoutTopic{}}
+	for _, t := range listed.Sorted() {
+		rc, err := configs.On(t.Topic, nil)
+		if err == nil {
+			err = rc.Err
+		}
+		if err != nil {
+			return topicLayout{}, fmt.Errorf("unable to describe configs of topic %q: %v", t.Topic, err)
+		}
+		layout.Topics = append(layout.Topics, layoutTopic{
+			Name:              t.Topic,
+			Partitions:        int32(len(t.Partitions)),
+			ReplicationFactor: int16(t.Partitions.NumReplicas()),
+			Configs:           topicConfigs(rc),
+		})
+	}
+	return layout, nil
+}
+
+// topicConfigs returns the configs that are set on the topic itself. Sensitive
+// configs are skipped, because their values are not returned.
+func topicConfigs(rc kadm.ResourceConfig) map[string]string {
+	configs := make(map[string]string)
+	for _, c := range rc.Configs {
+		if c.Source != kmsg.ConfigSourceDynamicTopicConfig {
+			continue
+		}
+		if c.Sensitive || c.Value == nil {
+			fmt.Fprintf(os.Stderr, "skipping sensitive config %q of topic %q\n", c.Key, rc.Name)
+			continue
+		}
+		configs[c.Key] = *c.Value
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+	return configs
+}
+
+func marshalLayout(layout topicLayout) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(layout); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sortedKeys returns the keys of the configs, sorted.
+func sortedKeys(configs map[string]string) []string {
+	keys := make([]string, 0, len(configs))
+	for k := range configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -25,6 +25,7 @@ func newListCommand(fs afero.Fs) *cobra.Command {
 		detailed bool
 		internal bool
 		re       bool
+		format   out.Format
 	)
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -48,6 +49,9 @@ as such, specifying both -i and -r will exit with failure.
 
 Lastly, --detailed flag (-d) opts in to printing extra per-partition
 information.
+
+With --format json or yaml, the topics are printed as a list of objects, which
+include per-partition details if --detailed is used.
 `,
 		Run: func(cmd *cobra.Command, topics []string) {
 			// The purpose of the regex flag really is for users to
@@ -74,12 +78,15 @@ information.
 
 			listed, err := adm.ListTopicsWithInternal(context.Background(), topics...)
 			out.MaybeDie(err, "unable to request metadata: %v", err)
-			cluster.PrintTopics(listed, internal, detailed)
+			format.Print(cluster.TopicSummaries(listed, internal, detailed), func() {
+				cluster.PrintTopics(listed, internal, detailed)
+			})
 		},
 	}
 
 	cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "Print per-partition information for topics")
 	cmd.Flags().BoolVarP(&internal, "internal", "i", false, "Print internal topics")
 	cmd.Flags().BoolVarP(&re, "regex", "r", false, "Parse topics as regex; list any topic that matches any input topic expression")
+	out.AddFormatFlag(cmd, &format)
 	return cmd
 }
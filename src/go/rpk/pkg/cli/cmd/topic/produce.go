@@ -17,14 +17,18 @@ import (
 	"os"
 	"time"
 
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/serde"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+var errMissingTopic = errors.New("topic to produce to is missing, check --help for produce syntax")
+
 func newProduceCommand(fs afero.Fs) *cobra.Command {
 	var (
 		key        string
@@ -35,9 +39,21 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 		outFormat   string
 		compression string
 		acks        int
+		idempotent  bool
+		batchMax    int32
+		linger      time.Duration
+
+		txnID          string
+		txnTimeout     time.Duration
+		commitRecords  int
+		commitInterval time.Duration
 
 		tombstone bool
 
+		schemaID      int
+		schemaSubject string
+		schemaMessage string
+
 		timeout time.Duration
 	)
 
@@ -71,6 +87,9 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			switch acks {
 			case -1:
 				opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+				if !idempotent {
+					opts = append(opts, kgo.DisableIdempotentWrite())
+				}
 			case 0:
 				opts = append(opts, kgo.RequiredAcks(kgo.NoAck()), kgo.DisableIdempotentWrite())
 			case 1:
@@ -78,6 +97,30 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			default:
 				out.Die("invalid acks %d, only -1, 0, and 1 are supported", acks)
 			}
+			if acks != -1 && idempotent && cmd.Flags().Changed("idempotent") {
+				out.Die("--idempotent requires --acks -1")
+			}
+
+			if txnID != "" {
+				if acks != -1 || !idempotent {
+					out.Die("--transactional-id requires --acks -1 and idempotence")
+				}
+				if commitRecords <= 0 && commitInterval <= 0 {
+					out.Die("--transactional-id requires a positive --commit-records or --commit-interval")
+				}
+				opts = append(opts, kgo.TransactionalID(txnID), kgo.TransactionTimeout(txnTimeout))
+			}
+
+			if batchMax < 0 {
+				out.Die("invalid negative --batch-max-bytes")
+			} else if batchMax > 0 {
+				opts = append(opts, kgo.ProducerBatchMaxBytes(batchMax))
+			}
+			if linger < 0 {
+				out.Die("invalid negative --linger")
+			} else if linger > 0 {
+				opts = append(opts, kgo.ProducerLinger(linger))
+			}
 
 			switch {
 			case timeout == 0:
@@ -121,12 +164,19 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 			cfg, err := p.Load(fs)
 			out.MaybeDie(err, "unable to load config: %v", err)
 
+			var enc *serde.Encoder
+			if schemaID != 0 || schemaSubject != "" {
+				enc, err = newSchemaEncoder(cmd.Context(), fs, cfg, schemaID, schemaSubject, schemaMessage)
+				out.MaybeDie(err, "unable to initialize schema encoder: %v", err)
+			}
+
 			cl, err := kafka.NewFranzClient(fs, p, cfg, opts...)
 			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
 			defer cl.Close()
-			defer cl.Flush(context.Background())
 
-			for {
+			// next reads and prepares the next record to produce,
+			// returning io.EOF once input is exhausted.
+			next := func() (*kgo.Record, error) {
 				r := &kgo.Record{
 					Partition: partition,
 					Headers:   headers,
@@ -135,23 +185,53 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 					r.Key = []byte(key)
 				}
 				if err := inf.ReadRecordInto(r); err != nil {
-					if !errors.Is(err, io.EOF) {
-						fmt.Fprintf(os.Stderr, "record read error: %v\n", err)
+					if errors.Is(err, io.EOF) {
+						return nil, err
 					}
-					return
+					return nil, fmt.Errorf("record read error: %v", err)
 				}
 				if r.Topic == "" && defaultTopic == "" {
-					out.Die("topic to produce to is missing, check --help for produce syntax")
+					return nil, errMissingTopic
 				}
 				if tombstone && len(r.Value) == 0 {
 					r.Value = nil
 				}
+				if enc != nil && r.Value != nil {
+					var err error
+					if r.Value, err = enc.Encode(r.Value); err != nil {
+						return nil, fmt.Errorf("unable to encode value with schema %d: %v", enc.ID(), err)
+					}
+				}
+				return r, nil
+			}
+			produced := func(r *kgo.Record) {
+				if outf != nil {
+					outfBuf = outf.AppendRecord(outfBuf[:0], r)
+					os.Stdout.Write(outfBuf)
+				}
+			}
+
+			if txnID != "" {
+				err := produceTransactionally(cl, next, produced, commitRecords, commitInterval)
+				out.MaybeDieErr(err)
+				return
+			}
+
+			defer cl.Flush(context.Background())
+			for {
+				r, err := next()
+				if err != nil {
+					if errors.Is(err, errMissingTopic) {
+						out.Die("%v", err)
+					}
+					if !errors.Is(err, io.EOF) {
+						fmt.Fprintln(os.Stderr, err)
+					}
+					return
+				}
 				cl.Produce(context.Background(), r, func(r *kgo.Record, err error) {
 					out.MaybeDie(err, "unable to produce record: %v", err)
-					if outf != nil {
-						outfBuf = outf.AppendRecord(outfBuf[:0], r)
-						os.Stdout.Write(outfBuf)
-					}
+					produced(r)
 				})
 			}
 		},
@@ -162,6 +242,14 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 	cmd.Flags().IntVar(&acks, "acks", -1, "Number of acks required for producing (-1=all, 0=none, 1=leader)")
 	cmd.Flags().DurationVar(&timeout, "delivery-timeout", 0, "Per-record delivery timeout, if non-zero, min 1s")
 	cmd.Flags().Int32VarP(&partition, "partition", "p", -1, "Partition to directly produce to, if non-negative (also allows %p parsing to set partitions)")
+	cmd.Flags().BoolVar(&idempotent, "idempotent", true, "Enable idempotent producing (requires --acks -1)")
+	cmd.Flags().Int32Var(&batchMax, "batch-max-bytes", 0, "Maximum size of a record batch before compression, if non-zero (default 1MB)")
+	cmd.Flags().DurationVar(&linger, "linger", 0, "How long to wait for more records before sending a batch, if non-zero")
+
+	cmd.Flags().StringVar(&txnID, "transactional-id", "", "Produce within transactions using this transactional ID")
+	cmd.Flags().DurationVar(&txnTimeout, "transaction-timeout", 40*time.Second, "Transaction timeout, after which the broker aborts an open transaction")
+	cmd.Flags().IntVar(&commitRecords, "commit-records", 1000, "With --transactional-id, commit after this many records, if positive")
+	cmd.Flags().DurationVar(&commitInterval, "commit-interval", 0, "With --transactional-id, commit open transactions on this interval, if positive")
 
 	cmd.Flags().StringVarP(&inFormat, "format", "f", "%v\n", "Input record format")
 	cmd.Flags().StringVarP(
@@ -175,6 +263,10 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 	cmd.Flags().StringVarP(&key, "key", "k", "", "A fixed key to use for each record (parsed input keys take precedence)")
 	cmd.Flags().BoolVarP(&tombstone, "tombstone", "Z", false, "Produce empty values as tombstones")
 
+	cmd.Flags().IntVar(&schemaID, "schema-id", 0, "Schema registry ID of the schema to encode values with")
+	cmd.Flags().StringVar(&schemaSubject, "schema-subject", "", "Schema registry subject whose latest schema to encode values with")
+	cmd.Flags().StringVar(&schemaMessage, "schema-message", "", "Fully qualified protobuf message to encode values as, if not the first message in the schema")
+
 	// Deprecated
 	cmd.Flags().IntVarP(new(int), "num", "n", 1, "")
 	cmd.Flags().MarkDeprecated("num", "Invoke rpk multiple times if you wish to repeat records")
@@ -186,6 +278,36 @@ func newProduceCommand(fs afero.Fs) *cobra.Command {
 	return cmd
 }
 
+func newSchemaEncoder(
+	ctx context.Context,
+	fs afero.Fs,
+	cfg *config.Config,
+	id int,
+	subject string,
+	message string,
+) (*serde.Encoder, error) {
+	if id != 0 && subject != "" {
+		return nil, errors.New("only one of --schema-id and --schema-subject can be specified")
+	}
+	cl, err := schemaregistry.NewClient(fs, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var s schemaregistry.Schema
+	if id != 0 {
+		if s, err = cl.SchemaByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("unable to get schema %d: %v", id, err)
+		}
+	} else {
+		ss, err := cl.SchemaByVersion(ctx, subject, schemaregistry.VersionLatest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the latest schema for subject %q: %v", subject, err)
+		}
+		id, s = ss.ID, ss.Schema
+	}
+	return serde.NewEncoder(ctx, cl, id, s, message)
+}
+
 const helpProduce = `Produce records to a topic.
 
 Producing records reads from STDIN, parses input according to --format, and
@@ -310,4 +432,40 @@ requirement for being able to directly control which partition to produce to.
 You can also specify an output format to write when a record is produced
 successfully. The output format follows the same formatting rules as the topic
 consume command. See that command's help text for a detailed description.
+
+TRANSACTIONS
+
+With --transactional-id, records are produced within transactions. A
+transaction is committed after --commit-records records, after every
+--commit-interval, or once input is exhausted, whichever comes first. If any
+record fails to produce, or if rpk is interrupted, the open transaction is
+aborted and rpk exits with an error. Transactions require --acks -1 and
+idempotence. The output format is written as records are produced, which is
+before their transaction is committed.
+
+Consumers only skip aborted and open transactions if they read committed
+records; see 'rpk topic consume --read-committed'. For example, to produce ten
+records per transaction:
+
+    rpk topic produce foo --transactional-id my-txn --commit-records 10
+
+SCHEMAS
+
+Values can be encoded with a schema from the schema registry by using either
+--schema-id or --schema-subject (which uses the latest schema registered under
+the subject). Each parsed value is expected to be the JSON representation of
+the value, and is encoded and framed with the schema ID before producing:
+
+    Avro       Avro JSON encoding, where unions are wrapped: {"string": "foo"}
+    Protobuf   canonical protobuf JSON; use --schema-message to choose the
+               message if the schema has more than one
+    JSON       the value is validated against the schema as is
+
+Empty values produced as tombstones (with -Z) are not encoded. To talk to the
+schema registry, see the --registry-urls and --registry-tls flags.
+
+For example, to produce Avro values one per line with the schema registered
+for the topic's values:
+
+    rpk topic produce foo --schema-subject foo-value
 `
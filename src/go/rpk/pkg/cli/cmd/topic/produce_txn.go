@@ -0,0 +1,178 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// txnProducer is the subset of *kgo.Client used to produce transactionally.
+type txnProducer interface {
+	BeginTransaction() error
+	Produce(context.Context, *kgo.Record, func(*kgo.Record, error))
+	Flush(context.Context) error
+	AbortBufferedRecords(context.Context) error
+	EndTransaction(context.Context, kgo.TransactionEndTry) error
+}
+
+// produceTransactionally produces records returned from next until it returns
+// an error, grouping records into transactions. A transaction is committed
+// every commitRecords records and every commitInterval, if either is
+// positive, and when next returns io.EOF. Any other error from next, any
+// produce error, and an interrupt abort the open transaction.
+func produceTransactionally(
+	cl txnProducer,
+	next func() (*kgo.Record, error),
+	produced func(*kgo.Record),
+	commitRecords int,
+	commitInterval time.Duration,
+) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	// Reading input blocks, so we read in a goroutine to be able to
+	// commit on an interval and to abort when interrupted.
+	type read struct {
+		r   *kgo.Record
+		err error
+	}
+	reads := make(chan read)
+	go func() {
+		for {
+			r, err := next()
+			reads <- read{r, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var tick <-chan time.Time
+	if commitInterval > 0 {
+		ticker := time.NewTicker(commitInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	t := &txn{cl: cl}
+	for {
+		select {
+		case <-sigs:
+			return t.abort(errors.New("interrupted"))
+
+		case <-tick:
+			if err := t.commit(); err != nil {
+				return err
+			}
+
+		case rd := <-reads:
+			if rd.err != nil {
+				if errors.Is(rd.err, io.EOF) {
+					return t.commit()
+				}
+				return t.abort(rd.err)
+			}
+			if err := t.produce(rd.r, produced); err != nil {
+				return err
+			}
+			if commitRecords > 0 && t.records >= commitRecords {
+				if err := t.commit(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// txn tracks the currently open transaction.
+type txn struct {
+	cl      txnProducer
+	records int
+
+	// errs receives the first produce error in the open transaction.
+	errs chan error
+}
+
+func (t *txn) produce(r *kgo.Record, produced func(*kgo.Record)) error {
+	if t.records == 0 {
+		if err := t.cl.BeginTransaction(); err != nil {
+			return fmt.Errorf("unable to begin transaction: %v", err)
+		}
+		t.errs = make(chan error, 1)
+	}
+	select {
+	case err := <-t.errs:
+		return t.abort(err)
+	default:
+	}
+	t.records++
+	errs := t.errs
+	t.cl.Produce(context.Background(), r, func(r *kgo.Record, err error) {
+		if err != nil {
+			select {
+			case errs <- fmt.Errorf("unable to produce record: %v", err):
+			default:
+			}
+			return
+		}
+		produced(r)
+	})
+	return nil
+}
+
+// commit flushes and commits the open transaction, if any, aborting it
+// instead if any record failed.
+func (t *txn) commit() error {
+	if t.records == 0 {
+		return nil
+	}
+	if err := t.cl.Flush(context.Background()); err != nil {
+		return t.abort(fmt.Errorf("unable to flush records: %v", err))
+	}
+	select {
+	case err := <-t.errs:
+		return t.abort(err)
+	default:
+	}
+	n := t.records
+	t.records = 0
+	if err := t.cl.EndTransaction(context.Background(), kgo.TryCommit); err != nil {
+		return fmt.Errorf("unable to commit transaction of %d records: %v", n, err)
+	}
+	fmt.Fprintf(os.Stderr, "Committed transaction of %d records.\n", n)
+	return nil
+}
+
+// abort aborts the open transaction, if any, and returns an error wrapping
+// why.
+func (t *txn) abort(why error) error {
+	if t.records == 0 {
+		return why
+	}
+	n := t.records
+	t.records = 0
+	if err := t.cl.AbortBufferedRecords(context.Background()); err != nil {
+		return fmt.Errorf("%v; unable to abort buffered records: %v", why, err)
+	}
+	if err := t.cl.EndTransaction(context.Background(), kgo.TryAbort); err != nil {
+		return fmt.Errorf("%v; unable to abort transaction: %v", why, err)
+	}
+	return fmt.Errorf("%v; aborted transaction of %d records", why, n)
+}
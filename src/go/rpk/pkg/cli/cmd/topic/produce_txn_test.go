@@ -0,0 +1,168 @@
+package topic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// fakeTxnProducer records transaction boundaries: each committed or aborted
+// transaction is the list of record values produced within it.
+type fakeTxnProducer struct {
+	failValue string
+
+	inTxn     bool
+	open      []string
+	committed [][]string
+	aborted   [][]string
+}
+
+func (f *fakeTxnProducer) BeginTransaction() error {
+	if f.inTxn {
+		return errors.New("transaction already begun")
+	}
+	f.inTxn = true
+	return nil
+}
+
+func (f *fakeTxnProducer) Produce(_ context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	if !f.inTxn {
+		promise(r, errors.New("not in a transaction"))
+		return
+	}
+	f.open = append(f.open, string(r.Value))
+	if string(r.Value) == f.failValue {
+		promise(r, errors.New("injected failure"))
+		return
+	}
+	promise(r, nil)
+}
+
+func (*fakeTxnProducer) Flush(context.Context) error                { return nil }
+func (*fakeTxnProducer) AbortBufferedRecords(context.Context) error { return nil }
+
+func (f *fakeTxnProducer) EndTransaction(_ context.Context, commit kgo.TransactionEndTry) error {
+	if !f.inTxn {
+		return errors.New("not in a transaction")
+	}
+	if commit {
+		f.committed = append(f.committed, f.open)
+	} else {
+		f.aborted = append(f.aborted, f.open)
+	}
+	f.inTxn, f.open = false, nil
+	return nil
+}
+
+// nextValues returns a next function that returns records with the given
+// values and then the given final error.
+func nextValues(final error, values ...string) func() (*kgo.Record, error) {
+	return func() (*kgo.Record, error) {
+		if len(values) == 0 {
+			return nil, final
+		}
+		v := values[0]
+		values = values[1:]
+		return &kgo.Record{Value: []byte(v)}, nil
+	}
+}
+
+func TestProduceTransactionally(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		values        []string
+		final         error
+		failValue     string
+		commitRecords int
+
+		expCommitted [][]string
+		expAborted   [][]string
+		expProduced  int
+		expErr       bool
+	}{
+		{
+			name:          "commit every two and at EOF",
+			values:        []string{"a", "b", "c", "d", "e"},
+			final:         io.EOF,
+			commitRecords: 2,
+			expCommitted:  [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+			expProduced:   5,
+		},
+		{
+			name:          "no empty transaction at EOF",
+			values:        []string{"a", "b"},
+			final:         io.EOF,
+			commitRecords: 2,
+			expCommitted:  [][]string{{"a", "b"}},
+			expProduced:   2,
+		},
+		{
+			name:          "read error aborts",
+			values:        []string{"a", "b", "c"},
+			final:         errors.New("bad input"),
+			commitRecords: 2,
+			expCommitted:  [][]string{{"a", "b"}},
+			expAborted:    [][]string{{"c"}},
+			expProduced:   3,
+			expErr:        true,
+		},
+		{
+			name:          "produce error aborts",
+			values:        []string{"a", "b", "c", "d"},
+			final:         io.EOF,
+			failValue:     "c",
+			commitRecords: 10,
+			expAborted:    [][]string{{"a", "b", "c"}},
+			expProduced:   2,
+			expErr:        true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cl := &fakeTxnProducer{failValue: test.failValue}
+			var produced int
+			err := produceTransactionally(
+				cl,
+				nextValues(test.final, test.values...),
+				func(*kgo.Record) { produced++ },
+				test.commitRecords,
+				0,
+			)
+			if test.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expCommitted, cl.committed)
+			require.Equal(t, test.expAborted, cl.aborted)
+			require.Equal(t, test.expProduced, produced)
+			require.False(t, cl.inTxn, "transaction left open")
+		})
+	}
+}
+
+func TestProduceTransactionallyInterval(t *testing.T) {
+	cl := &fakeTxnProducer{}
+	values := make(chan string)
+	next := func() (*kgo.Record, error) {
+		v, ok := <-values
+		if !ok {
+			return nil, io.EOF
+		}
+		return &kgo.Record{Value: []byte(v)}, nil
+	}
+	done := make(chan error)
+	go func() {
+		done <- produceTransactionally(cl, next, func(*kgo.Record) {}, 0, 10*time.Millisecond)
+	}()
+	values <- "a"
+	time.Sleep(50 * time.Millisecond)
+	values <- "b"
+	close(values)
+	require.NoError(t, <-done)
+	require.Equal(t, [][]string{{"a"}, {"b"}}, cl.committed)
+}
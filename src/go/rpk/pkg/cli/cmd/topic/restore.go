@@ -0,0 +1,161 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package topic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func newRestoreCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topic       string
+		repartition bool
+		create      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "restore [DIR]",
+		Short: "Restore records dumped with 'rpk topic dump' into a topic",
+		Long: `Restore records dumped with 'rpk topic dump' into a topic.
+
+This command produces every record of a dump directory, in the order they
+were dumped, to the dumped topic or to the topic specified with --topic.
+Record keys, values, headers, and timestamps are preserved; offsets are
+assigned by the topic as records are produced.
+
+By default, every record is produced to the partition it was dumped from,
+which requires the topic to have at least as many partitions as the dumped
+topic. With --repartition, records are instead partitioned by key, as with
+'rpk topic produce'.
+
+If the topic does not exist, it can be created with --create, with as many
+partitions as the dumped topic and the default replication factor.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := args[0]
+			m, err := readDumpManifest(fs, dir)
+			out.MaybeDie(err, "unable to read dump in %s: %v", dir, err)
+			if topic == "" {
+				topic = m.Topic
+			}
+
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			adm, err := kafka.NewAdmin(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize admin kafka client: %v", err)
+			defer adm.Close()
+
+			listed, err := adm.ListTopics(cmd.Context(), topic)
+			out.MaybeDie(err, "unable to request metadata: %v", err)
+			td, exists := listed[topic]
+			var partitions int32
+			switch {
+			case !exists || errors.Is(td.Err, kerr.UnknownTopicOrPartition):
+				if !create {
+					out.Die("topic %q does not exist, use --create to create it", topic)
+				}
+				resps, err := adm.CreateTopics(cmd.Context(), m.Partitions, -1, nil, topic)
+				if err == nil {
+					_, err = resps.On(topic, func(r *kadm.CreateTopicResponse) error { return r.Err })
+				}
+				out.MaybeDie(err, "unable to create topic %q: %v", topic, err)
+				partitions = m.Partitions
+			case td.Err != nil:
+				out.Die("unable to describe topic %q: %v", topic, td.Err)
+			default:
+				partitions = int32(len(td.Partitions))
+			}
+			if !repartition && partitions < m.Partitions {
+				out.Die("topic %q has %d partitions, fewer than the %d dumped partitions; use --repartition to partition records by key", topic, partitions, m.Partitions)
+			}
+
+			opts := []kgo.Opt{kgo.DefaultProduceTopic(topic)}
+			if !repartition {
+				opts = append(opts, kgo.RecordPartitioner(kgo.ManualPartitioner()))
+			}
+			cl, err := kafka.NewFranzClient(fs, p, cfg, opts...)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			n, err := restoreDump(cmd.Context(), fs, dir, m, cl, topic)
+			out.MaybeDie(err, "unable to restore dump after %d record(s): %v", n, err)
+			fmt.Printf("Restored %d record(s) to topic %q.\n", n, topic)
+		},
+	}
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic to restore to (default the dumped topic)")
+	cmd.Flags().BoolVar(&repartition, "repartition", false, "Partition records by key rather than producing to their dumped partition")
+	cmd.Flags().BoolVar(&create, "create", false, "Create the topic if it does not exist")
+	return cmd
+}
+
+// restoreDump produces every record of the dump to the topic, returning the
+// number of records that were produced.
+func restoreDump(
+	ctx context.Context, fs afero.Fs, dir string, m dumpManifest, cl *kgo.Client, topic string,
+) (int64, error) {
+	var (
+		mu       sync.Mutex
+		produced int64
+		firstErr error
+	)
+	promise := func(_ *kgo.Record, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err == nil {
+			produced++
+		}
+	}
+	failed := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr
+	}
+	result := func(err error) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			err = firstErr
+		}
+		return produced, err
+	}
+
+	for _, file := range m.Files {
+		err := readDumpFile(fs, dir, m, file, func(r *kgo.Record) error {
+			if err := failed(); err != nil {
+				return err
+			}
+			r.Topic = topic
+			cl.Produce(ctx, r, promise)
+			return nil
+		})
+		if err != nil {
+			cl.Flush(ctx)
+			return result(err)
+		}
+	}
+	return result(cl.Flush(ctx))
+}
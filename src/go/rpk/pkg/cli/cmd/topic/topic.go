@@ -11,6 +11,7 @@ package topic
 
 import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +27,12 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 		certFile       string
 		keyFile        string
 		truststoreFile string
+
+		registryURLs           []string
+		registryEnableTLS      bool
+		registryCertFile       string
+		registryKeyFile        string
+		registryTruststoreFile string
 	)
 	command := &cobra.Command{
 		Use:   "topic",
@@ -34,15 +41,29 @@ func NewCommand(fs afero.Fs) *cobra.Command {
 
 	common.AddKafkaFlags(command, &configFile, &user, &password, &mechanism, &enableTLS, &certFile, &keyFile, &truststoreFile, &brokers)
 
+	// The schema registry is only used when producing or consuming with
+	// schemas, but the flags are persistent to mirror the Kafka flags.
+	command.PersistentFlags().StringSliceVar(
+		&registryURLs,
+		config.FlagRegistryHosts,
+		[]string{},
+		"Comma-separated list of Schema Registry addresses (<IP>:<port>), for producing or consuming with schemas",
+	)
+	common.AddSchemaRegistryTLSFlags(command, &registryEnableTLS, &registryCertFile, &registryKeyFile, &registryTruststoreFile)
+
 	command.AddCommand(
 		newAddPartitionsCommand(fs),
 		newAlterConfigCommand(fs),
+		newApplyCommand(fs),
 		newConsumeCommand(fs),
 		newCreateCommand(fs),
 		newDeleteCommand(fs),
 		newDescribeCommand(fs),
+		newDumpCommand(fs),
+		newExportCommand(fs),
 		newListCommand(fs),
 		newProduceCommand(fs),
+		newRestoreCommand(fs),
 	)
 
 	return command
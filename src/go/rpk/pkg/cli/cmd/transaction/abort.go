@@ -0,0 +1,152 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newAbortCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topic      string
+		partition  int32
+		producerID int64
+		noConfirm  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "abort",
+		Short: "Abort the open transaction of a producer in a partition",
+		Long: `Abort the open transaction of a producer in a partition.
+
+This writes an abort marker for a single producer to a single partition,
+ending the producer's open transaction in that partition. This unblocks read
+committed consumers that are stuck at the start of a hanging transaction.
+
+This is a last resort. Aborting bypasses the transaction coordinator: if the
+producer's transaction is not actually hanging, the producer may go on to
+commit a transaction that has already been aborted in this partition, breaking
+the atomicity of the transaction. Before aborting, use 'rpk transaction
+describe' to check that the transaction is not ongoing on its coordinator,
+and 'rpk transaction describe-producers' to find the producer ID of the
+hanging transaction.
+
+Before writing the marker, this checks that the producer has an open
+transaction in the partition, prints the producer, and asks for confirmation.
+Use --no-confirm to disable the confirmation prompt.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			ctx := context.Background()
+			described, err := describeProducers(ctx, cl, topic, []int32{partition})
+			out.MaybeDieErr(err)
+			ap, err := findOpenTransaction(described, partition, producerID)
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable("producer-id", "producer-epoch", "coordinator-epoch", "last-timestamp", "txn-start-offset")
+			tw.Print(ap.ProducerID, ap.ProducerEpoch, ap.CoordinatorEpoch, formatMillis(ap.LastTimestamp), ap.CurrentTxnStartOffset)
+			tw.Flush()
+
+			if !noConfirm {
+				confirmed, err := out.Confirm("Abort the above producer's transaction in %s/%d?", topic, partition)
+				out.MaybeDie(err, "unable to confirm abort: %v", err)
+				if !confirmed {
+					out.Exit("Command execution canceled.")
+				}
+			}
+
+			// Markers must be written to the partition leader.
+			topics, err := kadm.NewClient(cl).ListTopics(ctx, topic)
+			out.MaybeDie(err, "unable to request metadata: %v", err)
+			pd, ok := topics[topic].Partitions[partition]
+			if !ok || pd.Leader < 0 {
+				out.Die("unable to find the leader of %s/%d", topic, partition)
+			}
+
+			req := kmsg.NewPtrWriteTxnMarkersRequest()
+			marker := kmsg.NewWriteTxnMarkersRequestMarker()
+			marker.ProducerID = ap.ProducerID
+			marker.ProducerEpoch = int16(ap.ProducerEpoch)
+			marker.Committed = false
+			marker.CoordinatorEpoch = ap.CoordinatorEpoch
+			mt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
+			mt.Topic = topic
+			mt.Partitions = []int32{partition}
+			marker.Topics = append(marker.Topics, mt)
+			req.Markers = append(req.Markers, marker)
+
+			resp, err := req.RequestWith(ctx, cl.Broker(int(pd.Leader)))
+			out.MaybeDie(err, "unable to write abort marker: %v", err)
+			for _, m := range resp.Markers {
+				for _, t := range m.Topics {
+					for _, p := range t.Partitions {
+						if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+							out.Die("unable to write abort marker to %s/%d: %v", t.Topic, p.Partition, err)
+						}
+					}
+				}
+			}
+			fmt.Printf("Aborted the transaction of producer %d in %s/%d.\n", ap.ProducerID, topic, partition)
+		},
+	}
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic of the partition with the hanging transaction")
+	cmd.Flags().Int32VarP(&partition, "partition", "p", -1, "Partition with the hanging transaction")
+	cmd.Flags().Int64Var(&producerID, "producer-id", -1, "Producer ID of the hanging transaction")
+	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "Disable confirmation prompt")
+	cobra.MarkFlagRequired(cmd.Flags(), "topic")
+	cobra.MarkFlagRequired(cmd.Flags(), "partition")
+	cobra.MarkFlagRequired(cmd.Flags(), "producer-id")
+	return cmd
+}
+
+// findOpenTransaction returns the active producer with the given ID in the
+// partition, if the producer has an open transaction.
+func findOpenTransaction(
+	described []partitionProducers, partition int32, producerID int64,
+) (kmsg.DescribeProducersResponseTopicPartitionActiveProducer, error) {
+	var zero kmsg.DescribeProducersResponseTopicPartitionActiveProducer
+	for _, pp := range described {
+		if pp.partition != partition {
+			continue
+		}
+		if pp.err != nil {
+			return zero, fmt.Errorf("unable to describe the producers of partition %d: %v", partition, pp.err)
+		}
+		for _, ap := range pp.producers {
+			if ap.ProducerID != producerID {
+				continue
+			}
+			if ap.CurrentTxnStartOffset < 0 {
+				return zero, fmt.Errorf("producer %d has no open transaction in partition %d", producerID, partition)
+			}
+			return ap, nil
+		}
+		return zero, fmt.Errorf("producer %d is not an active producer of partition %d", producerID, partition)
+	}
+	return zero, errors.New("the partition was not described")
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package transaction
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newDescribeCommand(fs afero.Fs) *cobra.Command {
+	var ongoing bool
+	cmd := &cobra.Command{
+		Use:   "describe [TRANSACTIONAL-IDS...]",
+		Short: "Describe transactional IDs",
+		Long: `Describe transactional IDs.
+
+This prints the state of each transactional ID: the producer ID and epoch
+assigned to it, the transaction timeout, and, if a transaction is open, when it
+started, how long it has been open, and the partitions it has written to.
+
+If no transactional IDs are given, every listed transactional ID is described.
+Use --ongoing to only print IDs with an open transaction. A transaction that
+has been open for longer than its timeout is likely hanging.
+`,
+		Run: func(cmd *cobra.Command, ids []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			ctx := context.Background()
+			if len(ids) == 0 {
+				txns, err := listTransactions(ctx, cl, nil, nil)
+				out.MaybeDieErr(err)
+				for _, t := range txns {
+					ids = append(ids, t.TransactionalID)
+				}
+				if len(ids) == 0 {
+					return
+				}
+			}
+
+			req := kmsg.NewPtrDescribeTransactionsRequest()
+			req.TransactionalIDs = ids
+			type described struct {
+				coordinator int32
+				kmsg.DescribeTransactionsResponseTransactionState
+			}
+			var txns []described
+			shards := cl.RequestSharded(ctx, req)
+			allFailed := kafka.EachShard(req, shards, func(shard kgo.ResponseShard) {
+				resp := shard.Resp.(*kmsg.DescribeTransactionsResponse)
+				for _, t := range resp.TransactionStates {
+					txns = append(txns, described{shard.Meta.NodeID, t})
+				}
+			})
+			if allFailed {
+				out.Die("unable to describe transactions")
+			}
+			sort.Slice(txns, func(i, j int) bool {
+				return txns[i].TransactionalID < txns[j].TransactionalID
+			})
+
+			now := time.Now()
+			tw := out.NewTable(
+				"coordinator",
+				"transactional-id",
+				"producer-id",
+				"producer-epoch",
+				"state",
+				"timeout",
+				"start",
+				"duration",
+				"partitions",
+				"error",
+			)
+			defer tw.Flush()
+			for _, t := range txns {
+				if ongoing && t.StartTimestamp < 0 {
+					continue
+				}
+				duration := "-"
+				if t.StartTimestamp >= 0 {
+					duration = now.Sub(time.UnixMilli(t.StartTimestamp)).Truncate(time.Second).String()
+				}
+				tps := make(map[string][]int32)
+				for _, topic := range t.Topics {
+					tps[topic.Topic] = append(tps[topic.Topic], topic.Partitions...)
+				}
+				var errMsg string
+				if err := kerr.ErrorForCode(t.ErrorCode); err != nil {
+					errMsg = err.Error()
+				}
+				tw.Print(
+					t.coordinator,
+					t.TransactionalID,
+					t.ProducerID,
+					t.ProducerEpoch,
+					t.State,
+					time.Duration(t.TimeoutMillis)*time.Millisecond,
+					formatMillis(t.StartTimestamp),
+					duration,
+					formatTopicPartitions(tps),
+					errMsg,
+				)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&ongoing, "ongoing", false, "Only print transactional IDs with an open transaction")
+	return cmd
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newDescribeProducersCommand(fs afero.Fs) *cobra.Command {
+	var (
+		topic      string
+		partitions []int32
+	)
+	cmd := &cobra.Command{
+		Use:   "describe-producers",
+		Short: "Describe the active producers of partitions",
+		Long: `Describe the active producers of partitions.
+
+This prints every producer that has recently written to the partitions of
+--topic (all partitions, or those selected with --partition). For each
+producer, this prints its ID and epoch, the last sequence number and timestamp
+it wrote, the epoch of its transaction coordinator, and the offset its open
+transaction started at, if it has an open transaction.
+
+The smallest transaction start offset in a partition is the partition's last
+stable offset: read committed consumers cannot read past it. A producer with a
+long open transaction that is no longer listed as ongoing by 'rpk transaction
+describe' is likely hanging.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			described, err := describeProducers(context.Background(), cl, topic, partitions)
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable(
+				"partition",
+				"producer-id",
+				"producer-epoch",
+				"coordinator-epoch",
+				"last-sequence",
+				"last-timestamp",
+				"txn-start-offset",
+				"error",
+			)
+			defer tw.Flush()
+			for _, pp := range described {
+				if pp.err != nil {
+					tw.Print(pp.partition, "-", "-", "-", "-", "-", "-", pp.err)
+					continue
+				}
+				for _, ap := range pp.producers {
+					start := "-"
+					if ap.CurrentTxnStartOffset >= 0 {
+						start = fmt.Sprint(ap.CurrentTxnStartOffset)
+					}
+					tw.Print(
+						pp.partition,
+						ap.ProducerID,
+						ap.ProducerEpoch,
+						ap.CoordinatorEpoch,
+						ap.LastSequence,
+						formatMillis(ap.LastTimestamp),
+						start,
+						"",
+					)
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&topic, "topic", "t", "", "Topic to describe the producers of")
+	cmd.Flags().Int32SliceVarP(&partitions, "partition", "p", nil, "Partitions to describe the producers of, if not all (repeatable)")
+	cobra.MarkFlagRequired(cmd.Flags(), "topic")
+	return cmd
+}
+
+// partitionProducers are the active producers of a partition.
+type partitionProducers struct {
+	partition int32
+	producers []kmsg.DescribeProducersResponseTopicPartitionActiveProducer
+	err       error
+}
+
+// describeProducers describes the active producers of the given partitions of
+// topic, or all partitions if none are given, sorted by partition and then
+// producer ID.
+func describeProducers(
+	ctx context.Context, cl *kgo.Client, topic string, partitions []int32,
+) ([]partitionProducers, error) {
+	if topic == "" {
+		return nil, errors.New("invalid empty topic")
+	}
+	if len(partitions) == 0 {
+		topics, err := kadm.NewClient(cl).ListTopics(ctx, topic)
+		if err != nil {
+			return nil, fmt.Errorf("unable to request metadata: %v", err)
+		}
+		td, ok := topics[topic]
+		if !ok {
+			return nil, fmt.Errorf("topic %q does not exist", topic)
+		}
+		if td.Err != nil {
+			return nil, fmt.Errorf("unable to describe topic %q: %v", topic, td.Err)
+		}
+		partitions = td.Partitions.Numbers()
+	}
+
+	req := kmsg.NewPtrDescribeProducersRequest()
+	rt := kmsg.NewDescribeProducersRequestTopic()
+	rt.Topic = topic
+	rt.Partitions = partitions
+	req.Topics = append(req.Topics, rt)
+
+	var described []partitionProducers
+	shards := cl.RequestSharded(ctx, req)
+	allFailed := kafka.EachShard(req, shards, func(shard kgo.ResponseShard) {
+		resp := shard.Resp.(*kmsg.DescribeProducersResponse)
+		for _, t := range resp.Topics {
+			for _, p := range t.Partitions {
+				pp := partitionProducers{
+					partition: p.Partition,
+					producers: p.ActiveProducers,
+					err:       kerr.ErrorForCode(p.ErrorCode),
+				}
+				if pp.err != nil && p.ErrorMessage != nil {
+					pp.err = fmt.Errorf("%v: %s", pp.err, *p.ErrorMessage)
+				}
+				sort.Slice(pp.producers, func(i, j int) bool {
+					return pp.producers[i].ProducerID < pp.producers[j].ProducerID
+				})
+				described = append(described, pp)
+			}
+		}
+	})
+	if allFailed {
+		return nil, errors.New("unable to describe producers")
+	}
+	sort.Slice(described, func(i, j int) bool {
+		return described[i].partition < described[j].partition
+	})
+	return described, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/kafka"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+type listedTxn struct {
+	coordinator int32
+	kmsg.ListTransactionsResponseTransactionState
+}
+
+func newListCommand(fs afero.Fs) *cobra.Command {
+	var (
+		states      []string
+		producerIDs []int64
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List transactional IDs and their state",
+		Long: `List transactional IDs and their state.
+
+This lists every transactional ID known to each broker, the producer ID
+currently assigned to it, and the state of its transaction. The COORDINATOR
+column is the broker that coordinates transactions for the ID.
+
+Common states are Empty (no transaction), Ongoing (a transaction is open),
+PrepareCommit and PrepareAbort (the transaction is ending), CompleteCommit and
+CompleteAbort (the transaction ended), and Dead.
+
+You can filter the listed IDs by state with --state and by producer ID with
+--producer-id; both flags are repeatable.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			p := config.ParamsFromCommand(cmd)
+			cfg, err := p.Load(fs)
+			out.MaybeDie(err, "unable to load config: %v", err)
+
+			cl, err := kafka.NewFranzClient(fs, p, cfg)
+			out.MaybeDie(err, "unable to initialize kafka client: %v", err)
+			defer cl.Close()
+
+			txns, err := listTransactions(context.Background(), cl, states, producerIDs)
+			out.MaybeDieErr(err)
+
+			tw := out.NewTable("coordinator", "transactional-id", "producer-id", "state")
+			defer tw.Flush()
+			for _, t := range txns {
+				tw.Print(t.coordinator, t.TransactionalID, t.ProducerID, t.TransactionState)
+			}
+		},
+	}
+	cmd.Flags().StringSliceVar(&states, "state", nil, "Only list transactions in these states (repeatable)")
+	cmd.Flags().Int64SliceVar(&producerIDs, "producer-id", nil, "Only list transactions with these producer IDs (repeatable)")
+	return cmd
+}
+
+// listTransactions lists transactions from every broker, sorted by
+// transactional ID. This returns an error only if every broker failed; partial
+// failures are printed.
+func listTransactions(
+	ctx context.Context, cl *kgo.Client, states []string, producerIDs []int64,
+) ([]listedTxn, error) {
+	req := kmsg.NewPtrListTransactionsRequest()
+	req.StateFilters = states
+	req.ProducerIDFilters = producerIDs
+
+	var (
+		txns    []listedTxn
+		unknown = make(map[string]bool)
+	)
+	shards := cl.RequestSharded(ctx, req)
+	allFailed := kafka.EachShard(req, shards, func(shard kgo.ResponseShard) {
+		resp := shard.Resp.(*kmsg.ListTransactionsResponse)
+		if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+			fmt.Printf("(broker %s ListTransactions failure: %v)\n", kafka.MetaString(shard.Meta), err)
+			return
+		}
+		for _, s := range resp.UnknownStateFilters {
+			unknown[s] = true
+		}
+		for _, t := range resp.TransactionStates {
+			txns = append(txns, listedTxn{shard.Meta.NodeID, t})
+		}
+	})
+	if allFailed {
+		return nil, fmt.Errorf("unable to list transactions from any broker")
+	}
+	if len(unknown) > 0 {
+		var us []string
+		for u := range unknown {
+			us = append(us, u)
+		}
+		sort.Strings(us)
+		fmt.Fprintf(os.Stderr, "Unknown state filters: %s\n", strings.Join(us, ", "))
+	}
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].TransactionalID < txns[j].TransactionalID
+	})
+	return txns, nil
+}
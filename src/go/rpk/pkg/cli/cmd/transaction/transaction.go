@@ -0,0 +1,123 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package transaction contains commands to inspect and abort transactions.
+package transaction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand(fs afero.Fs) *cobra.Command {
+	var (
+		brokers        []string
+		configFile     string
+		user           string
+		password       string
+		mechanism      string
+		enableTLS      bool
+		certFile       string
+		keyFile        string
+		truststoreFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "transaction",
+		Aliases: []string{"txn"},
+		Short:   "Inspect and abort transactions",
+		Long: `Inspect and abort transactions.
+
+Transactional producers write records to partitions and then commit or abort
+the transaction by writing a marker to every partition in the transaction.
+Consumers that read committed records (see 'rpk topic consume
+--read-committed') cannot read past the first open transaction in a partition:
+the last stable offset (LSO) of the partition stays at the start of the open
+transaction until the transaction ends.
+
+Usually, a transaction ends quickly, or the broker aborts it once the
+transaction timeout passes. If a producer or coordinator misbehaves, a
+transaction can hang and block read committed consumers indefinitely. These
+commands help you find why the LSO of a partition is stuck:
+
+  * list shows all transactional IDs and their state
+  * describe shows the producer and partitions of transactional IDs, and how
+    long any ongoing transaction has been open
+  * describe-producers shows the active producers of partitions, and the
+    start offset of any transaction a producer has open
+
+If a transaction is hanging, abort can write an abort marker for a single
+producer and partition. This is a last resort; see 'rpk transaction abort
+--help'.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+
+	// backcompat: until we switch to -X, we need these flags.
+	common.AddKafkaFlags(
+		cmd,
+		&configFile,
+		&user,
+		&password,
+		&mechanism,
+		&enableTLS,
+		&certFile,
+		&keyFile,
+		&truststoreFile,
+		&brokers,
+	)
+
+	cmd.AddCommand(
+		newAbortCommand(fs),
+		newDescribeCommand(fs),
+		newDescribeProducersCommand(fs),
+		newListCommand(fs),
+	)
+	return cmd
+}
+
+// formatTopicPartitions formats topics and their partitions as
+// "foo[0,1] bar[2]", sorted by topic and partition.
+func formatTopicPartitions(tps map[string][]int32) string {
+	topics := make([]string, 0, len(tps))
+	for t := range tps {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	var sb strings.Builder
+	for i, t := range topics {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		ps := append([]int32(nil), tps[t]...)
+		sort.Slice(ps, func(i, j int) bool { return ps[i] < ps[j] })
+		strs := make([]string, len(ps))
+		for i, p := range ps {
+			strs[i] = fmt.Sprint(p)
+		}
+		fmt.Fprintf(&sb, "%s[%s]", t, strings.Join(strs, ","))
+	}
+	return sb.String()
+}
+
+// formatMillis formats a Kafka millisecond timestamp, returning "-" for
+// negative (unknown) timestamps.
+func formatMillis(millis int64) string {
+	if millis < 0 {
+		return "-"
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,56 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFormatTopicPartitions(t *testing.T) {
+	require.Equal(t, "", formatTopicPartitions(nil))
+	require.Equal(t, "bar[0] foo[0,1,3]", formatTopicPartitions(map[string][]int32{
+		"foo": {3, 0, 1},
+		"bar": {0},
+	}))
+}
+
+func TestFindOpenTransaction(t *testing.T) {
+	producer := func(id, start int64) kmsg.DescribeProducersResponseTopicPartitionActiveProducer {
+		ap := kmsg.NewDescribeProducersResponseTopicPartitionActiveProducer()
+		ap.ProducerID = id
+		ap.CurrentTxnStartOffset = start
+		return ap
+	}
+	described := []partitionProducers{
+		{partition: 0, producers: []kmsg.DescribeProducersResponseTopicPartitionActiveProducer{
+			producer(1, -1),
+			producer(2, 100),
+		}},
+		{partition: 1, err: errors.New("NOT_LEADER_OR_FOLLOWER")},
+	}
+
+	for _, test := range []struct {
+		name       string
+		partition  int32
+		producerID int64
+		expErr     bool
+	}{
+		{name: "open transaction", partition: 0, producerID: 2},
+		{name: "no open transaction", partition: 0, producerID: 1, expErr: true},
+		{name: "unknown producer", partition: 0, producerID: 3, expErr: true},
+		{name: "partition error", partition: 1, producerID: 2, expErr: true},
+		{name: "undescribed partition", partition: 2, producerID: 2, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ap, err := findOpenTransaction(described, test.partition, test.producerID)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.producerID, ap.ProducerID)
+		})
+	}
+}
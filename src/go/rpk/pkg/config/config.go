@@ -223,6 +223,11 @@ func checkRpkConfig(cfg *Config) []error {
 	if cfg.Rpk.TuneCoredump && cfg.Rpk.CoredumpDir == "" {
 		errs = append(errs, fmt.Errorf("if rpk.tune_coredump is set to true, rpk.coredump_dir can't be empty"))
 	}
+	switch cfg.Rpk.IrqMode {
+	case "", "sq", "sq-split", "mq", "numa":
+	default:
+		errs = append(errs, fmt.Errorf("rpk.irq_mode %q is not one of sq, sq-split, mq or numa", cfg.Rpk.IrqMode))
+	}
 	return errs
 }
 
@@ -902,6 +902,24 @@ func TestCheckConfig(t *testing.T) {
 			},
 			expected: []string{"if rpk.tune_coredump is set to true, rpk.coredump_dir can't be empty"},
 		},
+		{
+			name: "shall return no error when irq_mode is numa",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Rpk.IrqMode = "numa"
+				return c
+			},
+			expected: []string{},
+		},
+		{
+			name: "shall return an error when irq_mode is unknown",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Rpk.IrqMode = "local"
+				return c
+			},
+			expected: []string{`rpk.irq_mode "local" is not one of sq, sq-split, mq or numa`},
+		},
 		{
 			name: "shall return no error if setup is empty," +
 				"but coredump_dir is empty",
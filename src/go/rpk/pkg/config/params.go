@@ -76,6 +76,10 @@ const (
 	// FlagConfig is rpk config flag.
 	FlagConfig = "config"
 
+	// FlagProfile selects the rpk profile to use, overriding the current
+	// profile in the profiles file.
+	FlagProfile = "profile"
+
 	// FlagVerbose opts in to verbose logging. This is to be replaced with
 	// a log-level flag later, with `-v` meaning DEBUG for backcompat.
 	FlagVerbose = "verbose"
@@ -89,6 +93,10 @@ const (
 	FlagTLSCert        = "tls-cert"
 	FlagTLSKey         = "tls-key"
 	FlagSASLMechanism  = "sasl-mechanism"
+	FlagSASLToken      = "sasl-token"
+	FlagSASLTokenFile  = "sasl-token-file"
+	FlagSASLTokenURL   = "sasl-token-url"
+	FlagSASLTokenScope = "sasl-token-scope"
 	FlagSASLUser       = "user"
 	FlagSASLPass       = "password"
 	FlagAdminHosts1    = "hosts"
@@ -98,6 +106,12 @@ const (
 	FlagAdminTLSCert   = "admin-api-tls-cert"
 	FlagAdminTLSKey    = "admin-api-tls-key"
 
+	FlagRegistryHosts     = "registry-urls"
+	FlagEnableRegistryTLS = "registry-tls-enabled"
+	FlagRegistryTLSCA     = "registry-tls-truststore"
+	FlagRegistryTLSCert   = "registry-tls-cert"
+	FlagRegistryTLSKey    = "registry-tls-key"
+
 	EnvBrokers       = "REDPANDA_BROKERS"
 	EnvTLSCA         = "REDPANDA_TLS_TRUSTSTORE"
 	EnvTLSCert       = "REDPANDA_TLS_CERT"
@@ -122,15 +136,25 @@ const (
 	xKafkaClientCert = "kafka.tls.client_cert_path"
 	xKafkaClientKey  = "kafka.tls.client_key_path"
 
-	xKafkaSASLMechanism = "kafka.sasl.mechanism"
-	xKafkaSASLUser      = "kafka.sasl.user"
-	xKafkaSASLPass      = "kafka.sasl.pass"
+	xKafkaSASLMechanism  = "kafka.sasl.mechanism"
+	xKafkaSASLUser       = "kafka.sasl.user"
+	xKafkaSASLPass       = "kafka.sasl.pass"
+	xKafkaSASLToken      = "kafka.sasl.token"
+	xKafkaSASLTokenFile  = "kafka.sasl.token_file"
+	xKafkaSASLTokenURL   = "kafka.sasl.token_url"
+	xKafkaSASLTokenScope = "kafka.sasl.token_scope"
 
 	xAdminHosts      = "admin.hosts"
 	xAdminTLSEnabled = "admin.tls.enabled"
 	xAdminCACert     = "admin.tls.ca_cert_path"
 	xAdminClientCert = "admin.tls.client_cert_path"
 	xAdminClientKey  = "admin.tls.client_key_path"
+
+	xRegistryHosts      = "registry.hosts"
+	xRegistryTLSEnabled = "registry.tls.enabled"
+	xRegistryCACert     = "registry.tls.ca_cert_path"
+	xRegistryClientCert = "registry.tls.client_cert_path"
+	xRegistryClientKey  = "registry.tls.client_key_path"
 )
 
 // DefaultPath is where redpanda's configuration is located by default.
@@ -143,6 +167,15 @@ type Params struct {
 	// This is unused until step (2) in the refactoring process.
 	ConfigPath string
 
+	// Profile is any flag-specified profile name. If empty, the
+	// RPK_PROFILE environment variable is used, and then the current
+	// profile in the profiles file.
+	Profile string
+
+	// ProfilesPath is the path to the profiles file. If empty,
+	// DefaultProfilesPath is used.
+	ProfilesPath string
+
 	// Verbose tracks the -v flag. This will be swapped with --log-level in
 	// the future.
 	Verbose bool
@@ -175,6 +208,10 @@ func ParamsFromCommand(cmd *cobra.Command) *Params {
 				p.ConfigPath = f.Value.String()
 				return
 
+			case FlagProfile:
+				p.Profile = f.Value.String()
+				return
+
 			case FlagVerbose:
 				if b, err := strconv.ParseBool(f.Value.String()); err == nil {
 					p.Verbose = b
@@ -200,6 +237,14 @@ func ParamsFromCommand(cmd *cobra.Command) *Params {
 				key = xKafkaSASLUser
 			case FlagSASLPass:
 				key = xKafkaSASLPass
+			case FlagSASLToken:
+				key = xKafkaSASLToken
+			case FlagSASLTokenFile:
+				key = xKafkaSASLTokenFile
+			case FlagSASLTokenURL:
+				key = xKafkaSASLTokenURL
+			case FlagSASLTokenScope:
+				key = xKafkaSASLTokenScope
 
 			case FlagAdminHosts1, FlagAdminHosts2:
 				key = xAdminHosts
@@ -212,6 +257,18 @@ func ParamsFromCommand(cmd *cobra.Command) *Params {
 				key = xAdminClientCert
 			case FlagAdminTLSKey:
 				key = xAdminClientKey
+
+			case FlagRegistryHosts:
+				key = xRegistryHosts
+				stripBrackets = true
+			case FlagEnableRegistryTLS:
+				key = xRegistryTLSEnabled
+			case FlagRegistryTLSCA:
+				key = xRegistryCACert
+			case FlagRegistryTLSCert:
+				key = xRegistryClientCert
+			case FlagRegistryTLSKey:
+				key = xRegistryClientKey
 			}
 
 			val := f.Value.String()
@@ -235,9 +292,22 @@ func ParamsFromCommand(cmd *cobra.Command) *Params {
 //   - Finds the config file, per the --config flag or the default search set.
 //   - Decodes the config over the default configuration.
 //   - Back-compats any old format into any new format.
+//   - Applies the active rpk profile, if any.
 //   - Processes env and flag overrides.
 //   - Sets unset default values.
 func (p *Params) Load(fs afero.Fs) (*Config, error) {
+	return p.load(fs, true)
+}
+
+// LoadWithoutProfile is Load without applying any rpk profile. Profiles are
+// client connection settings; commands that manage the local redpanda node,
+// such as start, tune and check, use this so that a missing or corrupt
+// profile cannot break them.
+func (p *Params) LoadWithoutProfile(fs afero.Fs) (*Config, error) {
+	return p.load(fs, false)
+}
+
+func (p *Params) load(fs afero.Fs, withProfile bool) (*Config, error) {
 	// If we have a config path loaded (through --config flag) the user
 	// expect to load or create the file from this directory.
 	if p.ConfigPath != "" {
@@ -265,6 +335,11 @@ func (p *Params) Load(fs afero.Fs) (*Config, error) {
 		}
 	}
 	c.backcompat()
+	if withProfile {
+		if err := p.applyProfile(fs, c); err != nil {
+			return nil, err
+		}
+	}
 	if err := p.processOverrides(c); err != nil {
 		return nil, err
 	}
@@ -394,6 +469,9 @@ func (c *Config) backcompat() {
 	if r.AdminAPI.TLS == nil {
 		r.AdminAPI.TLS = r.TLS
 	}
+	if r.SchemaRegistryAPI.TLS == nil {
+		r.SchemaRegistryAPI.TLS = r.TLS
+	}
 }
 
 func splitCommaIntoStrings(in string, dst *[]string) error {
@@ -410,78 +488,9 @@ func splitCommaIntoStrings(in string, dst *[]string) error {
 }
 
 // Process overrides processes env and flag overrides into a config file (so
-// that we result in our priority order: flag, env, file).
+// that we result in our priority order: flag, env, profile, file).
 func (p *Params) processOverrides(c *Config) error {
-	r := &c.Rpk
-	k := &r.KafkaAPI
-	a := &r.AdminAPI
-
-	// We have three "make" functions that initialize pointer values if
-	// necessary.
-	var (
-		mkKafkaTLS = func() {
-			if k.TLS == nil {
-				k.TLS = new(TLS)
-			}
-		}
-		mkSASL = func() {
-			if k.SASL == nil {
-				k.SASL = new(SASL)
-			}
-		}
-		mkAdminTLS = func() {
-			if a.TLS == nil {
-				a.TLS = new(TLS)
-			}
-		}
-	)
-
-	// To override, we lookup any override key (e.g., kafka.tls.enabled or
-	// admin.hosts) into this map. If the key exists, we processes the
-	// value as appropriate (per the value function in the map).
-	fns := map[string]func(string) error{
-		xKafkaBrokers: func(v string) error { return splitCommaIntoStrings(v, &k.Brokers) },
-
-		xKafkaTLSEnabled: func(string) error { mkKafkaTLS(); return nil },
-		xKafkaCACert:     func(v string) error { mkKafkaTLS(); k.TLS.TruststoreFile = v; return nil },
-		xKafkaClientCert: func(v string) error { mkKafkaTLS(); k.TLS.CertFile = v; return nil },
-		xKafkaClientKey:  func(v string) error { mkKafkaTLS(); k.TLS.KeyFile = v; return nil },
-
-		xKafkaSASLMechanism: func(v string) error { mkSASL(); k.SASL.Mechanism = v; return nil },
-		xKafkaSASLUser:      func(v string) error { mkSASL(); k.SASL.User = v; return nil },
-		xKafkaSASLPass:      func(v string) error { mkSASL(); k.SASL.Password = v; return nil },
-
-		xAdminHosts:      func(v string) error { return splitCommaIntoStrings(v, &a.Addresses) },
-		xAdminTLSEnabled: func(string) error { mkAdminTLS(); return nil },
-		xAdminCACert:     func(v string) error { mkAdminTLS(); a.TLS.TruststoreFile = v; return nil },
-		xAdminClientCert: func(v string) error { mkAdminTLS(); a.TLS.CertFile = v; return nil },
-		xAdminClientKey:  func(v string) error { mkAdminTLS(); a.TLS.KeyFile = v; return nil },
-	}
-
-	// The parse function accepts the given overrides (key=value pairs) and
-	// processes each. This is run first for env vars then for flags.
-	parse := func(isEnv bool, kvs []string) error {
-		from := "flag"
-		if isEnv {
-			from = "env"
-		}
-		for _, opt := range kvs {
-			kv := strings.SplitN(opt, "=", 2)
-			if len(kv) != 2 {
-				return fmt.Errorf("%s config: %q is not a key=value", from, opt)
-			}
-			k, v := kv[0], kv[1]
-
-			fn, exists := fns[strings.ToLower(k)]
-			if !exists {
-				return fmt.Errorf("%s config: unknown key %q", from, k)
-			}
-			if err := fn(v); err != nil {
-				return fmt.Errorf("%s config key %q: %s", from, k, err)
-			}
-		}
-		return nil
-	}
+	fns := overrideFns(&c.Rpk)
 
 	var envOverrides []string
 
@@ -525,10 +534,105 @@ func (p *Params) processOverrides(c *Config) error {
 
 	// Finally, we process overrides: first environment variables, and then
 	// flags.
-	if err := parse(true, envOverrides); err != nil {
+	if err := parseOverrides(fns, true, envOverrides); err != nil {
 		return err
 	}
-	return parse(false, p.FlagOverrides)
+	return parseOverrides(fns, false, p.FlagOverrides)
+}
+
+// overrideFns returns a map of override keys (e.g., kafka.tls.enabled or
+// admin.hosts) to functions that process an override value into r.
+func overrideFns(r *RpkConfig) map[string]func(string) error {
+	k := &r.KafkaAPI
+	a := &r.AdminAPI
+	sr := &r.SchemaRegistryAPI
+
+	// We have four "make" functions that initialize pointer values if
+	// necessary.
+	var (
+		mkKafkaTLS = func() {
+			if k.TLS == nil {
+				k.TLS = new(TLS)
+			}
+		}
+		mkSASL = func() {
+			if k.SASL == nil {
+				k.SASL = new(SASL)
+			}
+		}
+		mkAdminTLS = func() {
+			if a.TLS == nil {
+				a.TLS = new(TLS)
+			}
+		}
+		mkRegistryTLS = func() {
+			if sr.TLS == nil {
+				sr.TLS = new(TLS)
+			}
+		}
+	)
+
+	return map[string]func(string) error{
+		xKafkaBrokers: func(v string) error { return splitCommaIntoStrings(v, &k.Brokers) },
+
+		xKafkaTLSEnabled: func(string) error { mkKafkaTLS(); return nil },
+		xKafkaCACert:     func(v string) error { mkKafkaTLS(); k.TLS.TruststoreFile = v; return nil },
+		xKafkaClientCert: func(v string) error { mkKafkaTLS(); k.TLS.CertFile = v; return nil },
+		xKafkaClientKey:  func(v string) error { mkKafkaTLS(); k.TLS.KeyFile = v; return nil },
+
+		xKafkaSASLMechanism: func(v string) error {
+			mech, err := NormalizeSASLMechanism(v)
+			if err != nil {
+				return err
+			}
+			mkSASL()
+			k.SASL.Mechanism = mech
+			return nil
+		},
+		xKafkaSASLUser:       func(v string) error { mkSASL(); k.SASL.User = v; return nil },
+		xKafkaSASLPass:       func(v string) error { mkSASL(); k.SASL.Password = v; return nil },
+		xKafkaSASLToken:      func(v string) error { mkSASL(); k.SASL.Token = v; return nil },
+		xKafkaSASLTokenFile:  func(v string) error { mkSASL(); k.SASL.TokenFile = v; return nil },
+		xKafkaSASLTokenURL:   func(v string) error { mkSASL(); k.SASL.TokenURL = v; return nil },
+		xKafkaSASLTokenScope: func(v string) error { mkSASL(); k.SASL.TokenScope = v; return nil },
+
+		xAdminHosts:      func(v string) error { return splitCommaIntoStrings(v, &a.Addresses) },
+		xAdminTLSEnabled: func(string) error { mkAdminTLS(); return nil },
+		xAdminCACert:     func(v string) error { mkAdminTLS(); a.TLS.TruststoreFile = v; return nil },
+		xAdminClientCert: func(v string) error { mkAdminTLS(); a.TLS.CertFile = v; return nil },
+		xAdminClientKey:  func(v string) error { mkAdminTLS(); a.TLS.KeyFile = v; return nil },
+
+		xRegistryHosts:      func(v string) error { return splitCommaIntoStrings(v, &sr.Addresses) },
+		xRegistryTLSEnabled: func(string) error { mkRegistryTLS(); return nil },
+		xRegistryCACert:     func(v string) error { mkRegistryTLS(); sr.TLS.TruststoreFile = v; return nil },
+		xRegistryClientCert: func(v string) error { mkRegistryTLS(); sr.TLS.CertFile = v; return nil },
+		xRegistryClientKey:  func(v string) error { mkRegistryTLS(); sr.TLS.KeyFile = v; return nil },
+	}
+}
+
+// parseOverrides accepts the given overrides (key=value pairs) and processes
+// each with the matching function in fns.
+func parseOverrides(fns map[string]func(string) error, isEnv bool, kvs []string) error {
+	from := "flag"
+	if isEnv {
+		from = "env"
+	}
+	for _, opt := range kvs {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("%s config: %q is not a key=value", from, opt)
+		}
+		k, v := kv[0], kv[1]
+
+		fn, exists := fns[strings.ToLower(k)]
+		if !exists {
+			return fmt.Errorf("%s config: unknown key %q", from, k)
+		}
+		if err := fn(v); err != nil {
+			return fmt.Errorf("%s config key %q: %s", from, k, err)
+		}
+	}
+	return nil
 }
 
 // As a final step in initializing a config, we add a few defaults to some
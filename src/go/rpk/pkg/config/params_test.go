@@ -209,3 +209,18 @@ pandaproxy: {}
 schema_registry: {}
 `, string(file))
 }
+
+func TestParams_SASLOverrides(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	p := &Params{FlagOverrides: []string{
+		xKafkaSASLMechanism + "=oauthbearer",
+		xKafkaSASLTokenFile + "=/var/run/token",
+	}}
+	cfg, err := p.Load(fs)
+	require.NoError(t, err)
+	require.Equal(t, &SASL{Mechanism: "OAUTHBEARER", TokenFile: "/var/run/token"}, cfg.Rpk.KafkaAPI.SASL)
+
+	p = &Params{FlagOverrides: []string{xKafkaSASLMechanism + "=GSSAPI"}}
+	_, err = p.Load(fs)
+	require.Error(t, err)
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvProfile selects the rpk profile to use if the --profile flag is not set.
+const EnvProfile = "RPK_PROFILE"
+
+// ProfilesFileName is the name of the user-level file that rpk profiles are
+// stored in, within the user's rpk config directory.
+const ProfilesFileName = "profiles.yaml"
+
+// RpkProfile is a named set of connection settings. When a profile is active,
+// any settings it defines take precedence over the rpk section of
+// redpanda.yaml, while env and flag overrides still take precedence over the
+// profile.
+type RpkProfile struct {
+	Name              string               `yaml:"name" json:"name"`
+	Description       string               `yaml:"description,omitempty" json:"description,omitempty"`
+	KafkaAPI          RpkKafkaAPI          `yaml:"kafka_api,omitempty" json:"kafka_api"`
+	AdminAPI          RpkAdminAPI          `yaml:"admin_api,omitempty" json:"admin_api"`
+	SchemaRegistryAPI RpkSchemaRegistryAPI `yaml:"schema_registry_api,omitempty" json:"schema_registry_api"`
+}
+
+// RpkProfiles is the contents of the profiles file.
+type RpkProfiles struct {
+	fileLocation string
+
+	CurrentProfile string       `yaml:"current_profile,omitempty" json:"current_profile"`
+	Profiles       []RpkProfile `yaml:"profiles,omitempty" json:"profiles"`
+}
+
+// DefaultProfilesPath returns the default location of the profiles file,
+// within the user's config directory (e.g. ~/.config/rpk/profiles.yaml).
+func DefaultProfilesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the user config directory: %v", err)
+	}
+	return filepath.Join(dir, "rpk", ProfilesFileName), nil
+}
+
+// LoadProfiles loads the profiles file from the params' profiles path. If the
+// file does not exist, this returns an empty set of profiles that will be
+// written to the path.
+func (p *Params) LoadProfiles(fs afero.Fs) (*RpkProfiles, error) {
+	path, err := p.profilesPath()
+	if err != nil {
+		return nil, err
+	}
+	ps := &RpkProfiles{fileLocation: path}
+	file, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ps, nil
+		}
+		return nil, fmt.Errorf("unable to read profiles file: %v", err)
+	}
+	if err := yaml.Unmarshal(file, ps); err != nil {
+		return nil, fmt.Errorf("unable to yaml decode %s: %v", path, err)
+	}
+	return ps, nil
+}
+
+func (p *Params) profilesPath() (string, error) {
+	if p.ProfilesPath != "" {
+		return p.ProfilesPath, nil
+	}
+	return DefaultProfilesPath()
+}
+
+// ActiveProfileName returns the name of the profile to use: the --profile
+// flag, then the RPK_PROFILE environment variable, and then the current
+// profile in the profiles file. This returns an empty string if no profile is
+// active.
+func (p *Params) ActiveProfileName(ps *RpkProfiles) string {
+	if p.Profile != "" {
+		return p.Profile
+	}
+	if env := os.Getenv(EnvProfile); env != "" {
+		return env
+	}
+	return ps.CurrentProfile
+}
+
+// applyProfile overlays the active profile, if any, on top of the config.
+func (p *Params) applyProfile(fs afero.Fs, c *Config) error {
+	// If we cannot find the user config directory (e.g., $HOME is unset
+	// when running as a service), there are no profiles to apply. This is
+	// only an error if a profile was explicitly requested.
+	if _, err := p.profilesPath(); err != nil {
+		if p.Profile == "" && os.Getenv(EnvProfile) == "" {
+			return nil
+		}
+		return err
+	}
+	ps, err := p.LoadProfiles(fs)
+	if err != nil {
+		return err
+	}
+	name := p.ActiveProfileName(ps)
+	if name == "" {
+		return nil
+	}
+	profile := ps.Profile(name)
+	if profile == nil {
+		return fmt.Errorf("profile %q does not exist in %s", name, ps.fileLocation)
+	}
+	profile.apply(&c.Rpk)
+	return nil
+}
+
+// ProfileFromFlags returns a profile containing only the connection settings
+// from the params' flag overrides.
+func (p *Params) ProfileFromFlags(name string) (*RpkProfile, error) {
+	var r RpkConfig
+	if err := parseOverrides(overrideFns(&r), false, p.FlagOverrides); err != nil {
+		return nil, err
+	}
+	return &RpkProfile{
+		Name:              name,
+		KafkaAPI:          r.KafkaAPI,
+		AdminAPI:          r.AdminAPI,
+		SchemaRegistryAPI: r.SchemaRegistryAPI,
+	}, nil
+}
+
+// apply overlays every setting the profile defines onto r.
+func (rp *RpkProfile) apply(r *RpkConfig) {
+	rp.overlay(&r.KafkaAPI, &r.AdminAPI, &r.SchemaRegistryAPI)
+}
+
+// Merge overlays every setting that other defines onto the profile, keeping
+// the profile's name and, if other has none, its description.
+func (rp *RpkProfile) Merge(other *RpkProfile) {
+	other.overlay(&rp.KafkaAPI, &rp.AdminAPI, &rp.SchemaRegistryAPI)
+	if other.Description != "" {
+		rp.Description = other.Description
+	}
+}
+
+func (rp *RpkProfile) overlay(k *RpkKafkaAPI, a *RpkAdminAPI, sr *RpkSchemaRegistryAPI) {
+	if len(rp.KafkaAPI.Brokers) > 0 {
+		k.Brokers = rp.KafkaAPI.Brokers
+	}
+	if rp.KafkaAPI.TLS != nil {
+		k.TLS = rp.KafkaAPI.TLS
+	}
+	if rp.KafkaAPI.SASL != nil {
+		k.SASL = rp.KafkaAPI.SASL
+	}
+	if len(rp.AdminAPI.Addresses) > 0 {
+		a.Addresses = rp.AdminAPI.Addresses
+	}
+	if rp.AdminAPI.TLS != nil {
+		a.TLS = rp.AdminAPI.TLS
+	}
+	if len(rp.SchemaRegistryAPI.Addresses) > 0 {
+		sr.Addresses = rp.SchemaRegistryAPI.Addresses
+	}
+	if rp.SchemaRegistryAPI.TLS != nil {
+		sr.TLS = rp.SchemaRegistryAPI.TLS
+	}
+}
+
+// FileLocation returns the path the profiles were loaded from, and where they
+// are written to.
+func (ps *RpkProfiles) FileLocation() string {
+	return ps.fileLocation
+}
+
+// Profile returns the profile with the given name, or nil if it does not
+// exist.
+func (ps *RpkProfiles) Profile(name string) *RpkProfile {
+	for i := range ps.Profiles {
+		if ps.Profiles[i].Name == name {
+			return &ps.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// Add adds a profile, returning an error if one with the same name exists.
+func (ps *RpkProfiles) Add(p RpkProfile) error {
+	if p.Name == "" {
+		return errors.New("invalid empty profile name")
+	}
+	if ps.Profile(p.Name) != nil {
+		return fmt.Errorf("profile %q already exists", p.Name)
+	}
+	ps.Profiles = append(ps.Profiles, p)
+	return nil
+}
+
+// Delete deletes the named profile, clearing the current profile if it was
+// the one deleted. This returns whether the profile existed.
+func (ps *RpkProfiles) Delete(name string) bool {
+	for i := range ps.Profiles {
+		if ps.Profiles[i].Name == name {
+			ps.Profiles = append(ps.Profiles[:i], ps.Profiles[i+1:]...)
+			if ps.CurrentProfile == name {
+				ps.CurrentProfile = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the profiles to their file location. Since profiles can
+// contain SASL credentials, the file is only readable by the current user.
+func (ps *RpkProfiles) Write(fs afero.Fs) error {
+	b, err := yaml.Marshal(ps)
+	if err != nil {
+		return fmt.Errorf("marshal error in profiles: %v", err)
+	}
+	if err := fs.MkdirAll(filepath.Dir(ps.fileLocation), 0o755); err != nil {
+		return fmt.Errorf("unable to create profiles directory: %v", err)
+	}
+	temp := ps.fileLocation + ".tmp"
+	if err := afero.WriteFile(fs, temp, b, 0o600); err != nil {
+		return fmt.Errorf("error writing to temporary file: %v", err)
+	}
+	if err := fs.Rename(temp, ps.fileLocation); err != nil {
+		fs.Remove(temp)
+		return fmt.Errorf("unable to write profiles file: %v", err)
+	}
+	return nil
+}
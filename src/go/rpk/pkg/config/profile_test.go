@@ -0,0 +1,249 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testProfilesPath = "/home/rpk/.config/rpk/profiles.yaml"
+
+func TestParams_LoadProfile(t *testing.T) {
+	const redpandaYAML = `rpk:
+    kafka_api:
+        brokers:
+            - file:9092
+        sasl:
+            user: fileuser
+            password: filepass
+            type: SCRAM-SHA-256
+    admin_api:
+        addresses:
+            - file:9644
+`
+	const profilesYAML = `current_profile: dev
+profiles:
+    - name: dev
+      kafka_api:
+        brokers:
+            - dev:9092
+    - name: prod
+      description: production
+      kafka_api:
+        brokers:
+            - prod-0:9092
+            - prod-1:9092
+        tls: {}
+      admin_api:
+        addresses:
+            - prod-0:9644
+`
+	for _, test := range []struct {
+		name      string
+		profile   string
+		env       string
+		overrides []string
+		noProfile bool
+
+		expBrokers []string
+		expAdmin   []string
+		expTLS     bool
+		expErr     bool
+	}{
+		{
+			name:       "current profile overlays the file",
+			expBrokers: []string{"dev:9092"},
+			expAdmin:   []string{"file:9644"},
+		},
+		{
+			name:       "flag selects a profile",
+			profile:    "prod",
+			expBrokers: []string{"prod-0:9092", "prod-1:9092"},
+			expAdmin:   []string{"prod-0:9644"},
+			expTLS:     true,
+		},
+		{
+			name:       "env selects a profile",
+			env:        "prod",
+			expBrokers: []string{"prod-0:9092", "prod-1:9092"},
+			expAdmin:   []string{"prod-0:9644"},
+			expTLS:     true,
+		},
+		{
+			name:       "flag beats env",
+			profile:    "dev",
+			env:        "prod",
+			expBrokers: []string{"dev:9092"},
+			expAdmin:   []string{"file:9644"},
+		},
+		{
+			name:       "overrides beat the profile",
+			profile:    "prod",
+			overrides:  []string{xKafkaBrokers + "=flag:9092"},
+			expBrokers: []string{"flag:9092"},
+			expAdmin:   []string{"prod-0:9644"},
+			expTLS:     true,
+		},
+		{
+			name:       "no profiles file",
+			noProfile:  true,
+			expBrokers: []string{"file:9092"},
+			expAdmin:   []string{"file:9644"},
+		},
+		{
+			name:    "missing profile",
+			profile: "staging",
+			expErr:  true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(EnvProfile, test.env)
+
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, DefaultPath, []byte(redpandaYAML), 0o644)
+			require.NoError(t, err)
+			if !test.noProfile {
+				err = afero.WriteFile(fs, testProfilesPath, []byte(profilesYAML), 0o600)
+				require.NoError(t, err)
+			}
+
+			p := &Params{
+				ProfilesPath:  testProfilesPath,
+				Profile:       test.profile,
+				FlagOverrides: test.overrides,
+			}
+			cfg, err := p.Load(fs)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			k := cfg.Rpk.KafkaAPI
+			require.Equal(t, test.expBrokers, k.Brokers)
+			require.Equal(t, test.expAdmin, cfg.Rpk.AdminAPI.Addresses)
+			require.Equal(t, test.expTLS, k.TLS != nil)
+
+			// No profile overrides SASL, so it always comes from
+			// the file.
+			require.NotNil(t, k.SASL)
+			require.Equal(t, "fileuser", k.SASL.User)
+
+			// Writing the config must not persist the profile into
+			// redpanda.yaml.
+			require.Equal(t, []string{"file:9092"}, cfg.FileOrDefaults().Rpk.KafkaAPI.Brokers)
+		})
+	}
+}
+
+func TestParams_LoadWithoutProfile(t *testing.T) {
+	const redpandaYAML = `rpk:
+    kafka_api:
+        brokers:
+            - file:9092
+`
+	const profilesYAML = `current_profile: prod
+profiles:
+    - name: prod
+      kafka_api:
+        brokers:
+            - prod:9092
+        sasl:
+            user: produser
+            password: prodpass
+            type: SCRAM-SHA-256
+`
+	t.Setenv(EnvProfile, "")
+
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, DefaultPath, []byte(redpandaYAML), 0o644)
+	require.NoError(t, err)
+	err = afero.WriteFile(fs, testProfilesPath, []byte(profilesYAML), 0o600)
+	require.NoError(t, err)
+
+	p := &Params{ProfilesPath: testProfilesPath}
+	cfg, err := p.Load(fs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod:9092"}, cfg.Rpk.KafkaAPI.Brokers)
+
+	cfg, err = p.LoadWithoutProfile(fs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"file:9092"}, cfg.Rpk.KafkaAPI.Brokers)
+	require.Nil(t, cfg.Rpk.KafkaAPI.SASL)
+
+	// Writing the loaded config, as 'rpk redpanda start' does, must not
+	// persist any profile settings.
+	require.NoError(t, cfg.FileOrDefaults().Write(fs))
+	file, err := afero.ReadFile(fs, DefaultPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(file), "prod")
+
+	// A corrupt or missing profile breaks client commands, but not
+	// commands that do not use profiles.
+	err = afero.WriteFile(fs, testProfilesPath, []byte("^&notyaml"), 0o600)
+	require.NoError(t, err)
+	_, err = p.Load(fs)
+	require.Error(t, err)
+	_, err = p.LoadWithoutProfile(fs)
+	require.NoError(t, err)
+
+	p.Profile = "staging"
+	_, err = p.Load(fs)
+	require.Error(t, err)
+	_, err = p.LoadWithoutProfile(fs)
+	require.NoError(t, err)
+}
+
+func TestRpkProfiles(t *testing.T) {
+	t.Setenv(EnvProfile, "")
+	fs := afero.NewMemMapFs()
+	p := &Params{ProfilesPath: testProfilesPath}
+
+	ps, err := p.LoadProfiles(fs)
+	require.NoError(t, err)
+	require.Empty(t, ps.Profiles)
+	require.Equal(t, testProfilesPath, ps.FileLocation())
+
+	p.FlagOverrides = []string{
+		xKafkaBrokers + "=a:9092,b:9092",
+		xKafkaSASLUser + "=user",
+		xKafkaSASLPass + "=pass",
+		xKafkaSASLMechanism + "=SCRAM-SHA-512",
+	}
+	profile, err := p.ProfileFromFlags("foo")
+	require.NoError(t, err)
+	require.NoError(t, ps.Add(*profile))
+	require.Error(t, ps.Add(*profile), "adding a duplicate profile should fail")
+	require.Error(t, ps.Add(RpkProfile{}), "adding an unnamed profile should fail")
+	require.NoError(t, ps.Add(RpkProfile{Name: "bar"}))
+	ps.CurrentProfile = "foo"
+	require.NoError(t, ps.Write(fs))
+
+	info, err := fs.Stat(testProfilesPath)
+	require.NoError(t, err)
+	require.Equal(t, "-rw-------", info.Mode().String())
+
+	p.FlagOverrides = nil
+	ps, err = p.LoadProfiles(fs)
+	require.NoError(t, err)
+	require.Equal(t, "foo", p.ActiveProfileName(ps))
+	got := ps.Profile("foo")
+	require.NotNil(t, got)
+	require.Equal(t, []string{"a:9092", "b:9092"}, got.KafkaAPI.Brokers)
+	require.Equal(t, &SASL{User: "user", Password: "pass", Mechanism: "SCRAM-SHA-512"}, got.KafkaAPI.SASL)
+
+	got.Merge(&RpkProfile{
+		Description: "merged",
+		AdminAPI:    RpkAdminAPI{Addresses: []string{"a:9644"}},
+	})
+	require.Equal(t, "merged", got.Description)
+	require.Equal(t, []string{"a:9092", "b:9092"}, got.KafkaAPI.Brokers)
+	require.Equal(t, []string{"a:9644"}, got.AdminAPI.Addresses)
+
+	require.True(t, ps.Delete("foo"))
+	require.False(t, ps.Delete("foo"))
+	require.Equal(t, "", ps.CurrentProfile)
+	require.Nil(t, ps.Profile("foo"))
+	require.NotNil(t, ps.Profile("bar"))
+}
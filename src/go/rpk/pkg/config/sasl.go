@@ -0,0 +1,67 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The SASL mechanisms that rpk supports for the Kafka API.
+const (
+	SASLMechanismPlain       = "PLAIN"
+	SASLMechanismScramSha256 = "SCRAM-SHA-256"
+	SASLMechanismScramSha512 = "SCRAM-SHA-512"
+	SASLMechanismOAuthBearer = "OAUTHBEARER"
+	SASLMechanismAWSMSKIAM   = "AWS_MSK_IAM"
+)
+
+// SASLMechanisms returns the SASL mechanisms that rpk supports.
+func SASLMechanisms() []string {
+	return []string{
+		SASLMechanismScramSha256,
+		SASLMechanismScramSha512,
+		SASLMechanismPlain,
+		SASLMechanismOAuthBearer,
+		SASLMechanismAWSMSKIAM,
+	}
+}
+
+// NormalizeSASLMechanism returns the canonical, upper case name of a
+// supported SASL mechanism, or an error if the mechanism is not supported. An
+// empty mechanism is returned as is.
+func NormalizeSASLMechanism(mechanism string) (string, error) {
+	if mechanism == "" {
+		return "", nil
+	}
+	upper := strings.ToUpper(mechanism)
+	for _, m := range SASLMechanisms() {
+		if m == upper {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported SASL mechanism %q, supported: %s", mechanism, strings.Join(SASLMechanisms(), ", "))
+}
+
+// BasicCredentials returns the SASL user and password to use as HTTP basic
+// auth credentials for the admin API and schema registry. With OAUTHBEARER
+// and AWS_MSK_IAM, the user and password are an OAuth client ID and secret or
+// an AWS access key and secret, which are not basic credentials, so this
+// returns empty credentials. A nil SASL has no credentials either.
+func (s *SASL) BasicCredentials() (user, password string) {
+	if s == nil {
+		return "", ""
+	}
+	switch strings.ToUpper(s.Mechanism) {
+	case SASLMechanismOAuthBearer, SASLMechanismAWSMSKIAM:
+		return "", ""
+	}
+	return s.User, s.Password
+}
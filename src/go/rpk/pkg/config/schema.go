@@ -161,30 +161,32 @@ type RpkConfig struct {
 	// Deprecated 2021-07-1
 	SASL *SASL `yaml:"sasl,omitempty" json:"sasl,omitempty"`
 
-	KafkaAPI                 RpkKafkaAPI `yaml:"kafka_api,omitempty" json:"kafka_api"`
-	AdminAPI                 RpkAdminAPI `yaml:"admin_api,omitempty" json:"admin_api"`
-	AdditionalStartFlags     []string    `yaml:"additional_start_flags,omitempty"  json:"additional_start_flags"`
-	EnableUsageStats         bool        `yaml:"enable_usage_stats,omitempty" json:"enable_usage_stats"`
-	TuneNetwork              bool        `yaml:"tune_network,omitempty" json:"tune_network"`
-	TuneDiskScheduler        bool        `yaml:"tune_disk_scheduler,omitempty" json:"tune_disk_scheduler"`
-	TuneNomerges             bool        `yaml:"tune_disk_nomerges,omitempty" json:"tune_disk_nomerges"`
-	TuneDiskWriteCache       bool        `yaml:"tune_disk_write_cache,omitempty" json:"tune_disk_write_cache"`
-	TuneDiskIrq              bool        `yaml:"tune_disk_irq,omitempty" json:"tune_disk_irq"`
-	TuneFstrim               bool        `yaml:"tune_fstrim,omitempty" json:"tune_fstrim"`
-	TuneCPU                  bool        `yaml:"tune_cpu,omitempty" json:"tune_cpu"`
-	TuneAioEvents            bool        `yaml:"tune_aio_events,omitempty" json:"tune_aio_events"`
-	TuneClocksource          bool        `yaml:"tune_clocksource,omitempty" json:"tune_clocksource"`
-	TuneSwappiness           bool        `yaml:"tune_swappiness,omitempty" json:"tune_swappiness"`
-	TuneTransparentHugePages bool        `yaml:"tune_transparent_hugepages,omitempty" json:"tune_transparent_hugepages"`
-	EnableMemoryLocking      bool        `yaml:"enable_memory_locking,omitempty" json:"enable_memory_locking"`
-	TuneCoredump             bool        `yaml:"tune_coredump,omitempty" json:"tune_coredump"`
-	CoredumpDir              string      `yaml:"coredump_dir,omitempty" json:"coredump_dir"`
-	TuneBallastFile          bool        `yaml:"tune_ballast_file,omitempty" json:"tune_ballast_file"`
-	BallastFilePath          string      `yaml:"ballast_file_path,omitempty" json:"ballast_file_path"`
-	BallastFileSize          string      `yaml:"ballast_file_size,omitempty" json:"ballast_file_size"`
-	WellKnownIo              string      `yaml:"well_known_io,omitempty" json:"well_known_io"`
-	Overprovisioned          bool        `yaml:"overprovisioned,omitempty" json:"overprovisioned"`
-	SMP                      *int        `yaml:"smp,omitempty" json:"smp,omitempty"`
+	KafkaAPI                 RpkKafkaAPI          `yaml:"kafka_api,omitempty" json:"kafka_api"`
+	AdminAPI                 RpkAdminAPI          `yaml:"admin_api,omitempty" json:"admin_api"`
+	SchemaRegistryAPI        RpkSchemaRegistryAPI `yaml:"schema_registry_api,omitempty" json:"schema_registry_api"`
+	AdditionalStartFlags     []string             `yaml:"additional_start_flags,omitempty"  json:"additional_start_flags"`
+	EnableUsageStats         bool                 `yaml:"enable_usage_stats,omitempty" json:"enable_usage_stats"`
+	TuneNetwork              bool                 `yaml:"tune_network,omitempty" json:"tune_network"`
+	TuneDiskScheduler        bool                 `yaml:"tune_disk_scheduler,omitempty" json:"tune_disk_scheduler"`
+	TuneNomerges             bool                 `yaml:"tune_disk_nomerges,omitempty" json:"tune_disk_nomerges"`
+	TuneDiskWriteCache       bool                 `yaml:"tune_disk_write_cache,omitempty" json:"tune_disk_write_cache"`
+	TuneDiskIrq              bool                 `yaml:"tune_disk_irq,omitempty" json:"tune_disk_irq"`
+	IrqMode                  string               `yaml:"irq_mode,omitempty" json:"irq_mode"`
+	TuneFstrim               bool                 `yaml:"tune_fstrim,omitempty" json:"tune_fstrim"`
+	TuneCPU                  bool                 `yaml:"tune_cpu,omitempty" json:"tune_cpu"`
+	TuneAioEvents            bool                 `yaml:"tune_aio_events,omitempty" json:"tune_aio_events"`
+	TuneClocksource          bool                 `yaml:"tune_clocksource,omitempty" json:"tune_clocksource"`
+	TuneSwappiness           bool                 `yaml:"tune_swappiness,omitempty" json:"tune_swappiness"`
+	TuneTransparentHugePages bool                 `yaml:"tune_transparent_hugepages,omitempty" json:"tune_transparent_hugepages"`
+	EnableMemoryLocking      bool                 `yaml:"enable_memory_locking,omitempty" json:"enable_memory_locking"`
+	TuneCoredump             bool                 `yaml:"tune_coredump,omitempty" json:"tune_coredump"`
+	CoredumpDir              string               `yaml:"coredump_dir,omitempty" json:"coredump_dir"`
+	TuneBallastFile          bool                 `yaml:"tune_ballast_file,omitempty" json:"tune_ballast_file"`
+	BallastFilePath          string               `yaml:"ballast_file_path,omitempty" json:"ballast_file_path"`
+	BallastFileSize          string               `yaml:"ballast_file_size,omitempty" json:"ballast_file_size"`
+	WellKnownIo              string               `yaml:"well_known_io,omitempty" json:"well_known_io"`
+	Overprovisioned          bool                 `yaml:"overprovisioned,omitempty" json:"overprovisioned"`
+	SMP                      *int                 `yaml:"smp,omitempty" json:"smp,omitempty"`
 }
 
 type RpkKafkaAPI struct {
@@ -198,10 +200,24 @@ type RpkAdminAPI struct {
 	TLS       *TLS     `yaml:"tls,omitempty" json:"tls"`
 }
 
+type RpkSchemaRegistryAPI struct {
+	Addresses []string `yaml:"addresses,omitempty" json:"addresses"`
+	TLS       *TLS     `yaml:"tls,omitempty" json:"tls"`
+}
+
 type SASL struct {
 	User      string `yaml:"user,omitempty" json:"user,omitempty"`
 	Password  string `yaml:"password,omitempty" json:"password,omitempty"`
 	Mechanism string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// The following fields are used with OAUTHBEARER; at most one of
+	// Token, TokenFile, or TokenURL can be set. With TokenURL, User and
+	// Password are used as the OAuth client ID and secret. With
+	// AWS_MSK_IAM, Token is the optional session token.
+	Token      string `yaml:"token,omitempty" json:"token,omitempty"`
+	TokenFile  string `yaml:"token_file,omitempty" json:"token_file,omitempty"`
+	TokenURL   string `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+	TokenScope string `yaml:"token_scope,omitempty" json:"token_scope,omitempty"`
 }
 
 func (c *Config) PIDFile() string {
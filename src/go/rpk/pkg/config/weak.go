@@ -378,30 +378,32 @@ func (rpkc *RpkConfig) UnmarshalYAML(n *yaml.Node) error {
 		// Deprecated 2021-07-1
 		SASL *SASL `yaml:"sasl"`
 
-		KafkaAPI                 RpkKafkaAPI     `yaml:"kafka_api"`
-		AdminAPI                 RpkAdminAPI     `yaml:"admin_api"`
-		AdditionalStartFlags     weakStringArray `yaml:"additional_start_flags"`
-		EnableUsageStats         weakBool        `yaml:"enable_usage_stats"`
-		TuneNetwork              weakBool        `yaml:"tune_network"`
-		TuneDiskScheduler        weakBool        `yaml:"tune_disk_scheduler"`
-		TuneNomerges             weakBool        `yaml:"tune_disk_nomerges"`
-		TuneDiskWriteCache       weakBool        `yaml:"tune_disk_write_cache"`
-		TuneDiskIrq              weakBool        `yaml:"tune_disk_irq"`
-		TuneFstrim               weakBool        `yaml:"tune_fstrim"`
-		TuneCPU                  weakBool        `yaml:"tune_cpu"`
-		TuneAioEvents            weakBool        `yaml:"tune_aio_events"`
-		TuneClocksource          weakBool        `yaml:"tune_clocksource"`
-		TuneSwappiness           weakBool        `yaml:"tune_swappiness"`
-		TuneTransparentHugePages weakBool        `yaml:"tune_transparent_hugepages"`
-		EnableMemoryLocking      weakBool        `yaml:"enable_memory_locking"`
-		TuneCoredump             weakBool        `yaml:"tune_coredump"`
-		CoredumpDir              weakString      `yaml:"coredump_dir"`
-		TuneBallastFile          weakBool        `yaml:"tune_ballast_file"`
-		BallastFilePath          weakString      `yaml:"ballast_file_path"`
-		BallastFileSize          weakString      `yaml:"ballast_file_size"`
-		WellKnownIo              weakString      `yaml:"well_known_io"`
-		Overprovisioned          weakBool        `yaml:"overprovisioned"`
-		SMP                      *weakInt        `yaml:"smp"`
+		KafkaAPI                 RpkKafkaAPI          `yaml:"kafka_api"`
+		AdminAPI                 RpkAdminAPI          `yaml:"admin_api"`
+		SchemaRegistryAPI        RpkSchemaRegistryAPI `yaml:"schema_registry_api"`
+		AdditionalStartFlags     weakStringArray      `yaml:"additional_start_flags"`
+		EnableUsageStats         weakBool             `yaml:"enable_usage_stats"`
+		TuneNetwork              weakBool             `yaml:"tune_network"`
+		TuneDiskScheduler        weakBool             `yaml:"tune_disk_scheduler"`
+		TuneNomerges             weakBool             `yaml:"tune_disk_nomerges"`
+		TuneDiskWriteCache       weakBool             `yaml:"tune_disk_write_cache"`
+		TuneDiskIrq              weakBool             `yaml:"tune_disk_irq"`
+		IrqMode                  weakString           `yaml:"irq_mode"`
+		TuneFstrim               weakBool             `yaml:"tune_fstrim"`
+		TuneCPU                  weakBool             `yaml:"tune_cpu"`
+		TuneAioEvents            weakBool             `yaml:"tune_aio_events"`
+		TuneClocksource          weakBool             `yaml:"tune_clocksource"`
+		TuneSwappiness           weakBool             `yaml:"tune_swappiness"`
+		TuneTransparentHugePages weakBool             `yaml:"tune_transparent_hugepages"`
+		EnableMemoryLocking      weakBool             `yaml:"enable_memory_locking"`
+		TuneCoredump             weakBool             `yaml:"tune_coredump"`
+		CoredumpDir              weakString           `yaml:"coredump_dir"`
+		TuneBallastFile          weakBool             `yaml:"tune_ballast_file"`
+		BallastFilePath          weakString           `yaml:"ballast_file_path"`
+		BallastFileSize          weakString           `yaml:"ballast_file_size"`
+		WellKnownIo              weakString           `yaml:"well_known_io"`
+		Overprovisioned          weakBool             `yaml:"overprovisioned"`
+		SMP                      *weakInt             `yaml:"smp"`
 	}
 	if err := n.Decode(&internal); err != nil {
 		return err
@@ -411,6 +413,7 @@ func (rpkc *RpkConfig) UnmarshalYAML(n *yaml.Node) error {
 	rpkc.SASL = internal.SASL
 	rpkc.KafkaAPI = internal.KafkaAPI
 	rpkc.AdminAPI = internal.AdminAPI
+	rpkc.SchemaRegistryAPI = internal.SchemaRegistryAPI
 	rpkc.AdditionalStartFlags = internal.AdditionalStartFlags
 	rpkc.EnableUsageStats = bool(internal.EnableUsageStats)
 	rpkc.TuneNetwork = bool(internal.TuneNetwork)
@@ -418,6 +421,7 @@ func (rpkc *RpkConfig) UnmarshalYAML(n *yaml.Node) error {
 	rpkc.TuneNomerges = bool(internal.TuneNomerges)
 	rpkc.TuneDiskWriteCache = bool(internal.TuneDiskWriteCache)
 	rpkc.TuneDiskIrq = bool(internal.TuneDiskIrq)
+	rpkc.IrqMode = string(internal.IrqMode)
 	rpkc.TuneFstrim = bool(internal.TuneFstrim)
 	rpkc.TuneCPU = bool(internal.TuneCPU)
 	rpkc.TuneAioEvents = bool(internal.TuneAioEvents)
@@ -464,6 +468,19 @@ func (r *RpkAdminAPI) UnmarshalYAML(n *yaml.Node) error {
 	return nil
 }
 
+func (r *RpkSchemaRegistryAPI) UnmarshalYAML(n *yaml.Node) error {
+	var internal struct {
+		Addresses weakStringArray `yaml:"addresses"`
+		TLS       *TLS            `yaml:"tls"`
+	}
+	if err := n.Decode(&internal); err != nil {
+		return err
+	}
+	r.Addresses = internal.Addresses
+	r.TLS = internal.TLS
+	return nil
+}
+
 func (p *Pandaproxy) UnmarshalYAML(n *yaml.Node) error {
 	var internal struct {
 		PandaproxyAPI           namedSocketAddresses   `yaml:"pandaproxy_api"`
@@ -647,9 +664,13 @@ func (t *TLS) UnmarshalYAML(n *yaml.Node) error {
 
 func (s *SASL) UnmarshalYAML(n *yaml.Node) error {
 	var internal struct {
-		User      weakString `yaml:"user"`
-		Password  weakString `yaml:"password"`
-		Mechanism weakString `yaml:"type"`
+		User       weakString `yaml:"user"`
+		Password   weakString `yaml:"password"`
+		Mechanism  weakString `yaml:"type"`
+		Token      weakString `yaml:"token"`
+		TokenFile  weakString `yaml:"token_file"`
+		TokenURL   weakString `yaml:"token_url"`
+		TokenScope weakString `yaml:"token_scope"`
 	}
 	if err := n.Decode(&internal); err != nil {
 		return err
@@ -657,6 +678,10 @@ func (s *SASL) UnmarshalYAML(n *yaml.Node) error {
 	s.User = string(internal.User)
 	s.Password = string(internal.Password)
 	s.Mechanism = string(internal.Mechanism)
+	s.Token = string(internal.Token)
+	s.TokenFile = string(internal.TokenFile)
+	s.TokenURL = string(internal.TokenURL)
+	s.TokenScope = string(internal.TokenScope)
 
 	return nil
 }
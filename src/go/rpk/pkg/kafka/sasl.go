@@ -0,0 +1,188 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/spf13/afero"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/aws"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// saslMechanisms maps each supported SASL mechanism to a function that builds
+// the mechanism from the SASL config.
+var saslMechanisms = map[string]func(afero.Fs, *config.SASL) (sasl.Mechanism, error){
+	config.SASLMechanismScramSha256: func(_ afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+		return scram.Auth{User: s.User, Pass: s.Password}.AsSha256Mechanism(), nil
+	},
+	config.SASLMechanismScramSha512: func(_ afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+		return scram.Auth{User: s.User, Pass: s.Password}.AsSha512Mechanism(), nil
+	},
+	config.SASLMechanismPlain: func(_ afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+		return plain.Auth{User: s.User, Pass: s.Password}.AsMechanism(), nil
+	},
+	config.SASLMechanismOAuthBearer: newOAuthMechanism,
+	config.SASLMechanismAWSMSKIAM: func(_ afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+		return aws.Auth{
+			AccessKey:    s.User,
+			SecretKey:    s.Password,
+			SessionToken: s.Token,
+		}.AsManagedStreamingIAMMechanism(), nil
+	},
+}
+
+// saslMechanism returns the SASL mechanism to use for the given config, or nil
+// if the config has no mechanism.
+func saslMechanism(fs afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+	if s == nil || s.Mechanism == "" {
+		return nil, nil
+	}
+	name, err := config.NormalizeSASLMechanism(s.Mechanism)
+	if err != nil {
+		return nil, err
+	}
+	return saslMechanisms[name](fs, s)
+}
+
+// newOAuthMechanism returns an OAUTHBEARER mechanism that uses a static token,
+// a token read from a file, or a token from a client credentials endpoint.
+func newOAuthMechanism(fs afero.Fs, s *config.SASL) (sasl.Mechanism, error) {
+	var sources int
+	for _, v := range []string{s.Token, s.TokenFile, s.TokenURL} {
+		if v != "" {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return nil, errors.New("OAUTHBEARER requires a token, token file, or token URL")
+	case sources > 1:
+		return nil, errors.New("only one of a token, token file, or token URL can be used with OAUTHBEARER")
+	}
+
+	switch {
+	case s.Token != "":
+		return oauth.Auth{Token: s.Token}.AsMechanism(), nil
+
+	case s.TokenFile != "":
+		// We read the file on every authentication so that tokens
+		// rotated by an external process are picked up.
+		return oauth.Oauth(func(context.Context) (oauth.Auth, error) {
+			b, err := afero.ReadFile(fs, s.TokenFile)
+			if err != nil {
+				return oauth.Auth{}, fmt.Errorf("unable to read OAUTHBEARER token file: %v", err)
+			}
+			token := strings.TrimSpace(string(b))
+			if token == "" {
+				return oauth.Auth{}, fmt.Errorf("OAUTHBEARER token file %q is empty", s.TokenFile)
+			}
+			return oauth.Auth{Token: token}, nil
+		}), nil
+
+	default:
+		if s.User == "" {
+			return nil, errors.New("OAUTHBEARER with a token URL requires a user (the OAuth client ID)")
+		}
+		ts := &clientCredentialsSource{
+			url:      s.TokenURL,
+			clientID: s.User,
+			secret:   s.Password,
+			scope:    s.TokenScope,
+			cl:       &http.Client{Timeout: 10 * time.Second},
+		}
+		return oauth.Oauth(func(ctx context.Context) (oauth.Auth, error) {
+			token, err := ts.token(ctx)
+			return oauth.Auth{Token: token}, err
+		}), nil
+	}
+}
+
+// clientCredentialsSource issues OAuth 2.0 client credentials grant requests
+// (RFC 6749 section 4.4) and caches the token until shortly before it expires.
+type clientCredentialsSource struct {
+	url      string
+	clientID string
+	secret   string
+	scope    string
+	cl       *http.Client
+
+	mu      sync.Mutex
+	cached  string
+	expires time.Time
+}
+
+// tokenExpiryBuffer is how long before a token's expiry we request a new one,
+// so that a token does not expire mid authentication.
+const tokenExpiryBuffer = 10 * time.Second
+
+func (c *clientCredentialsSource) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached != "" && (c.expires.IsZero() || time.Now().Before(c.expires)) {
+		return c.cached, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if c.scope != "" {
+		form.Set("scope", c.scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("unable to create token request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.secret))
+
+	res, err := c.cl.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to request OAuth token from %s: %v", c.url, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read OAuth token response: %v", err)
+	}
+	if res.StatusCode/100 != 2 {
+		return "", fmt.Errorf("OAuth token request to %s failed: %s, body: %q", c.url, http.StatusText(res.StatusCode), body)
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("unable to decode OAuth token response: %v", err)
+	}
+	if resp.AccessToken == "" {
+		return "", errors.New("OAuth token response did not contain an access_token")
+	}
+
+	c.cached = resp.AccessToken
+	c.expires = time.Time{}
+	if resp.ExpiresIn > 0 {
+		c.expires = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - tokenExpiryBuffer)
+	}
+	return c.cached, nil
+}
@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSASLMechanism(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		sasl    *config.SASL
+		expName string
+		expErr  bool
+	}{
+		{name: "no sasl"},
+		{name: "no mechanism", sasl: &config.SASL{User: "u", Password: "p"}},
+		{name: "scram 256", sasl: &config.SASL{User: "u", Password: "p", Mechanism: "scram-sha-256"}, expName: "SCRAM-SHA-256"},
+		{name: "scram 512", sasl: &config.SASL{User: "u", Password: "p", Mechanism: "SCRAM-SHA-512"}, expName: "SCRAM-SHA-512"},
+		{name: "plain", sasl: &config.SASL{User: "u", Password: "p", Mechanism: "plain"}, expName: "PLAIN"},
+		{name: "oauth token", sasl: &config.SASL{Mechanism: "OAUTHBEARER", Token: "t"}, expName: "OAUTHBEARER"},
+		{name: "aws", sasl: &config.SASL{User: "u", Password: "p", Mechanism: "AWS_MSK_IAM"}, expName: "AWS_MSK_IAM"},
+		{name: "unknown", sasl: &config.SASL{Mechanism: "GSSAPI"}, expErr: true},
+		{name: "oauth no token", sasl: &config.SASL{Mechanism: "OAUTHBEARER"}, expErr: true},
+		{name: "oauth two tokens", sasl: &config.SASL{Mechanism: "OAUTHBEARER", Token: "t", TokenFile: "/f"}, expErr: true},
+		{name: "oauth url no client", sasl: &config.SASL{Mechanism: "OAUTHBEARER", TokenURL: "http://localhost"}, expErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			mech, err := saslMechanism(afero.NewMemMapFs(), test.sasl)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.expName == "" {
+				require.Nil(t, mech)
+				return
+			}
+			require.Equal(t, test.expName, mech.Name())
+		})
+	}
+}
+
+// initialOAuth authenticates with the mechanism and returns the token from the
+// initial client message.
+func initialOAuth(t *testing.T, s *config.SASL, fs afero.Fs) string {
+	mech, err := saslMechanism(fs, s)
+	require.NoError(t, err)
+	_, msg, err := mech.Authenticate(context.Background(), "localhost:9092")
+	require.NoError(t, err)
+	const prefix = "auth=Bearer "
+	i := strings.Index(string(msg), prefix)
+	require.NotEqual(t, -1, i, "bearer token not found in %q", msg)
+	token := string(msg[i+len(prefix):])
+	return token[:strings.IndexByte(token, '\x01')]
+}
+
+func TestOAuthTokenFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := &config.SASL{Mechanism: "OAUTHBEARER", TokenFile: "/token"}
+
+	_, err := saslMechanism(fs, s)
+	require.NoError(t, err, "a missing file should only error on authentication")
+
+	require.NoError(t, afero.WriteFile(fs, "/token", []byte("first\n"), 0o600))
+	require.Equal(t, "first", initialOAuth(t, s, fs))
+
+	// The file is re-read, so rotated tokens are used.
+	require.NoError(t, afero.WriteFile(fs, "/token", []byte("second"), 0o600))
+	require.Equal(t, "second", initialOAuth(t, s, fs))
+}
+
+func TestOAuthClientCredentials(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "kafka", r.PostForm.Get("scope"))
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, requests)
+	}))
+	defer ts.Close()
+
+	s := &config.SASL{
+		Mechanism:  "OAUTHBEARER",
+		User:       "client",
+		Password:   "secret",
+		TokenURL:   ts.URL,
+		TokenScope: "kafka",
+	}
+	mech, err := saslMechanism(afero.NewMemMapFs(), s)
+	require.NoError(t, err)
+
+	// The token is cached until it nears expiry.
+	for i := 0; i < 3; i++ {
+		_, msg, err := mech.Authenticate(context.Background(), "localhost:9092")
+		require.NoError(t, err)
+		require.Contains(t, string(msg), "auth=Bearer token-1\x01")
+	}
+	require.Equal(t, 1, requests)
+
+	s.Password = "wrong"
+	mech, err = saslMechanism(afero.NewMemMapFs(), s)
+	require.NoError(t, err)
+	_, _, err = mech.Authenticate(context.Background(), "localhost:9092")
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package out
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the output format of a command's result.
+type Format string
+
+// The supported output formats. Text is the default, human readable (often
+// tabular) output; json and yaml are meant for scripts.
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Formats returns all supported output formats.
+func Formats() []Format { return []Format{FormatText, FormatJSON, FormatYAML} }
+
+// AddFormatFlag adds a --format flag to the command that is parsed into f,
+// defaulting to text.
+func AddFormatFlag(cmd *cobra.Command, f *Format) {
+	*f = FormatText
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	cmd.Flags().Var(f, "format", fmt.Sprintf("Output format (%s)", strings.Join(names, ", ")))
+}
+
+// String implements pflag.Value.
+func (f *Format) String() string { return string(*f) }
+
+// Type implements pflag.Value.
+func (*Format) Type() string { return "format" }
+
+// Set implements pflag.Value, returning an error if s is not a supported
+// format.
+func (f *Format) Set(s string) error {
+	for _, known := range Formats() {
+		if strings.EqualFold(s, string(known)) {
+			*f = known
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q", s)
+}
+
+// IsText returns whether the format is the default human readable format.
+func (f Format) IsText() bool { return f == FormatText || f == "" }
+
+// Marshal encodes v as json or yaml. Both encodings use v's json struct tags,
+// such that json and yaml output always have the same field names in the same
+// order. Marshal returns an error for the text format.
+func (f Format) Marshal(v interface{}) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatYAML:
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		// JSON is valid YAML: decoding into a node keeps the json
+		// field order, and we drop the flow (json) style so that
+		// the node is re-encoded as block yaml.
+		var n yaml.Node
+		if err := yaml.Unmarshal(j, &n); err != nil {
+			return nil, err
+		}
+		blockStyle(&n)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&n); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unable to marshal in format %q", f)
+	}
+}
+
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// Print writes v to stdout in the format. For the text format, printText is
+// called instead. Print exits the process if v cannot be encoded.
+func (f Format) Print(v interface{}, printText func()) {
+	f.Fprint(os.Stdout, v, printText)
+}
+
+// Fprint is Print writing to w.
+func (f Format) Fprint(w io.Writer, v interface{}, printText func()) {
+	if f.IsText() {
+		printText()
+		return
+	}
+	b, err := f.Marshal(v)
+	MaybeDie(err, "unable to encode output as %s: %v", f, err)
+	w.Write(b)
+}
@@ -0,0 +1,71 @@
+package out
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	type inner struct {
+		ID    int      `json:"id"`
+		Names []string `json:"names,omitempty"`
+	}
+	v := struct {
+		Zed    string  `json:"zed"`
+		Alpha  string  `json:"alpha"`
+		Inners []inner `json:"inners"`
+		Empty  *inner  `json:"empty,omitempty"`
+	}{
+		Zed:    "true",
+		Alpha:  "a b",
+		Inners: []inner{{ID: 1, Names: []string{"x", "123"}}, {ID: 2}},
+	}
+
+	b, err := FormatJSON.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "zed": "true",
+  "alpha": "a b",
+  "inners": [
+    {
+      "id": 1,
+      "names": [
+        "x",
+        "123"
+      ]
+    },
+    {
+      "id": 2
+    }
+  ]
+}
+`, string(b))
+
+	b, err = FormatYAML.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `zed: "true"
+alpha: a b
+inners:
+  - id: 1
+    names:
+      - x
+      - "123"
+  - id: 2
+`, string(b))
+
+	_, err = FormatText.Marshal(v)
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	var printed bool
+	FormatText.Fprint(&buf, v, func() { printed = true })
+	require.True(t, printed)
+	require.Empty(t, buf.String())
+
+	var f Format
+	require.NoError(t, f.Set("YAML"))
+	require.Equal(t, FormatYAML, f)
+	require.Error(t, f.Set("xml"))
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package remote
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/out"
+)
+
+// DriftTable is a table with a row per item (such as a check or a tuner), a
+// column per host with the item's value on the host, and a last column with
+// whether the value drifts, that is, differs across the hosts.
+type DriftTable struct {
+	headers []string
+	hosts   []string
+	rows    [][]string
+}
+
+// NewDriftTable returns a table whose rows start with the given headers,
+// followed by a column per host.
+func NewDriftTable(hosts []string, headers ...string) *DriftTable {
+	return &DriftTable{headers: headers, hosts: hosts}
+}
+
+// Add adds a row with the leading values, and the value on each host. Hosts
+// that are missing from values are shown as "-" and do not drift.
+func (t *DriftTable) Add(leading []string, values map[string]string) {
+	row := append([]string(nil), leading...)
+	var (
+		first string
+		seen  bool
+		drift bool
+	)
+	for _, host := range t.hosts {
+		v, ok := values[host]
+		if !ok {
+			row = append(row, "-")
+			continue
+		}
+		if seen && v != first {
+			drift = true
+		}
+		if !seen {
+			first, seen = v, true
+		}
+		row = append(row, v)
+	}
+	if drift {
+		row = append(row, "yes")
+	} else {
+		row = append(row, "no")
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Print prints the table to stdout.
+func (t *DriftTable) Print() { t.Fprint(os.Stdout) }
+
+// Fprint prints the table to w. Unlike the item headers, host names are not
+// uppercased.
+func (t *DriftTable) Fprint(w io.Writer) {
+	tw := out.NewTabWriterTo(w)
+	defer tw.Flush()
+	var headers []interface{}
+	for _, h := range t.headers {
+		headers = append(headers, strings.ToUpper(h))
+	}
+	for _, h := range t.hosts {
+		headers = append(headers, h)
+	}
+	tw.Print(append(headers, "DRIFT")...)
+	for _, row := range t.rows {
+		cells := make([]interface{}, len(row))
+		for i, c := range row {
+			cells[i] = c
+		}
+		tw.Print(cells...)
+	}
+}
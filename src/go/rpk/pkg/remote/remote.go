@@ -0,0 +1,308 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package remote runs rpk commands on remote hosts over SSH.
+//
+// Tuners and checkers decide what to change by reading the /proc and /sys
+// files of the host they run on, so rather than executing the commands of
+// the local host remotely, the same rpk command is run on every host and its
+// json output is aggregated.
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Config is how to connect to the remote hosts and run rpk on them.
+type Config struct {
+	Hosts                 []string
+	User                  string
+	Port                  int
+	IdentityFile          string
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+	Timeout               time.Duration
+	Parallel              int
+	Rpk                   string
+	Sudo                  bool
+}
+
+// flagNames are the flags that AddFlags adds, which are not forwarded to the
+// remote command.
+var flagNames = map[string]bool{
+	"hosts":                        true,
+	"ssh-user":                     true,
+	"ssh-port":                     true,
+	"ssh-key":                      true,
+	"ssh-known-hosts":              true,
+	"ssh-insecure-ignore-host-key": true,
+	"ssh-timeout":                  true,
+	"parallel":                     true,
+	"remote-rpk":                   true,
+	"sudo":                         true,
+	"format":                       true,
+}
+
+// AddFlags adds the --hosts flag and the flags to connect to the hosts to the
+// command.
+func AddFlags(cmd *cobra.Command, c *Config) {
+	f := cmd.Flags()
+	f.StringSliceVar(&c.Hosts, "hosts", nil, "Comma separated list of [user@]host[:port] to run on over SSH, rather than the local host")
+	f.StringVar(&c.User, "ssh-user", os.Getenv("USER"), "SSH user for hosts that do not specify one")
+	f.IntVar(&c.Port, "ssh-port", 22, "SSH port for hosts that do not specify one")
+	f.StringVar(&c.IdentityFile, "ssh-key", "", "SSH private key file (default ~/.ssh/id_ed25519, id_ecdsa or id_rsa, and the ssh-agent)")
+	f.StringVar(&c.KnownHostsFile, "ssh-known-hosts", "", "SSH known hosts file (default ~/.ssh/known_hosts)")
+	f.BoolVar(&c.InsecureIgnoreHostKey, "ssh-insecure-ignore-host-key", false, "Do not verify the host keys of the hosts")
+	f.DurationVar(&c.Timeout, "ssh-timeout", 10*time.Second, "Timeout to connect to a host")
+	f.IntVar(&c.Parallel, "parallel", 10, "Maximum number of hosts to run on at once")
+	f.StringVar(&c.Rpk, "remote-rpk", "rpk", "Path to rpk on the hosts")
+	f.BoolVar(&c.Sudo, "sudo", false, "Run rpk on the hosts with 'sudo -n'")
+}
+
+// Result is the result of running rpk on a host.
+type Result struct {
+	Host     string
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	// Err is a failure to connect or run rpk; a non-zero exit code is
+	// not an error.
+	Err error
+}
+
+// Decode decodes the json output of rpk into v. If rpk did not output json,
+// the error has what rpk wrote to stderr, such as why it failed.
+func (r Result) Decode(v interface{}) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if err := json.Unmarshal(r.Stdout, v); err != nil {
+		msg := strings.TrimSpace(string(r.Stderr))
+		if msg == "" {
+			msg = strings.TrimSpace(string(r.Stdout))
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("rpk exited with code %d: %s", r.ExitCode, msg)
+	}
+	return nil
+}
+
+// Args returns the arguments to run cmd remotely with: the command path
+// without the root command, args, every flag that was set except the ones
+// added by AddFlags and the excluded ones, and "--format json".
+func Args(cmd *cobra.Command, args []string, exclude ...string) []string {
+	remote := strings.Fields(cmd.CommandPath())[1:]
+	remote = append(remote, args...)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if flagNames[f.Name] {
+			return
+		}
+		for _, name := range exclude {
+			if f.Name == name {
+				return
+			}
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				remote = append(remote, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		remote = append(remote, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return append(remote, "--format", "json")
+}
+
+// Run runs rpk with args on every host, at most Parallel at a time, and
+// returns the results in the order of the hosts.
+func (c *Config) Run(fs afero.Fs, args []string) ([]Result, error) {
+	cfg, err := c.clientConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+	command := c.command(args)
+	log.Debugf("Running %q on %v", command, c.Hosts)
+
+	parallel := c.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	var (
+		results = make([]Result, len(c.Hosts))
+		sem     = make(chan struct{}, parallel)
+		wg      sync.WaitGroup
+	)
+	for i, host := range c.Hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host string) {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = c.run(*cfg, host, command)
+		}(i, host)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (c *Config) run(cfg ssh.ClientConfig, host, command string) Result {
+	r := Result{Host: host}
+	addr := host
+	if at := strings.LastIndex(addr, "@"); at >= 0 {
+		cfg.User, addr = addr[:at], addr[at+1:]
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), strconv.Itoa(c.Port))
+	}
+	client, err := ssh.Dial("tcp", addr, &cfg)
+	if err != nil {
+		r.Err = fmt.Errorf("unable to connect: %v", err)
+		return r
+	}
+	defer client.Close()
+	session, err := client.NewSession()
+	if err != nil {
+		r.Err = fmt.Errorf("unable to start session: %v", err)
+		return r
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	err = session.Run(command)
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		r.ExitCode = exitErr.ExitStatus()
+		err = nil
+	}
+	r.Stdout, r.Stderr, r.Err = stdout.Bytes(), stderr.Bytes(), err
+	return r
+}
+
+func (c *Config) command(args []string) string {
+	words := []string{c.Rpk}
+	if c.Sudo {
+		words = []string{"sudo", "-n", c.Rpk}
+	}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+var shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_./:=,@%+-]+$`)
+
+func shellQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (c *Config) clientConfig(fs afero.Fs) (*ssh.ClientConfig, error) {
+	auth, err := c.authMethods(fs)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if !c.InsecureIgnoreHostKey {
+		path := c.KnownHostsFile
+		if path == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("unable to find the known hosts file: %v", err)
+			}
+			path = filepath.Join(home, ".ssh", "known_hosts")
+		}
+		hostKeyCallback, err = knownhosts.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read known hosts file: %v", err)
+		}
+	}
+	return &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         c.Timeout,
+	}, nil
+}
+
+func (c *Config) authMethods(fs afero.Fs) ([]ssh.AuthMethod, error) {
+	var signers []ssh.Signer
+	if c.IdentityFile != "" {
+		signer, err := readSigner(fs, c.IdentityFile)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			signer, err := readSigner(fs, filepath.Join(home, ".ssh", name))
+			if err != nil {
+				log.Debugf("Skipping SSH key: %v", err)
+				continue
+			}
+			signers = append(signers, signer)
+		}
+	}
+
+	var methods []ssh.AuthMethod
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			log.Debugf("Skipping ssh-agent: %v", err)
+		} else {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no SSH key found: use --ssh-key or an ssh-agent")
+	}
+	return methods, nil
+}
+
+func readSigner(fs afero.Fs, path string) (ssh.Signer, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SSH key: %v", err)
+	}
+	signer, err := ssh.ParsePrivateKey(raw)
+	var passErr *ssh.PassphraseMissingError
+	if errors.As(err, &passErr) {
+		return nil, fmt.Errorf("SSH key %s is protected by a passphrase: add it to an ssh-agent", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SSH key %s: %v", path, err)
+	}
+	return signer, nil
+}
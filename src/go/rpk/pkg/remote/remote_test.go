@@ -0,0 +1,267 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package remote_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/remote"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type execResult struct {
+	stdout, stderr string
+	code           uint32
+}
+
+// sshServer is an in-process SSH server that runs exec requests with a
+// handler rather than a shell.
+type sshServer struct {
+	addr    string
+	hostKey ssh.PublicKey
+
+	mu       sync.Mutex
+	commands map[string]string // user => last command
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, ssh.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return key, signer
+}
+
+// startServer starts a server that accepts clientKey, and returns the path of
+// clientKey's private key file in fs.
+func startServer(
+	t *testing.T, fs afero.Fs, handler func(user, cmd string) execResult,
+) (*sshServer, string) {
+	_, hostSigner := newKey(t)
+	clientKey, clientSigner := newKey(t)
+	der, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	keyFile := "/home/rpk/.ssh/id_ecdsa"
+	require.NoError(t, afero.WriteFile(fs, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), clientSigner.PublicKey().Marshal()) {
+				return nil, ssh.ErrNoAuth
+			}
+			return nil, nil
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	s := &sshServer{addr: ln.Addr().String(), hostKey: hostSigner.PublicKey(), commands: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, cfg, handler)
+		}
+	}()
+	return s, keyFile
+}
+
+func (s *sshServer) serve(conn net.Conn, cfg *ssh.ServerConfig, handler func(user, cmd string) execResult) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer sconn.Close()
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		ch, reqs, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer ch.Close()
+			for req := range reqs {
+				if req.Type != "exec" {
+					req.Reply(false, nil)
+					continue
+				}
+				var exec struct{ Command string }
+				if err := ssh.Unmarshal(req.Payload, &exec); err != nil {
+					req.Reply(false, nil)
+					return
+				}
+				req.Reply(true, nil)
+				s.mu.Lock()
+				s.commands[sconn.User()] = exec.Command
+				s.mu.Unlock()
+
+				res := handler(sconn.User(), exec.Command)
+				ch.Write([]byte(res.stdout))
+				ch.Stderr().Write([]byte(res.stderr))
+				ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{res.code}))
+				return
+			}
+		}()
+	}
+}
+
+func TestRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, keyFile := startServer(t, fs, func(user, _ string) execResult {
+		if user == "warn" {
+			return execResult{stdout: `{"passed":false}`, code: 2}
+		}
+		return execResult{stdout: `{"passed":true}`}
+	})
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(s.addr)}, s.hostKey)
+	require.NoError(t, os.WriteFile(knownHosts, []byte(line+"\n"), 0o600))
+
+	_, port, err := net.SplitHostPort(s.addr)
+	require.NoError(t, err)
+	cfg := remote.Config{
+		Hosts:          []string{s.addr, "warn@" + s.addr, "127.0.0.1:1"},
+		User:           "redpanda",
+		IdentityFile:   keyFile,
+		KnownHostsFile: knownHosts,
+		Timeout:        5 * time.Second,
+		Parallel:       2,
+		Rpk:            "/opt/rpk",
+		Sudo:           true,
+	}
+	results, err := cfg.Run(fs, []string{"redpanda", "check", "--config=/etc/my redpanda.yaml", "--format", "json"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, s.addr, results[0].Host)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, 0, results[0].ExitCode)
+	var report struct{ Passed bool }
+	require.NoError(t, results[0].Decode(&report))
+	require.True(t, report.Passed)
+
+	require.NoError(t, results[1].Err)
+	require.Equal(t, 2, results[1].ExitCode)
+	require.NoError(t, results[1].Decode(&report))
+	require.False(t, report.Passed)
+
+	require.Error(t, results[2].Err)
+	require.Error(t, results[2].Decode(&report))
+
+	require.Equal(t, `sudo -n /opt/rpk redpanda check '--config=/etc/my redpanda.yaml' --format json`, s.commands["redpanda"])
+	require.Contains(t, s.commands, "warn")
+
+	// A host whose key is not known is rejected.
+	cfg.Hosts = []string{"localhost:" + port}
+	results, err = cfg.Run(fs, []string{"redpanda", "check"})
+	require.NoError(t, err)
+	require.Error(t, results[0].Err)
+
+	// Unless host keys are not verified.
+	cfg.InsecureIgnoreHostKey = true
+	results, err = cfg.Run(fs, []string{"redpanda", "check"})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+}
+
+func TestRunMissingKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	cfg := remote.Config{Hosts: []string{"host"}, IdentityFile: "/missing", InsecureIgnoreHostKey: true}
+	_, err := cfg.Run(afero.NewMemMapFs(), []string{"redpanda", "check"})
+	require.Error(t, err)
+}
+
+func TestResultDecode(t *testing.T) {
+	var v struct{}
+	err := remote.Result{Stdout: []byte("not json"), Stderr: []byte("Error: unknown flag: --format\n"), ExitCode: 1}.Decode(&v)
+	require.EqualError(t, err, "rpk exited with code 1: Error: unknown flag: --format")
+}
+
+func TestArgs(t *testing.T) {
+	var (
+		cfg    remote.Config
+		config string
+		disks  []string
+		dry    bool
+		junit  string
+	)
+	root := &cobra.Command{Use: "rpk"}
+	redpanda := &cobra.Command{Use: "redpanda"}
+	cmd := &cobra.Command{Use: "tune", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().StringVar(&config, "config", "", "")
+	cmd.Flags().StringSliceVar(&disks, "disks", nil, "")
+	cmd.Flags().BoolVar(&dry, "dry", false, "")
+	cmd.Flags().StringVar(&junit, "junit", "", "")
+	cmd.Flags().String("format", "text", "")
+	remote.AddFlags(cmd, &cfg)
+	root.AddCommand(redpanda)
+	redpanda.AddCommand(cmd)
+
+	root.SetArgs([]string{
+		"redpanda", "tune", "disk_irq,net",
+		"--hosts", "a,b", "--ssh-user", "admin", "--format", "text",
+		"--config", "/etc/redpanda/redpanda.yaml", "--disks", "sda,sdb", "--dry", "--junit", "/report.xml",
+	})
+	require.NoError(t, root.Execute())
+	require.Equal(t, []string{"a", "b"}, cfg.Hosts)
+
+	args := remote.Args(cmd, []string{"disk_irq,net"}, "junit")
+	require.Equal(t, []string{
+		"redpanda", "tune", "disk_irq,net",
+		"--config=/etc/redpanda/redpanda.yaml",
+		"--disks=sda",
+		"--disks=sdb",
+		"--dry=true",
+		"--format", "json",
+	}, args)
+}
+
+func TestDriftTable(t *testing.T) {
+	table := remote.NewDriftTable([]string{"node-a", "node-b"}, "tuner")
+	table.Add([]string{"aio_events"}, map[string]string{"node-a": "applied", "node-b": "applied"})
+	table.Add([]string{"net"}, map[string]string{"node-a": "applied", "node-b": "failed"})
+	table.Add([]string{"cpu"}, map[string]string{"node-a": "applied"})
+
+	var buf bytes.Buffer
+	table.Fprint(&buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"TUNER       node-a   node-b   DRIFT",
+		"aio_events  applied  applied  no",
+		"net         applied  failed   yes",
+		"cpu         applied  -        no",
+	}, lines)
+}
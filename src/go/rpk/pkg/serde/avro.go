@@ -0,0 +1,54 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package serde
+
+import (
+	"errors"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+)
+
+// Avro values are converted to and from Avro's JSON encoding, meaning union
+// values are wrapped in an object keyed by the union branch's type, e.g.
+// {"string": "foo"}.
+type avroCodec struct {
+	c *goavro.Codec
+}
+
+func newAvroCodec(s schemaregistry.Schema) (*avroCodec, error) {
+	if len(s.References) > 0 {
+		return nil, errors.New("avro schemas with references are not supported")
+	}
+	c, err := goavro.NewCodec(s.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return &avroCodec{c}, nil
+}
+
+func (a *avroCodec) encode(text []byte, _ []int) ([]byte, error) {
+	native, _, err := a.c.NativeFromTextual(text)
+	if err != nil {
+		return nil, err
+	}
+	return a.c.BinaryFromNative(nil, native)
+}
+
+func (a *avroCodec) decode(b []byte, _ []int) ([]byte, error) {
+	native, rem, err := a.c.NativeFromBinary(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rem) > 0 {
+		return nil, errTrailingBytes
+	}
+	return a.c.TextualFromNative(nil, native)
+}
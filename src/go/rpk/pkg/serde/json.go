@@ -0,0 +1,94 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package serde
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// The schema and its references are added to the compiler as in-memory
+// resources under this base URL, so that relative references resolve against
+// each other and nothing is ever loaded from the network or filesystem.
+const jsonSchemaBase = "rpk://registry/"
+
+// JSON Schema values are already JSON; encoding validates the value against
+// the schema and decoding only ensures the value is valid JSON.
+type jsonCodec struct {
+	s *jsonschema.Schema
+}
+
+func newJSONCodec(
+	ctx context.Context, f SchemaFetcher, s schemaregistry.Schema,
+) (*jsonCodec, error) {
+	refs, err := resolveReferences(ctx, f, s.References)
+	if err != nil {
+		return nil, err
+	}
+
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("unable to load %q: only schema references are supported", s)
+	}
+	base, _ := url.Parse(jsonSchemaBase)
+	for name, ref := range refs {
+		u, err := base.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference name %q: %v", name, err)
+		}
+		if err := c.AddResource(u.String(), strings.NewReader(ref)); err != nil {
+			return nil, err
+		}
+	}
+	root := jsonSchemaBase + "schema.json"
+	if err := c.AddResource(root, strings.NewReader(s.Schema)); err != nil {
+		return nil, err
+	}
+	compiled, err := c.Compile(root)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonCodec{compiled}, nil
+}
+
+func (j *jsonCodec) encode(text []byte, _ []int) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid json: %v", err)
+	}
+	if dec.More() {
+		return nil, errTrailingBytes
+	}
+	if err := j.s.Validate(v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, text); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (*jsonCodec) decode(b []byte, _ []int) ([]byte, error) {
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("value is not valid json")
+	}
+	return b, nil
+}
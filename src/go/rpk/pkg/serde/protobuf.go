@@ -0,0 +1,176 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package serde
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// The schema itself has no file name in the registry; we give it one that is
+// unlikely to conflict with any referenced import.
+const protoRootFile = "rpk_registry_schema.proto"
+
+// Protobuf values are converted to and from the canonical protobuf JSON
+// mapping.
+type protoCodec struct {
+	fd protoreflect.FileDescriptor
+}
+
+func newProtoCodec(
+	ctx context.Context, f SchemaFetcher, s schemaregistry.Schema,
+) (*protoCodec, error) {
+	files, err := resolveReferences(ctx, f, s.References)
+	if err != nil {
+		return nil, err
+	}
+	files[protoRootFile] = s.Schema
+
+	c := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(files),
+		}),
+	}
+	compiled, err := c.Compile(ctx, protoRootFile)
+	if err != nil {
+		return nil, err
+	}
+	return &protoCodec{fd: compiled[0]}, nil
+}
+
+// messageIndex returns the index path of the given message in the schema,
+// which can be either fully qualified or relative to the schema's package. If
+// name is empty, this returns the index of the first message.
+func (p *protoCodec) messageIndex(name string) ([]int, error) {
+	if p.fd.Messages().Len() == 0 {
+		return nil, errors.New("protobuf schema has no messages")
+	}
+	if name == "" {
+		return []int{0}, nil
+	}
+	full := strings.TrimPrefix(name, ".")
+	if pkg := p.fd.Package(); pkg != "" && !strings.HasPrefix(full, string(pkg)+".") {
+		full = string(pkg) + "." + full
+	}
+
+	var search func(protoreflect.MessageDescriptors, []int) []int
+	search = func(mds protoreflect.MessageDescriptors, path []int) []int {
+		for i := 0; i < mds.Len(); i++ {
+			md := mds.Get(i)
+			at := append(append([]int(nil), path...), i)
+			if string(md.FullName()) == full {
+				return at
+			}
+			if found := search(md.Messages(), at); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	if index := search(p.fd.Messages(), nil); index != nil {
+		return index, nil
+	}
+	return nil, fmt.Errorf("message %q not found in protobuf schema", name)
+}
+
+func (p *protoCodec) descriptor(index []int) (protoreflect.MessageDescriptor, error) {
+	mds := p.fd.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, i := range index {
+		if i < 0 || i >= mds.Len() {
+			return nil, fmt.Errorf("message index %v is out of range for the protobuf schema", index)
+		}
+		md = mds.Get(i)
+		mds = md.Messages()
+	}
+	if md == nil {
+		return nil, errors.New("invalid empty message index")
+	}
+	return md, nil
+}
+
+func (p *protoCodec) encode(text []byte, index []int) ([]byte, error) {
+	md, err := p.descriptor(index)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal(text, msg); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+func (p *protoCodec) decode(b []byte, index []int) ([]byte, error) {
+	md, err := p.descriptor(index)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(msg)
+}
+
+// The message index is encoded as a zigzag varint count followed by zigzag
+// varint indexes. As an optimization, the common case of the first message in
+// the schema is encoded as a single zero count.
+func appendMessageIndex(b []byte, index []int) []byte {
+	if len(index) == 0 {
+		return b
+	}
+	if len(index) == 1 && index[0] == 0 {
+		return append(b, 0)
+	}
+	b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(len(index))))
+	for _, i := range index {
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(i)))
+	}
+	return b
+}
+
+func readMessageIndex(b []byte) ([]int, []byte, error) {
+	read := func() (int, error) {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return 0, errors.New("unable to read protobuf message index")
+		}
+		b = b[n:]
+		return int(protowire.DecodeZigZag(v)), nil
+	}
+	count, err := read()
+	if err != nil {
+		return nil, nil, err
+	}
+	if count == 0 {
+		return []int{0}, b, nil
+	}
+	if count < 0 || count > len(b) {
+		return nil, nil, fmt.Errorf("invalid protobuf message index count %d", count)
+	}
+	index := make([]int, count)
+	for i := range index {
+		if index[i], err = read(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return index, b, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package serde encodes and decodes record values using schemas from the
+// schema registry.
+//
+// Values are framed in the standard schema registry wire format: a zero magic
+// byte, a four byte big endian schema ID, and then the encoded value. Protobuf
+// values additionally have the index of the message within the schema after
+// the schema ID.
+package serde
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+)
+
+const (
+	magicByte  = 0
+	headerSize = 5 // magic byte + 4 byte schema ID
+)
+
+// A codec converts between the textual (JSON) and binary representation of
+// values for a single schema. Only protobuf codecs use the message index.
+type codec interface {
+	encode(text []byte, index []int) ([]byte, error)
+	decode(b []byte, index []int) ([]byte, error)
+}
+
+// SchemaFetcher looks up schemas from the schema registry.
+// *schemaregistry.Client implements this interface.
+type SchemaFetcher interface {
+	SchemaByID(ctx context.Context, id int) (schemaregistry.Schema, error)
+	SchemaByVersion(ctx context.Context, subject, version string) (schemaregistry.SubjectSchema, error)
+}
+
+// AppendHeader appends the wire format header for the given schema ID to b.
+func AppendHeader(b []byte, id int) []byte {
+	b = append(b, magicByte)
+	return binary.BigEndian.AppendUint32(b, uint32(id))
+}
+
+// ParseHeader returns the schema ID of a framed value and the remaining
+// bytes after the header.
+func ParseHeader(b []byte) (int, []byte, error) {
+	if len(b) < headerSize {
+		return 0, nil, fmt.Errorf("value of %d bytes is too short to contain a schema header", len(b))
+	}
+	if b[0] != magicByte {
+		return 0, nil, fmt.Errorf("value does not begin with the schema magic byte %d, saw %d", magicByte, b[0])
+	}
+	return int(binary.BigEndian.Uint32(b[1:headerSize])), b[headerSize:], nil
+}
+
+// Encoder encodes textual values into the wire format for a single schema.
+type Encoder struct {
+	id    int
+	index []int
+	c     codec
+}
+
+// NewEncoder returns an encoder for the schema with the given ID. The fetcher
+// is used to resolve any references in the schema. For protobuf schemas,
+// message is the name of the message to encode; if empty, the first message
+// in the schema is used. The message name is ignored for other schema types.
+func NewEncoder(
+	ctx context.Context,
+	f SchemaFetcher,
+	id int,
+	s schemaregistry.Schema,
+	message string,
+) (*Encoder, error) {
+	e := &Encoder{id: id}
+	if s.SchemaType() == schemaregistry.TypeProtobuf {
+		pc, err := newProtoCodec(ctx, f, s)
+		if err != nil {
+			return nil, err
+		}
+		if e.index, err = pc.messageIndex(message); err != nil {
+			return nil, err
+		}
+		e.c = pc
+		return e, nil
+	}
+	var err error
+	e.c, err = newCodec(ctx, f, s)
+	return e, err
+}
+
+// ID returns the schema ID this encoder encodes with.
+func (e *Encoder) ID() int { return e.id }
+
+// Encode encodes the textual value into the wire format.
+func (e *Encoder) Encode(text []byte) ([]byte, error) {
+	b, err := e.c.encode(text, e.index)
+	if err != nil {
+		return nil, err
+	}
+	out := AppendHeader(make([]byte, 0, headerSize+len(b)), e.id)
+	out = appendMessageIndex(out, e.index)
+	return append(out, b...), nil
+}
+
+// Decoder decodes values in the wire format into their textual
+// representation, looking up and caching schemas by ID as needed.
+//
+// A Decoder is not safe for concurrent use.
+type Decoder struct {
+	f      SchemaFetcher
+	codecs map[int]decoderCodec
+}
+
+type decoderCodec struct {
+	c       codec
+	isProto bool
+	err     error // non-nil if we failed to build a codec for this ID
+}
+
+// NewDecoder returns a decoder that looks up schemas with f.
+func NewDecoder(f SchemaFetcher) *Decoder {
+	return &Decoder{f: f, codecs: make(map[int]decoderCodec)}
+}
+
+// Decode decodes a value in the wire format into its textual representation.
+func (d *Decoder) Decode(ctx context.Context, b []byte) ([]byte, error) {
+	id, b, err := ParseHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	dc, ok := d.codecs[id]
+	if !ok {
+		dc = d.loadCodec(ctx, id)
+		d.codecs[id] = dc
+	}
+	if dc.err != nil {
+		return nil, dc.err
+	}
+	var index []int
+	if dc.isProto {
+		if index, b, err = readMessageIndex(b); err != nil {
+			return nil, err
+		}
+	}
+	return dc.c.decode(b, index)
+}
+
+func (d *Decoder) loadCodec(ctx context.Context, id int) decoderCodec {
+	s, err := d.f.SchemaByID(ctx, id)
+	if err != nil {
+		return decoderCodec{err: fmt.Errorf("unable to get schema %d: %w", id, err)}
+	}
+	c, err := newCodec(ctx, d.f, s)
+	if err != nil {
+		return decoderCodec{err: fmt.Errorf("unable to parse schema %d: %w", id, err)}
+	}
+	return decoderCodec{c: c, isProto: s.SchemaType() == schemaregistry.TypeProtobuf}
+}
+
+func newCodec(
+	ctx context.Context, f SchemaFetcher, s schemaregistry.Schema,
+) (codec, error) {
+	switch t := s.SchemaType(); t {
+	case schemaregistry.TypeAvro:
+		return newAvroCodec(s)
+	case schemaregistry.TypeProtobuf:
+		return newProtoCodec(ctx, f, s)
+	case schemaregistry.TypeJSON:
+		return newJSONCodec(ctx, f, s)
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", t)
+	}
+}
+
+// resolveReferences returns the text of every schema referenced, directly or
+// transitively, by the given references, keyed by reference name.
+func resolveReferences(
+	ctx context.Context, f SchemaFetcher, refs []schemaregistry.SchemaReference,
+) (map[string]string, error) {
+	resolved := make(map[string]string)
+	var resolve func([]schemaregistry.SchemaReference) error
+	resolve = func(refs []schemaregistry.SchemaReference) error {
+		for _, ref := range refs {
+			if _, ok := resolved[ref.Name]; ok {
+				continue
+			}
+			ss, err := f.SchemaByVersion(ctx, ref.Subject, strconv.Itoa(ref.Version))
+			if err != nil {
+				return fmt.Errorf("unable to resolve reference %q (subject %q version %d): %w", ref.Name, ref.Subject, ref.Version, err)
+			}
+			resolved[ref.Name] = ss.Schema.Schema
+			if err := resolve(ss.References); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return resolved, resolve(refs)
+}
+
+var errTrailingBytes = errors.New("value has unexpected trailing bytes")
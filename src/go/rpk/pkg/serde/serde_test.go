@@ -0,0 +1,218 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package serde
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/schemaregistry"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistry struct {
+	byID      map[int]schemaregistry.Schema
+	bySubject map[string]schemaregistry.SubjectSchema // subject-version
+}
+
+func (f *fakeRegistry) SchemaByID(_ context.Context, id int) (schemaregistry.Schema, error) {
+	s, ok := f.byID[id]
+	if !ok {
+		return s, fmt.Errorf("schema %d not found", id)
+	}
+	return s, nil
+}
+
+func (f *fakeRegistry) SchemaByVersion(_ context.Context, subject, version string) (schemaregistry.SubjectSchema, error) {
+	s, ok := f.bySubject[subject+"-"+version]
+	if !ok {
+		return s, fmt.Errorf("subject %s version %s not found", subject, version)
+	}
+	return s, nil
+}
+
+const (
+	avroSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": ["null", "int"], "default": null}
+  ]
+}`
+
+	protoSchema = `syntax = "proto3";
+package test;
+
+import "common.proto";
+
+message Outer {
+  string id = 1;
+  message Inner {
+    int32 count = 1;
+    common.Tag tag = 2;
+  }
+}
+`
+
+	protoCommon = `syntax = "proto3";
+package common;
+
+message Tag {
+  string name = 1;
+}
+`
+
+	jsonSchema = `{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "address": {"$ref": "address.json"}
+  },
+  "required": ["name"]
+}`
+
+	jsonAddress = `{
+  "type": "object",
+  "properties": {"city": {"type": "string"}},
+  "required": ["city"]
+}`
+)
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		byID: map[int]schemaregistry.Schema{
+			1: {Schema: avroSchema},
+			2: {
+				Schema:     protoSchema,
+				Type:       schemaregistry.TypeProtobuf,
+				References: []schemaregistry.SchemaReference{{Name: "common.proto", Subject: "common", Version: 1}},
+			},
+			3: {
+				Schema:     jsonSchema,
+				Type:       schemaregistry.TypeJSON,
+				References: []schemaregistry.SchemaReference{{Name: "address.json", Subject: "address", Version: 1}},
+			},
+		},
+		bySubject: map[string]schemaregistry.SubjectSchema{
+			"common-1":  {Subject: "common", Version: 1, ID: 10, Schema: schemaregistry.Schema{Schema: protoCommon, Type: schemaregistry.TypeProtobuf}},
+			"address-1": {Subject: "address", Version: 1, ID: 11, Schema: schemaregistry.Schema{Schema: jsonAddress, Type: schemaregistry.TypeJSON}},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	reg := newFakeRegistry()
+	for _, test := range []struct {
+		name      string
+		id        int
+		message   string
+		in        string
+		exp       string
+		expHeader []byte
+		expErr    bool
+	}{
+		{
+			name:      "avro",
+			id:        1,
+			in:        `{"name":"foo","age":{"int":3}}`,
+			exp:       `{"name":"foo","age":{"int":3}}`,
+			expHeader: []byte{0, 0, 0, 0, 1},
+		},
+		{
+			name:   "avro invalid value",
+			id:     1,
+			in:     `{"age":{"int":3}}`,
+			expErr: true,
+		},
+		{
+			name:      "protobuf first message",
+			id:        2,
+			in:        `{"id":"abc"}`,
+			exp:       `{"id":"abc"}`,
+			expHeader: []byte{0, 0, 0, 0, 2, 0},
+		},
+		{
+			name:      "protobuf nested message with reference",
+			id:        2,
+			message:   "Outer.Inner",
+			in:        `{"count":3,"tag":{"name":"t"}}`,
+			exp:       `{"count":3,"tag":{"name":"t"}}`,
+			expHeader: []byte{0, 0, 0, 0, 2, 4, 0, 0}, // zigzag: count 2, indexes 0 0
+		},
+		{
+			name:    "protobuf unknown message",
+			id:      2,
+			message: "test.Missing",
+			expErr:  true,
+		},
+		{
+			name:      "json",
+			id:        3,
+			in:        `{"name": "foo", "address": {"city": "nyc"}}`,
+			exp:       `{"name":"foo","address":{"city":"nyc"}}`,
+			expHeader: []byte{0, 0, 0, 0, 3},
+		},
+		{
+			name:   "json fails referenced schema validation",
+			id:     3,
+			in:     `{"name": "foo", "address": {}}`,
+			expErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			enc, err := NewEncoder(ctx, reg, test.id, reg.byID[test.id], test.message)
+			if err == nil {
+				var encoded []byte
+				encoded, err = enc.Encode([]byte(test.in))
+				if err == nil {
+					require.Equal(t, test.expHeader, encoded[:len(test.expHeader)])
+
+					var decoded []byte
+					decoded, err = NewDecoder(reg).Decode(ctx, encoded)
+					require.NoError(t, err)
+					require.JSONEq(t, test.exp, string(decoded))
+				}
+			}
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	dec := NewDecoder(newFakeRegistry())
+	ctx := context.Background()
+	for _, b := range [][]byte{
+		{0, 0, 0},          // too short
+		{1, 0, 0, 0, 1, 2}, // bad magic
+		{0, 0, 0, 0, 9, 2}, // unknown schema
+		{0, 0, 0, 0, 2, 2, 0x7f},
+	} {
+		_, err := dec.Decode(ctx, b)
+		require.Error(t, err, "input %v", b)
+	}
+}
+
+func TestMessageIndex(t *testing.T) {
+	for _, index := range [][]int{{0}, {1}, {0, 2}, {3, 1, 4}} {
+		b := appendMessageIndex(nil, index)
+		b = append(b, 0xff)
+		got, rem, err := readMessageIndex(b)
+		require.NoError(t, err)
+		require.Equal(t, index, got)
+		require.Equal(t, []byte{0xff}, rem)
+	}
+}
@@ -11,6 +11,7 @@ package tuners
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"time"
@@ -24,12 +25,44 @@ import (
 func Check(
 	fs afero.Fs, conf *config.Config, timeout time.Duration,
 ) ([]CheckResult, error) {
-	var results []CheckResult
 	ioConfigFile := redpanda.GetIOConfigPath(filepath.Dir(conf.FileLocation()))
 	checkersMap, err := RedpandaCheckers(fs, ioConfigFile, conf, timeout)
 	if err != nil {
-		return results, err
+		return nil, err
+	}
+	return runCheckers(checkersMap)
+}
+
+// CheckPreProvision runs the checks against the config of a node that is not
+// provisioned yet. If the data directory does not exist yet, its checks run
+// against its nearest existing parent directory, which is the directory it
+// will be created in. The checks of files that provisioning creates, such as
+// the I/O config file and the ballast file, are not run.
+func CheckPreProvision(
+	fs afero.Fs, conf *config.Config, timeout time.Duration,
+) ([]CheckResult, error) {
+	provisioned := *conf
+	dir := filepath.Clean(conf.Redpanda.Directory)
+	for {
+		if exists, _ := afero.DirExists(fs, dir); exists || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	provisioned.Redpanda.Directory = dir
+	ioConfigFile := redpanda.GetIOConfigPath(filepath.Dir(conf.FileLocation()))
+	checkersMap, err := RedpandaCheckers(fs, ioConfigFile, &provisioned, timeout)
+	if err != nil {
+		return nil, err
 	}
+	checkersMap[ConfigFileChecker] = []Checker{NewConfigChecker(conf)}
+	delete(checkersMap, IoConfigFileChecker)
+	delete(checkersMap, BallastFileChecker)
+	return runCheckers(checkersMap)
+}
+
+func runCheckers(checkersMap map[CheckerID][]Checker) ([]CheckResult, error) {
+	var results []CheckResult
 
 	// We use a sorted list of the checker's ID present in the checkersMap to
 	// run in a consistent order.
@@ -48,7 +81,10 @@ func Check(
 				if c.GetSeverity() == Fatal {
 					return results, fmt.Errorf("fatal error during checker %q execution: %v", c.GetDesc(), result.Err)
 				}
-				fmt.Printf("System check %q failed with non-fatal error %q\n", c.GetDesc(), result.Err)
+				fmt.Fprintf(os.Stderr, "System check %q failed with non-fatal error %q\n", c.GetDesc(), result.Err)
+			}
+			if !result.IsOk {
+				result.Remediation = RemediationFor(result.CheckerID)
 			}
 			log.Debugf("Finished checker %q; result %+v", c.GetDesc(), result)
 			results = append(results, *result)
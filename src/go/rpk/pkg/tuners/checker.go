@@ -34,6 +34,8 @@ type CheckResult struct {
 	Desc      string
 	Severity  Severity
 	Required  string
+	// Remediation is how to fix the check if it failed.
+	Remediation Remediation
 }
 
 type Checker interface {
@@ -10,6 +10,8 @@
 package tuners
 
 import (
+	"path"
+
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
@@ -198,6 +200,9 @@ func GetExpectedIRQsDistribution(
 	if err != nil {
 		return nil, err
 	}
+	if effectiveMode == irq.Numa {
+		return getNumaIRQsDistribution(devices, blockDevices, irqCPUMask, cpuMasks)
+	}
 	devicesIRQsDistribution := make(map[int]string)
 	if len(nonNvmeDisksInfo.Devices) > 0 {
 		IRQsDist, err := cpuMasks.GetIRQsDistributionMasks(
@@ -224,6 +229,47 @@ func GetExpectedIRQsDistribution(
 	return devicesIRQsDistribution, nil
 }
 
+// getNumaIRQsDistribution distributes the IRQs of each device among the CPUs
+// in the mask that are on the NUMA node the device is attached to.
+func getNumaIRQsDistribution(
+	devices []string,
+	blockDevices disk.BlockDevices,
+	cpuMask string,
+	cpuMasks irq.CPUMasks,
+) (map[int]string, error) {
+	devicesIRQsDistribution := make(map[int]string)
+	for _, device := range devices {
+		syspath, err := blockDevices.GetDeviceSystemPath(path.Join("/dev", device))
+		if err != nil {
+			return nil, err
+		}
+		localCPUMask, err := cpuMasks.NumaLocalCPUMask(syspath, cpuMask)
+		if err != nil {
+			return nil, err
+		}
+		diskInfoByType, err := blockDevices.GetDiskInfoByType([]string{device})
+		if err != nil {
+			return nil, err
+		}
+		var IRQs []int
+		for _, diskInfo := range diskInfoByType {
+			IRQs = append(IRQs, diskInfo.Irqs...)
+		}
+		if len(IRQs) == 0 {
+			continue
+		}
+		IRQsDist, err := cpuMasks.GetIRQsDistributionMasks(IRQs, localCPUMask)
+		if err != nil {
+			return nil, err
+		}
+		for IRQ, mask := range IRQsDist {
+			devicesIRQsDistribution[IRQ] = mask
+		}
+	}
+	log.Debugf("Calculated NUMA local IRQs distribution %v", devicesIRQsDistribution)
+	return devicesIRQsDistribution, nil
+}
+
 func GetDefaultMode(
 	cpuMask string,
 	diskInfoByType map[disk.DiskType]disk.DevicesIRQs,
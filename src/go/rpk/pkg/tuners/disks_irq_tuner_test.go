@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
@@ -22,6 +23,8 @@ type cpuMasksMock struct {
 	baseCPUMask              func(string) (string, error)
 	cpuMaskForIRQs           func(irq.Mode, string) (string, error)
 	getIRQsDistributionMasks func([]int, string) (map[int]string, error)
+	numaLocalCPUMask         func(string, string) (string, error)
+	readIRQMask              func(int) (string, error)
 }
 
 type blockDevicesMock struct {
@@ -48,6 +51,16 @@ func (m *cpuMasksMock) GetIRQsDistributionMasks(
 	return m.getIRQsDistributionMasks(IRQs, cpuMask)
 }
 
+func (m *cpuMasksMock) NumaLocalCPUMask(
+	syspath string, cpuMask string,
+) (string, error) {
+	return m.numaLocalCPUMask(syspath, cpuMask)
+}
+
+func (m *cpuMasksMock) ReadIRQMask(IRQ int) (string, error) {
+	return m.readIRQMask(IRQ)
+}
+
 func (m *blockDevicesMock) GetDirectoriesDevices(
 	directories []string,
 ) (map[string][]string, error) {
@@ -139,6 +152,58 @@ func TestGetExpectedIRQsDistribution(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "shall distribute each device IRQs on its NUMA node in numa mode",
+			args: args{
+				devices: []string{"nvme0n1", "nvme1n1"},
+				mode:    irq.Numa,
+				cpuMask: "all",
+				blockDevices: &blockDevicesMock{
+					getBlockDeviceSystemPath: func(path string) (string, error) {
+						return map[string]string{
+							"/dev/nvme0n1": "/sys/devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1",
+							"/dev/nvme1n1": "/sys/devices/pci0000:80/0000:80:04.0/nvme/nvme1/nvme1n1",
+						}[path], nil
+					},
+					getDiskInfoByType: func(devices []string) (map[disk.DiskType]disk.DevicesIRQs, error) {
+						IRQs := map[string][]int{
+							"nvme0n1": {30, 31},
+							"nvme1n1": {40, 41},
+						}[devices[0]]
+						return map[disk.DiskType]disk.DevicesIRQs{
+							disk.Nvme: {Devices: devices, Irqs: IRQs},
+						}, nil
+					},
+				},
+				cpuMasks: &cpuMasksMock{
+					baseCPUMask: func(string) (string, error) {
+						return "0x000000ff", nil
+					},
+					cpuMaskForIRQs: func(_ irq.Mode, cpuMask string) (string, error) {
+						return cpuMask, nil
+					},
+					numaLocalCPUMask: func(syspath string, _ string) (string, error) {
+						if strings.Contains(syspath, "pci0000:00") {
+							return "0x0000000f", nil
+						}
+						return "0x000000f0", nil
+					},
+					getIRQsDistributionMasks: func(IRQs []int, cpuMask string) (map[int]string, error) {
+						if cpuMask == "0x0000000f" {
+							return map[int]string{IRQs[0]: "0x00000001", IRQs[1]: "0x00000002"}, nil
+						}
+						return map[int]string{IRQs[0]: "0x00000010", IRQs[1]: "0x00000020"}, nil
+					},
+				},
+			},
+			want: map[int]string{
+				30: "0x00000001",
+				31: "0x00000002",
+				40: "0x00000010",
+				41: "0x00000020",
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
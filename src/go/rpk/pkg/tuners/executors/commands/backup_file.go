@@ -45,3 +45,8 @@ func (c *backupFileCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintf(w, "cp %s %s.vectorized.${md_5}.bk\n", c.path, c.path)
 	return w.Flush()
 }
+
+// Prior returns no values: backing up a file only creates the backup.
+func (*backupFileCommand) Prior() ([]PriorValue, error) {
+	return nil, nil
+}
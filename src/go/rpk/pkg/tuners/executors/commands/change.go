@@ -0,0 +1,38 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import "strings"
+
+// Change is a change of kernel state that a command makes, which is lost on
+// reboot.
+type Change struct {
+	// Kind is PriorFile for writes to /proc and /sys files, PriorSysctl,
+	// or PriorEthtool.
+	Kind string
+	// Key is the file path, the sysctl key, or the interface and feature
+	// separated by a space.
+	Key   string
+	Value string
+}
+
+// Changes returns the changes of kernel state that cmd makes. Commands that
+// only change persistent state, such as writing regular files, have no
+// changes.
+func Changes(cmd Command) []Change {
+	if c, ok := cmd.(interface{ changes() []Change }); ok {
+		return c.changes()
+	}
+	return nil
+}
+
+func isKernelPath(path string) bool {
+	return strings.HasPrefix(path, "/proc/") || strings.HasPrefix(path, "/sys/")
+}
@@ -14,4 +14,7 @@ import "bufio"
 type Command interface {
 	Execute() error
 	RenderScript(*bufio.Writer) error
+	// Prior returns the current values of the state that Execute
+	// changes, such that the change can be reverted.
+	Prior() ([]PriorValue, error)
 }
@@ -53,3 +53,35 @@ func (c *ethtoolChangeCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintln(w)
 	return w.Flush()
 }
+
+func (c *ethtoolChangeCommand) Prior() ([]PriorValue, error) {
+	current, err := c.ethtool.Features(c.intf)
+	if err != nil {
+		return nil, err
+	}
+	features := make(map[string]bool, len(c.config))
+	for feature := range c.config {
+		features[feature] = current[feature]
+	}
+	return []PriorValue{{Kind: PriorEthtool, Key: c.intf, Features: features}}, nil
+}
+
+func (c *ethtoolChangeCommand) changes() []Change {
+	var changes []Change
+	for feature, state := range c.config {
+		value := "on"
+		if !state {
+			value = "off"
+		}
+		changes = append(changes, Change{Kind: PriorEthtool, Key: c.intf + " " + feature, Value: value})
+	}
+	return changes
+}
+
+func newEthtoolRevertCmd(intf string, features map[string]bool) (Command, error) {
+	wrapper, err := ethtool.NewEthtoolWrapper()
+	if err != nil {
+		return nil, err
+	}
+	return NewEthtoolChangeCmd(wrapper, intf, features), nil
+}
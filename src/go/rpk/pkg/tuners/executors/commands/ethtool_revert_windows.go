@@ -0,0 +1,16 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import "errors"
+
+func newEthtoolRevertCmd(string, map[string]bool) (Command, error) {
+	return nil, errors.New("ethtool is not supported on windows")
+}
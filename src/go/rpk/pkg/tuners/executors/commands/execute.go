@@ -12,6 +12,7 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
@@ -48,3 +49,9 @@ func (c *executeCommand) RenderScript(w *bufio.Writer) error {
 	}
 	return w.Flush()
 }
+
+// Prior returns a PriorNone value, because the effects of running a command
+// are unknown.
+func (c *executeCommand) Prior() ([]PriorValue, error) {
+	return []PriorValue{{Kind: PriorNone, Key: strings.Join(append([]string{c.cmd}, c.args...), " ")}}, nil
+}
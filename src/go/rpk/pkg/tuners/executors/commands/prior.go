@@ -0,0 +1,170 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// The kinds of state that commands change.
+const (
+	// PriorFile is the content of a file; if the file did not exist,
+	// reverting removes it.
+	PriorFile = "file"
+	// PriorFileSize is the size of a file that is resized.
+	PriorFileSize = "file_size"
+	// PriorSysctl is the value of a sysctl key.
+	PriorSysctl = "sysctl"
+	// PriorEthtool is the state of features of a network interface.
+	PriorEthtool = "ethtool"
+	// PriorSystemdUnit is whether a systemd unit is enabled; if it was not,
+	// reverting disables it.
+	PriorSystemdUnit = "systemd_unit"
+	// PriorNone is a change that cannot be reverted, such as running a
+	// command; the key describes the change.
+	PriorNone = "none"
+)
+
+// ErrNotRevertible is returned when reverting a PriorNone value.
+var ErrNotRevertible = errors.New("change cannot be reverted")
+
+// PriorValue is the state that a command changes, as it was before the
+// command was executed.
+type PriorValue struct {
+	Kind     string          `json:"kind"`
+	Key      string          `json:"key"`
+	Value    string          `json:"value,omitempty"`
+	Exists   bool            `json:"exists,omitempty"`
+	Mode     os.FileMode     `json:"mode,omitempty"`
+	Size     int64           `json:"size,omitempty"`
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+// SameState returns whether both values are the prior value of the same
+// state, such as the same file or sysctl key.
+func (v PriorValue) SameState(other PriorValue) bool {
+	return v.Kind == other.Kind && v.Key == other.Key
+}
+
+// selectedRe matches the selected choice of sysfs files that list every
+// choice, such as "none [mq-deadline] kyber".
+var selectedRe = regexp.MustCompile(`\[([^\]]+)\]`)
+
+// filePrior returns the prior value of a file. Kernel files (in /proc and
+// /sys) are trimmed and, if they list choices, reduced to the selected
+// choice, such that the value can be written back.
+func filePrior(fs afero.Fs, path string) (PriorValue, error) {
+	v := PriorValue{Kind: PriorFile, Key: path}
+	info, err := fs.Stat(path)
+	if os.IsNotExist(err) {
+		return v, nil
+	}
+	if err != nil {
+		return v, err
+	}
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return v, err
+	}
+	v.Value = string(content)
+	if isKernelPath(path) {
+		v.Value = strings.TrimSpace(v.Value)
+		if m := selectedRe.FindStringSubmatch(v.Value); m != nil {
+			v.Value = m[1]
+		}
+	}
+	v.Exists = true
+	v.Mode = info.Mode()
+	return v, nil
+}
+
+// NewRevertCmd returns a command that restores a prior value.
+func NewRevertCmd(fs afero.Fs, v PriorValue) (Command, error) {
+	switch v.Kind {
+	case PriorFile:
+		if !v.Exists {
+			return &removeFileCommand{fs: fs, path: v.Key}, nil
+		}
+		return NewWriteFileModeCmd(fs, v.Key, v.Value, v.Mode), nil
+	case PriorFileSize:
+		return &truncateFileCommand{fs: fs, path: v.Key, size: v.Size}, nil
+	case PriorSysctl:
+		return NewSysctlSetCmd(v.Key, v.Value), nil
+	case PriorEthtool:
+		return newEthtoolRevertCmd(v.Key, v.Features)
+	case PriorSystemdUnit:
+		return &disableSystemdUnitCommand{fs: fs, name: v.Key, keep: v.Exists}, nil
+	case PriorNone:
+		return nil, ErrNotRevertible
+	default:
+		return nil, fmt.Errorf("unknown kind %q", v.Kind)
+	}
+}
+
+type removeFileCommand struct {
+	fs   afero.Fs
+	path string
+}
+
+func (c *removeFileCommand) Execute() error {
+	log.Debugf("Removing '%s'", c.path)
+	err := c.fs.Remove(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *removeFileCommand) RenderScript(w *bufio.Writer) error {
+	fmt.Fprintf(w, "rm -f %s\n", c.path)
+	return w.Flush()
+}
+
+func (c *removeFileCommand) Prior() ([]PriorValue, error) {
+	v, err := filePrior(c.fs, c.path)
+	return []PriorValue{v}, err
+}
+
+type truncateFileCommand struct {
+	fs   afero.Fs
+	path string
+	size int64
+}
+
+func (c *truncateFileCommand) Execute() error {
+	log.Debugf("Resizing '%s' to %d B", c.path, c.size)
+	f, err := c.fs.OpenFile(c.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Truncate(c.size)
+}
+
+func (c *truncateFileCommand) RenderScript(w *bufio.Writer) error {
+	fmt.Fprintf(w, "truncate -s %d %s\n", c.size, c.path)
+	return w.Flush()
+}
+
+func (c *truncateFileCommand) Prior() ([]PriorValue, error) {
+	info, err := c.fs.Stat(c.path)
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{{Kind: PriorFileSize, Key: c.path, Size: info.Size()}}, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePrior(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		path    string
+		content string
+		exists  bool
+		exp     commands.PriorValue
+	}{
+		{
+			name: "missing file",
+			path: "/etc/file",
+			exp:  commands.PriorValue{Kind: commands.PriorFile, Key: "/etc/file"},
+		},
+		{
+			name:    "regular file is kept as is",
+			path:    "/etc/file",
+			content: "a\nb\n",
+			exists:  true,
+			exp:     commands.PriorValue{Kind: commands.PriorFile, Key: "/etc/file", Value: "a\nb\n", Exists: true, Mode: 0o644},
+		},
+		{
+			name:    "kernel file is trimmed",
+			path:    "/proc/irq/1/smp_affinity",
+			content: "ff\n",
+			exists:  true,
+			exp:     commands.PriorValue{Kind: commands.PriorFile, Key: "/proc/irq/1/smp_affinity", Value: "ff", Exists: true, Mode: 0o644},
+		},
+		{
+			name:    "kernel file with choices is reduced to the selected choice",
+			path:    "/sys/block/sda/queue/scheduler",
+			content: "none [mq-deadline] kyber\n",
+			exists:  true,
+			exp:     commands.PriorValue{Kind: commands.PriorFile, Key: "/sys/block/sda/queue/scheduler", Value: "mq-deadline", Exists: true, Mode: 0o644},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if test.exists {
+				require.NoError(t, afero.WriteFile(fs, test.path, []byte(test.content), 0o644))
+			}
+			priors, err := commands.NewWriteFileCmd(fs, test.path, "new").Prior()
+			require.NoError(t, err)
+			require.Equal(t, []commands.PriorValue{test.exp}, priors)
+		})
+	}
+}
+
+func TestNewRevertCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/created", []byte("new"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/changed", []byte("new"), 0o644))
+
+	remove, err := commands.NewRevertCmd(fs, commands.PriorValue{Kind: commands.PriorFile, Key: "/etc/created"})
+	require.NoError(t, err)
+	require.NoError(t, remove.Execute())
+	exists, err := afero.Exists(fs, "/etc/created")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	write, err := commands.NewRevertCmd(fs, commands.PriorValue{Kind: commands.PriorFile, Key: "/etc/changed", Value: "old", Exists: true, Mode: 0o600})
+	require.NoError(t, err)
+	require.NoError(t, write.Execute())
+	content, err := afero.ReadFile(fs, "/etc/changed")
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	require.NoError(t, remove.RenderScript(w))
+	require.Equal(t, "rm -f /etc/created\n", buf.String())
+
+	_, err = commands.NewRevertCmd(fs, commands.PriorValue{Kind: commands.PriorNone, Key: "systemctl start foo"})
+	require.True(t, errors.Is(err, commands.ErrNotRevertible))
+
+	// Enabling a unit reverts to disabling it, unless it was enabled before.
+	wants := "/etc/systemd/system/multi-user.target.wants/foo.service"
+	enable := commands.NewEnableSystemdUnitCmd(fs, nil, time.Second, "foo.service")
+	priors, err := enable.Prior()
+	require.NoError(t, err)
+	require.Equal(t, []commands.PriorValue{{Kind: commands.PriorSystemdUnit, Key: "foo.service"}}, priors)
+	for _, enabled := range []bool{true, false} {
+		require.NoError(t, afero.WriteFile(fs, wants, []byte("unit"), 0o644))
+		disable, err := commands.NewRevertCmd(fs, commands.PriorValue{Kind: commands.PriorSystemdUnit, Key: "foo.service", Exists: enabled})
+		require.NoError(t, err)
+		require.NoError(t, disable.Execute())
+		exists, err := afero.Exists(fs, wants)
+		require.NoError(t, err)
+		require.Equal(t, enabled, exists)
+	}
+
+	_, err = commands.NewRevertCmd(fs, commands.PriorValue{Kind: "unknown"})
+	require.Error(t, err)
+}
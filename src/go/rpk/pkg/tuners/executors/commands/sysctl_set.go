@@ -39,3 +39,15 @@ func (c *sysctlSetCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintf(w, "sysctl -w %s=%s\n", c.key, c.value)
 	return w.Flush()
 }
+
+func (c *sysctlSetCommand) Prior() ([]PriorValue, error) {
+	value, err := sysctl.Get(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{{Kind: PriorSysctl, Key: c.key, Value: value}}, nil
+}
+
+func (c *sysctlSetCommand) changes() []Change {
+	return []Change{{Kind: PriorSysctl, Key: c.key, Value: c.value}}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system/systemd"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+type enableSystemdUnitCommand struct {
+	fs      afero.Fs
+	proc    vos.Proc
+	timeout time.Duration
+	name    string
+}
+
+// NewEnableSystemdUnitCmd enables the systemd unit with the provided name,
+// which must be wanted by multi-user.target. Reverting it disables the unit if
+// it was not enabled before.
+func NewEnableSystemdUnitCmd(
+	fs afero.Fs, proc vos.Proc, timeout time.Duration, name string,
+) Command {
+	return &enableSystemdUnitCommand{
+		fs:      fs,
+		proc:    proc,
+		timeout: timeout,
+		name:    name,
+	}
+}
+
+func (cmd *enableSystemdUnitCommand) Execute() error {
+	_, err := cmd.proc.RunWithSystemLdPath(cmd.timeout, "systemctl", "enable", cmd.name)
+	return err
+}
+
+func (cmd *enableSystemdUnitCommand) RenderScript(w *bufio.Writer) error {
+	fmt.Fprintf(w, "systemctl enable %s\n", cmd.name)
+	return w.Flush()
+}
+
+func (cmd *enableSystemdUnitCommand) Prior() ([]PriorValue, error) {
+	return unitPrior(cmd.fs, cmd.name)
+}
+
+// disableSystemdUnitCommand disables a unit wanted by multi-user.target by
+// removing its wants symlink, which is what 'systemctl disable' does for such
+// units. If keep is set, the unit was enabled before rpk enabled it, and it
+// is left enabled.
+type disableSystemdUnitCommand struct {
+	fs   afero.Fs
+	name string
+	keep bool
+}
+
+func (cmd *disableSystemdUnitCommand) Execute() error {
+	if cmd.keep {
+		return nil
+	}
+	log.Debugf("Disabling systemd unit '%s'", cmd.name)
+	err := cmd.fs.Remove(unitWantsPath(cmd.name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (cmd *disableSystemdUnitCommand) RenderScript(w *bufio.Writer) error {
+	if cmd.keep {
+		fmt.Fprintf(w, "# %s was already enabled\n", cmd.name)
+		return w.Flush()
+	}
+	fmt.Fprintf(w, "systemctl disable %s\n", cmd.name)
+	return w.Flush()
+}
+
+func (cmd *disableSystemdUnitCommand) Prior() ([]PriorValue, error) {
+	return unitPrior(cmd.fs, cmd.name)
+}
+
+func unitPrior(fs afero.Fs, name string) ([]PriorValue, error) {
+	enabled, err := afero.Exists(fs, unitWantsPath(name))
+	return []PriorValue{{Kind: PriorSystemdUnit, Key: name, Exists: enabled}}, err
+}
+
+func unitWantsPath(name string) string {
+	return filepath.Join(filepath.Dir(systemd.UnitPath(name)), "multi-user.target.wants", name)
+}
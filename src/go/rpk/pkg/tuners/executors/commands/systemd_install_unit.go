@@ -53,3 +53,11 @@ EOF
 	_, err = fmt.Fprint(w, "sudo systemctl daemon-reload\n")
 	return err
 }
+
+func (cmd *installSystemdUnitCommand) Prior() ([]PriorValue, error) {
+	v, err := filePrior(cmd.fs, systemd.UnitPath(cmd.name))
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{v}, nil
+}
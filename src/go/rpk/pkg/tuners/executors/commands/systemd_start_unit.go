@@ -38,3 +38,8 @@ func (cmd *startSystemdUnitCommand) RenderScript(w *bufio.Writer) error {
 	_, err := fmt.Fprintf(w, "sudo systemctl start %s\n", cmd.name)
 	return err
 }
+
+// Prior returns a PriorNone value: rpk does not stop units when reverting.
+func (cmd *startSystemdUnitCommand) Prior() ([]PriorValue, error) {
+	return []PriorValue{{Kind: PriorNone, Key: "systemctl start " + cmd.name}}, nil
+}
@@ -75,3 +75,18 @@ func (c *writeFileCommand) RenderScript(w *bufio.Writer) error {
 	}
 	return w.Flush()
 }
+
+func (c *writeFileCommand) Prior() ([]PriorValue, error) {
+	v, err := filePrior(c.fs, c.path)
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{v}, nil
+}
+
+func (c *writeFileCommand) changes() []Change {
+	if !isKernelPath(c.path) {
+		return nil
+	}
+	return []Change{{Kind: PriorFile, Key: c.path, Value: c.content}}
+}
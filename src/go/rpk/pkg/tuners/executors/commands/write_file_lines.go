@@ -47,3 +47,11 @@ func (c *writeFileLinesCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintln(w, "EOF")
 	return w.Flush()
 }
+
+func (c *writeFileLinesCommand) Prior() ([]PriorValue, error) {
+	v, err := filePrior(c.fs, c.path)
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{v}, nil
+}
@@ -84,3 +84,14 @@ func (c *writeSizedFileCommand) RenderScript(w *bufio.Writer) error {
 	)
 	return w.Flush()
 }
+
+func (c *writeSizedFileCommand) Prior() ([]PriorValue, error) {
+	info, err := os.Stat(c.path)
+	if os.IsNotExist(err) {
+		return []PriorValue{{Kind: PriorFile, Key: c.path}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []PriorValue{{Kind: PriorFileSize, Key: c.path, Size: info.Size()}}, nil
+}
@@ -15,3 +15,9 @@ type Executor interface {
 	Execute(commands.Command) error
 	IsLazy() bool
 }
+
+// TunerSetter is implemented by executors that track which tuner executes
+// the subsequent commands.
+type TunerSetter interface {
+	SetTuner(tuner string)
+}
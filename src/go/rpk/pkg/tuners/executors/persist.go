@@ -0,0 +1,322 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	vos "github.com/redpanda-data/redpanda/src/go/rpk/pkg/os"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system/systemd"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// The files that persisted tuning is written to.
+const (
+	SysctlDropInFile = "/etc/sysctl.d/99-redpanda.conf"
+	UdevRulesFile    = "/etc/udev/rules.d/99-redpanda.rules"
+	PersistUnitName  = "redpanda-tune.service"
+
+	// DefaultRpkPath is the rpk binary that the systemd unit re-runs
+	// tuners with, unless another is set with SetTuneCommand.
+	DefaultRpkPath = "/usr/bin/rpk"
+)
+
+// PersistedFile is a file that a PersistingExecutor writes.
+type PersistedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	// Changed is whether Content differs from the file on disk.
+	Changed bool `json:"changed"`
+}
+
+// persistFormat describes a file that persisted changes are written to, one
+// change per line.
+type persistFormat struct {
+	path   string
+	header string
+	footer string
+	// key returns the key of a line written by a previous run, such that
+	// the change is kept unless it is overridden.
+	key func(line string) (string, bool)
+}
+
+const irqDir = "/proc/irq/"
+
+var (
+	udevRuleRe  = regexp.MustCompile(`KERNEL=="([^"]+)", ATTR\{queue/([^}]+)\}=`)
+	unitExecRe  = regexp.MustCompile(`^ExecStart=-?/bin/sh -c "(?:echo '.*' > (\S+)|ethtool -K (\S+ \S+) \S+)"$`)
+	unitTuneRe  = regexp.MustCompile(`^ExecStart=-\S+ redpanda tune .*?(\S+)$`)
+	queueAttrRe = regexp.MustCompile(`^/sys/.+/([^/]+)/queue/([^/]+)$`)
+
+	sysctlFormat = persistFormat{
+		path:   SysctlDropInFile,
+		header: "# Redpanda tuning, written by 'rpk redpanda tune --persist'.\n",
+		key: func(line string) (string, bool) {
+			if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				return "", false
+			}
+			key, _, ok := strings.Cut(line, "=")
+			return strings.TrimSpace(key), ok
+		},
+	}
+	udevFormat = persistFormat{
+		path:   UdevRulesFile,
+		header: "# Redpanda tuning, written by 'rpk redpanda tune --persist'.\n",
+		key: func(line string) (string, bool) {
+			m := udevRuleRe.FindStringSubmatch(line)
+			if m == nil {
+				return "", false
+			}
+			return m[1] + " " + m[2], true
+		},
+	}
+	unitFormat = persistFormat{
+		path: systemd.UnitPath(PersistUnitName),
+		header: `# Redpanda tuning, written by 'rpk redpanda tune --persist'.
+[Unit]
+Description=Redpanda tuning persisted by rpk
+After=network.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+`,
+		footer: `
+[Install]
+WantedBy=multi-user.target
+`,
+		key: func(line string) (string, bool) {
+			if m := unitTuneRe.FindStringSubmatch(line); m != nil {
+				return "tune " + m[1], true
+			}
+			// IRQ affinity written by previous versions is dropped:
+			// IRQ numbers change across reboots.
+			m := unitExecRe.FindStringSubmatch(line)
+			if m == nil || strings.HasPrefix(m[1], irqDir) {
+				return "", false
+			}
+			return m[1] + m[2], true
+		},
+	}
+)
+
+// PersistingExecutor persists the changes of kernel state that commands make
+// such that they survive reboots:
+//
+//   - sysctl keys and /proc/sys writes go to a sysctl.d drop-in,
+//   - block device queue attributes, such as the scheduler and nomerges,
+//     go to udev rules,
+//   - every other /proc and /sys write and ethtool features go to a oneshot
+//     systemd unit that applies them at boot,
+//   - IRQ affinity also goes to the systemd unit, but as a re-run of the
+//     tuner that set it: IRQ numbers change across reboots, so the tuner has
+//     to find the IRQs of its devices again.
+//
+// Every line of the unit may fail without failing the unit, such that a
+// missing device does not keep the rest of the tuning from applying.
+//
+// Commands are also executed with the wrapped executor, if any, such that the
+// tuning applies immediately. Changes persisted by previous runs are kept
+// unless they are overridden, such that persisting is idempotent.
+type PersistingExecutor struct {
+	executor Executor
+	fs       afero.Fs
+	proc     vos.Proc
+	timeout  time.Duration
+	lines    map[*persistFormat]map[string]string
+
+	tuner   string
+	tuneCmd []string
+}
+
+// NewPersistingExecutor returns an executor that persists changes, and
+// executes commands with executor. If executor is nil, commands are only
+// collected, which is used to preview the persisted files.
+func NewPersistingExecutor(
+	fs afero.Fs, executor Executor, proc vos.Proc, timeout time.Duration,
+) *PersistingExecutor {
+	return &PersistingExecutor{
+		executor: executor,
+		fs:       fs,
+		proc:     proc,
+		timeout:  timeout,
+		lines:    make(map[*persistFormat]map[string]string),
+		tuneCmd:  []string{DefaultRpkPath, "redpanda", "tune"},
+	}
+}
+
+// SetTuner sets the tuner that subsequent commands are persisted for, and
+// sets it on the wrapped executor if it tracks tuners too.
+func (e *PersistingExecutor) SetTuner(tuner string) {
+	e.tuner = tuner
+	if s, ok := e.executor.(TunerSetter); ok {
+		s.SetTuner(tuner)
+	}
+}
+
+// SetTuneCommand sets the command, without the tuner name, that the systemd
+// unit re-runs tuners with to persist IRQ affinity, e.g. "/usr/bin/rpk
+// redpanda tune --mode numa".
+func (e *PersistingExecutor) SetTuneCommand(args []string) {
+	e.tuneCmd = args
+}
+
+func (e *PersistingExecutor) Execute(cmd commands.Command) error {
+	for _, c := range commands.Changes(cmd) {
+		e.add(c)
+	}
+	if e.executor == nil {
+		return nil
+	}
+	return e.executor.Execute(cmd)
+}
+
+func (e *PersistingExecutor) IsLazy() bool {
+	return e.executor == nil || e.executor.IsLazy()
+}
+
+func (e *PersistingExecutor) add(c commands.Change) {
+	var (
+		format    *persistFormat
+		key, line string
+	)
+	switch {
+	case c.Kind == commands.PriorSysctl:
+		format, key = &sysctlFormat, c.Key
+		line = fmt.Sprintf("%s = %s", key, c.Value)
+	case c.Kind == commands.PriorEthtool:
+		format, key = &unitFormat, c.Key
+		line = fmt.Sprintf(`ExecStart=-/bin/sh -c "ethtool -K %s %s"`, c.Key, c.Value)
+	case strings.HasPrefix(c.Key, "/proc/sys/"):
+		format = &sysctlFormat
+		key = strings.ReplaceAll(strings.TrimPrefix(c.Key, "/proc/sys/"), "/", ".")
+		line = fmt.Sprintf("%s = %s", key, c.Value)
+	case strings.HasPrefix(c.Key, irqDir):
+		if e.tuner == "" {
+			log.Warnf("Unable to persist %s: unknown tuner to re-run at boot", c.Key)
+			return
+		}
+		format, key = &unitFormat, "tune "+e.tuner
+		line = "ExecStart=-" + unitCommandLine(append(e.tuneCmd[:len(e.tuneCmd):len(e.tuneCmd)], e.tuner))
+	case queueAttrRe.MatchString(c.Key):
+		m := queueAttrRe.FindStringSubmatch(c.Key)
+		format, key = &udevFormat, m[1]+" "+m[2]
+		line = fmt.Sprintf(`ACTION=="add|change", SUBSYSTEM=="block", KERNEL=="%s", ATTR{queue/%s}="%s"`, m[1], m[2], c.Value)
+	default:
+		format, key = &unitFormat, c.Key
+		line = fmt.Sprintf(`ExecStart=-/bin/sh -c "echo '%s' > %s"`, c.Value, c.Key)
+	}
+	if e.lines[format] == nil {
+		e.lines[format] = make(map[string]string)
+	}
+	e.lines[format][key] = line
+}
+
+// unitCommandLine joins args into a systemd command line, quoting arguments
+// that contain whitespace or quotes and escaping specifiers.
+func unitCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "%", "%%")
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Files returns the files that persist the collected changes, merged with the
+// changes persisted by previous runs.
+func (e *PersistingExecutor) Files() ([]PersistedFile, error) {
+	var files []PersistedFile
+	for _, format := range []*persistFormat{&sysctlFormat, &udevFormat, &unitFormat} {
+		lines := e.lines[format]
+		if len(lines) == 0 {
+			continue
+		}
+		existing, err := afero.ReadFile(e.fs, format.path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		merged := make(map[string]string, len(lines))
+		for _, line := range strings.Split(string(existing), "\n") {
+			if key, ok := format.key(line); ok {
+				merged[key] = line
+			}
+		}
+		for key, line := range lines {
+			merged[key] = line
+		}
+		keys := make([]string, 0, len(merged))
+		for key := range merged {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var sb strings.Builder
+		sb.WriteString(format.header)
+		for _, key := range keys {
+			sb.WriteString(merged[key])
+			sb.WriteString("\n")
+		}
+		sb.WriteString(format.footer)
+		files = append(files, PersistedFile{
+			Path:    format.path,
+			Content: sb.String(),
+			Changed: sb.String() != string(existing),
+		})
+	}
+	return files, nil
+}
+
+// Persist writes the changed files with the wrapped executor and enables the
+// systemd unit if it changed, returning every file.
+func (e *PersistingExecutor) Persist() ([]PersistedFile, error) {
+	if e.executor == nil {
+		return nil, fmt.Errorf("unable to persist changes without an executor")
+	}
+	files, err := e.Files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if !f.Changed {
+			continue
+		}
+		if err := e.fs.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+			return nil, err
+		}
+		if err := e.executor.Execute(commands.NewWriteFileCmd(e.fs, f.Path, f.Content)); err != nil {
+			return nil, fmt.Errorf("unable to write %s: %v", f.Path, err)
+		}
+		if f.Path != unitFormat.path {
+			continue
+		}
+		for _, cmd := range []commands.Command{
+			commands.NewLaunchCmd(e.proc, e.timeout, "systemctl", "daemon-reload"),
+			commands.NewEnableSystemdUnitCmd(e.fs, e.proc, e.timeout, PersistUnitName),
+		} {
+			if err := e.executor.Execute(cmd); err != nil {
+				return nil, fmt.Errorf("unable to enable %s: %v", PersistUnitName, err)
+			}
+		}
+	}
+	return files, nil
+}
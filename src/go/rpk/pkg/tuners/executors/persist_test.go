@@ -0,0 +1,170 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/system/systemd"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProc struct{ runs []string }
+
+func (p *fakeProc) RunWithSystemLdPath(
+	_ time.Duration, command string, args ...string,
+) ([]string, error) {
+	p.runs = append(p.runs, strings.Join(append([]string{command}, args...), " "))
+	return nil, nil
+}
+
+func (*fakeProc) IsRunning(time.Duration, string) bool { return false }
+
+const schedulerFile = "/sys/devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1/queue/scheduler"
+
+func tuneCommands(fs afero.Fs) []commands.Command {
+	return []commands.Command{
+		commands.NewSysctlSetCmd("net.core.somaxconn", "4096"),
+		commands.NewWriteFileCmd(fs, "/proc/sys/vm/swappiness", "1"),
+		commands.NewWriteFileCmd(fs, schedulerFile, "none"),
+		commands.NewWriteFileModeCmd(fs, "/proc/irq/24/smp_affinity", "00000002", 0o555),
+		commands.NewWriteFileCmd(fs, "/sys/class/net/eth0/queues/rx-0/rps_cpus", "ff"),
+		commands.NewWriteFileCmd(fs, "/etc/regular", "not persisted"),
+	}
+}
+
+func TestPersistingExecutorFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// A unit written by a previous version, which persisted IRQ affinity
+	// by IRQ number.
+	old := `[Service]
+ExecStart=/bin/sh -c "echo '00000001' > /proc/irq/30/smp_affinity"
+ExecStart=/bin/sh -c "ethtool -K eth1 gro on"
+`
+	unitPath := systemd.UnitPath(executors.PersistUnitName)
+	require.NoError(t, afero.WriteFile(fs, unitPath, []byte(old), 0o644))
+
+	e := executors.NewPersistingExecutor(fs, nil, new(fakeProc), time.Second)
+	require.True(t, e.IsLazy())
+	e.SetTuneCommand([]string{"/opt/rpk", "redpanda", "tune", "--dirs=/var/lib/my data"})
+	e.SetTuner("disk_irq")
+	for _, cmd := range tuneCommands(fs) {
+		require.NoError(t, e.Execute(cmd))
+	}
+	// Without an executor, nothing is applied.
+	exists, err := afero.Exists(fs, "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	files, err := e.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	require.Equal(t, executors.SysctlDropInFile, files[0].Path)
+	require.True(t, files[0].Changed)
+	require.Contains(t, files[0].Content, "net.core.somaxconn = 4096\nvm.swappiness = 1\n")
+
+	require.Equal(t, executors.UdevRulesFile, files[1].Path)
+	require.Contains(t, files[1].Content, `ACTION=="add|change", SUBSYSTEM=="block", KERNEL=="nvme0n1", ATTR{queue/scheduler}="none"`)
+
+	require.Equal(t, unitPath, files[2].Path)
+	require.Contains(t, files[2].Content, `ExecStart=-/bin/sh -c "echo 'ff' > /sys/class/net/eth0/queues/rx-0/rps_cpus"
+ExecStart=/bin/sh -c "ethtool -K eth1 gro on"
+ExecStart=-/opt/rpk redpanda tune "--dirs=/var/lib/my data" disk_irq
+`)
+	// IRQ numbers change across reboots, so IRQ affinity is persisted by
+	// re-running the tuner rather than by IRQ.
+	require.NotContains(t, files[2].Content, "/proc/irq")
+	require.Contains(t, files[2].Content, "WantedBy=multi-user.target")
+	require.NotContains(t, files[2].Content, "/etc/regular")
+
+	_, err = e.Persist()
+	require.Error(t, err)
+}
+
+// fakeExecutor executes commands on an in-memory filesystem, skipping
+// sysctls, which would change the host.
+type fakeExecutor struct{}
+
+func (fakeExecutor) Execute(cmd commands.Command) error {
+	for _, c := range commands.Changes(cmd) {
+		if c.Kind == commands.PriorSysctl {
+			return nil
+		}
+	}
+	return cmd.Execute()
+}
+
+func (fakeExecutor) IsLazy() bool { return false }
+
+func TestPersistingExecutorPersist(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	proc := new(fakeProc)
+	unitPath := systemd.UnitPath(executors.PersistUnitName)
+
+	e := executors.NewPersistingExecutor(fs, fakeExecutor{}, proc, time.Second)
+	e.SetTuner("disk_irq")
+	for _, cmd := range tuneCommands(fs) {
+		require.NoError(t, e.Execute(cmd))
+	}
+	files, err := e.Files()
+	require.NoError(t, err)
+	persisted, err := e.Persist()
+	require.NoError(t, err)
+	require.Equal(t, files, persisted)
+	for _, f := range persisted {
+		content, err := afero.ReadFile(fs, f.Path)
+		require.NoError(t, err)
+		require.Equal(t, f.Content, string(content))
+	}
+	require.Equal(t, []string{"systemctl daemon-reload", "systemctl enable " + executors.PersistUnitName}, proc.runs)
+	// The command is applied as well.
+	content, err := afero.ReadFile(fs, "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	require.Equal(t, "1", string(content))
+	sysctls, err := afero.ReadFile(fs, executors.SysctlDropInFile)
+	require.NoError(t, err)
+
+	// Persisting other tuners keeps what was persisted before, and IRQ
+	// affinity is persisted once per tuner, whatever the IRQs are.
+	irqs := []commands.Command{
+		commands.NewWriteFileModeCmd(fs, "/proc/irq/24/smp_affinity", "00000004", 0o555),
+		commands.NewWriteFileModeCmd(fs, "/proc/irq/25/smp_affinity", "00000008", 0o555),
+	}
+	for i := 0; i < 2; i++ {
+		proc.runs = nil
+		e = executors.NewPersistingExecutor(fs, fakeExecutor{}, proc, time.Second)
+		e.SetTuner("net")
+		for _, cmd := range irqs {
+			require.NoError(t, e.Execute(cmd))
+		}
+		persisted, err = e.Persist()
+		require.NoError(t, err)
+		require.Len(t, persisted, 1)
+		require.Equal(t, unitPath, persisted[0].Path)
+		// Persisting the same changes twice is a no-op.
+		require.Equal(t, i == 0, persisted[0].Changed)
+		require.Equal(t, i == 0, len(proc.runs) > 0)
+	}
+	unit, err := afero.ReadFile(fs, unitPath)
+	require.NoError(t, err)
+	require.Contains(t, string(unit), `ExecStart=-/bin/sh -c "echo 'ff' > /sys/class/net/eth0/queues/rx-0/rps_cpus"
+ExecStart=-/usr/bin/rpk redpanda tune disk_irq
+ExecStart=-/usr/bin/rpk redpanda tune net
+`)
+	after, err := afero.ReadFile(fs, executors.SysctlDropInFile)
+	require.NoError(t, err)
+	require.Equal(t, string(sysctls), string(after))
+}
@@ -21,8 +21,31 @@ import (
 type scriptRenderingExecutor struct {
 	deffered error
 	writer   *bufio.Writer
+
+	// If undoPath is non-empty, the undo script is rewritten with every
+	// command to revert the values in undo, newest first.
+	fs       afero.Fs
+	undoPath string
+	undo     TuneState
 }
 
+const scriptHeader = `#!/bin/bash
+
+# Redpanda Tuning Script
+# ----------------------------------
+# This file was autogenerated by RPK
+
+`
+
+const undoScriptHeader = `#!/bin/bash
+
+# Redpanda Tuning Undo Script
+# ----------------------------------
+# This file was autogenerated by RPK, and reverts the values changed by
+# the matching tuning script to their values when the script was rendered.
+
+`
+
 // FIXME: @david
 // This should also return an error.
 func NewScriptRenderingExecutor(fs afero.Fs, filename string) Executor {
@@ -33,15 +56,8 @@ func NewScriptRenderingExecutor(fs afero.Fs, filename string) Executor {
 			writer:   nil,
 		}
 	}
-	header := `#!/bin/bash
-
-# Redpanda Tuning Script
-# ----------------------------------
-# This file was autogenerated by RPK
-
-`
 	w := bufio.NewWriter(file)
-	_, _ = fmt.Fprint(w, header)
+	_, _ = fmt.Fprint(w, scriptHeader)
 	_ = w.Flush()
 	return &scriptRenderingExecutor{
 		deffered: nil,
@@ -49,7 +65,33 @@ func NewScriptRenderingExecutor(fs afero.Fs, filename string) Executor {
 	}
 }
 
+// NewUndoScriptRenderingExecutor returns an executor that renders a tuning
+// script to filename and a script that reverts it to undoFilename.
+func NewUndoScriptRenderingExecutor(
+	fs afero.Fs, filename, undoFilename string,
+) Executor {
+	e := NewScriptRenderingExecutor(fs, filename).(*scriptRenderingExecutor)
+	e.fs = fs
+	e.undoPath = undoFilename
+	if e.deffered == nil {
+		e.deffered = e.writeUndo()
+	}
+	return e
+}
+
 func (e *scriptRenderingExecutor) Execute(cmd commands.Command) error {
+	if e.undoPath != "" {
+		priors, err := cmd.Prior()
+		if err != nil {
+			return err
+		}
+		for _, v := range priors {
+			e.undo.Add("", v)
+		}
+		if err := e.writeUndo(); err != nil {
+			return err
+		}
+	}
 	err := cmd.RenderScript(e.writer)
 	if err != nil {
 		return err
@@ -57,6 +99,28 @@ func (e *scriptRenderingExecutor) Execute(cmd commands.Command) error {
 	return e.writer.Flush()
 }
 
+func (e *scriptRenderingExecutor) writeUndo() error {
+	file, err := e.fs.OpenFile(e.undoPath, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0o755)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	fmt.Fprint(w, undoScriptHeader)
+	for i := len(e.undo.Entries) - 1; i >= 0; i-- {
+		v := e.undo.Entries[i].Prior
+		cmd, err := commands.NewRevertCmd(e.fs, v)
+		if err != nil {
+			fmt.Fprintf(w, "# not reverted (%v): %s\n", err, v.Key)
+			continue
+		}
+		if err := cmd.RenderScript(w); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
 func (*scriptRenderingExecutor) IsLazy() bool {
 	return true
 }
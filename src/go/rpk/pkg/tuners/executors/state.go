@@ -0,0 +1,160 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// DefaultStateFile is where the values that tuners change are recorded.
+const DefaultStateFile = "/var/lib/redpanda/tune_state.json"
+
+// TuneState is the record of the values that tuners changed, in the order
+// they were changed. Only the first prior value of any state is recorded,
+// such that tuning twice and reverting restores the original value.
+type TuneState struct {
+	Entries []StateEntry `json:"entries"`
+}
+
+// StateEntry is the prior value of state that a tuner changed.
+type StateEntry struct {
+	Tuner string              `json:"tuner"`
+	Time  time.Time           `json:"time"`
+	Prior commands.PriorValue `json:"prior"`
+}
+
+// ReadTuneState reads the state file at path; a missing file is an empty
+// state.
+func ReadTuneState(fs afero.Fs, path string) (*TuneState, error) {
+	s := new(TuneState)
+	raw, err := afero.ReadFile(fs, path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// Write writes the state to path, creating its directory if needed.
+func (s *TuneState) Write(fs afero.Fs, path string) error {
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, path, append(raw, '\n'), 0o644)
+}
+
+// Add records the prior value for the tuner, returning false if a prior value
+// of the same state is already recorded.
+func (s *TuneState) Add(tuner string, v commands.PriorValue) bool {
+	for _, e := range s.Entries {
+		if e.Prior.SameState(v) {
+			return false
+		}
+	}
+	s.Entries = append(s.Entries, StateEntry{Tuner: tuner, Time: time.Now(), Prior: v})
+	return true
+}
+
+// Take removes and returns the entries of the tuners, or of all tuners if
+// none are specified, newest first, which is the order to revert them in.
+func (s *TuneState) Take(tuners []string) []StateEntry {
+	take := make(map[string]bool, len(tuners))
+	for _, t := range tuners {
+		take[t] = true
+	}
+	var taken, kept []StateEntry
+	for _, e := range s.Entries {
+		if len(tuners) == 0 || take[e.Tuner] {
+			taken = append([]StateEntry{e}, taken...)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	s.Entries = kept
+	return taken
+}
+
+// RecordingExecutor records the prior values of every command to a state
+// file before executing it with the wrapped executor.
+type RecordingExecutor struct {
+	Executor
+	fs    afero.Fs
+	path  string
+	state *TuneState
+	tuner string
+
+	bestEffort bool
+	failed     bool
+}
+
+func NewRecordingExecutor(
+	fs afero.Fs, path string, executor Executor,
+) (*RecordingExecutor, error) {
+	state, err := ReadTuneState(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingExecutor{
+		Executor: executor,
+		fs:       fs,
+		path:     path,
+		state:    state,
+	}, nil
+}
+
+// SetBestEffort makes the executor log a warning and keep executing commands
+// without recording them when the state file cannot be written, rather than
+// failing the commands.
+func (e *RecordingExecutor) SetBestEffort() {
+	e.bestEffort = true
+}
+
+// SetTuner sets the tuner that subsequent commands are recorded for.
+func (e *RecordingExecutor) SetTuner(tuner string) {
+	e.tuner = tuner
+}
+
+func (e *RecordingExecutor) Execute(cmd commands.Command) error {
+	priors, err := cmd.Prior()
+	if err != nil {
+		return fmt.Errorf("unable to record the current state before changing it: %v", err)
+	}
+	var changed bool
+	for _, v := range priors {
+		changed = e.state.Add(e.tuner, v) || changed
+	}
+	if changed && !e.failed {
+		if err := e.state.Write(e.fs, e.path); err != nil {
+			if !e.bestEffort {
+				return fmt.Errorf("unable to write tuner state file: %v", err)
+			}
+			log.Warnf("Unable to write tuner state file %s, tuning without recording the prior values: %v", e.path, err)
+			e.failed = true
+		}
+	}
+	return e.Executor.Execute(cmd)
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const stateFile = "/var/lib/redpanda/tune_state.json"
+
+func TestRecordingExecutor(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/a", []byte("original"), 0o644))
+
+	e, err := executors.NewRecordingExecutor(fs, stateFile, executors.NewDirectExecutor())
+	require.NoError(t, err)
+	e.SetTuner("first")
+	require.NoError(t, e.Execute(commands.NewWriteFileCmd(fs, "/etc/a", "tuned")))
+	e.SetTuner("second")
+	require.NoError(t, e.Execute(commands.NewWriteFileCmd(fs, "/etc/b", "tuned")))
+
+	// Tuning again must not overwrite the original values.
+	e, err = executors.NewRecordingExecutor(fs, stateFile, executors.NewDirectExecutor())
+	require.NoError(t, err)
+	e.SetTuner("first")
+	require.NoError(t, e.Execute(commands.NewWriteFileCmd(fs, "/etc/a", "tuned again")))
+
+	state, err := executors.ReadTuneState(fs, stateFile)
+	require.NoError(t, err)
+	require.Len(t, state.Entries, 2)
+	require.Equal(t, "first", state.Entries[0].Tuner)
+	require.Equal(t, "original", state.Entries[0].Prior.Value)
+	require.True(t, state.Entries[0].Prior.Exists)
+	require.Equal(t, "second", state.Entries[1].Tuner)
+	require.False(t, state.Entries[1].Prior.Exists)
+
+	for _, entry := range state.Take(nil) {
+		cmd, err := commands.NewRevertCmd(fs, entry.Prior)
+		require.NoError(t, err)
+		require.NoError(t, cmd.Execute())
+	}
+	require.Empty(t, state.Entries)
+	content, err := afero.ReadFile(fs, "/etc/a")
+	require.NoError(t, err)
+	require.Equal(t, "original", string(content))
+	exists, err := afero.Exists(fs, "/etc/b")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestRecordingExecutorBestEffort(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	e, err := executors.NewRecordingExecutor(afero.NewReadOnlyFs(fs), stateFile, executors.NewDirectExecutor())
+	require.NoError(t, err)
+	cmd := commands.NewWriteFileCmd(fs, "/etc/a", "tuned")
+	require.Error(t, e.Execute(cmd))
+
+	e.SetBestEffort()
+	require.NoError(t, e.Execute(cmd))
+	content, err := afero.ReadFile(fs, "/etc/a")
+	require.NoError(t, err)
+	require.Equal(t, "tuned", string(content))
+}
+
+func TestTuneStateTake(t *testing.T) {
+	state := new(executors.TuneState)
+	for i, tuner := range []string{"a", "b", "a", "c"} {
+		state.Add(tuner, commands.PriorValue{Kind: commands.PriorSysctl, Key: string(rune('w' + i))})
+	}
+	require.False(t, state.Add("b", commands.PriorValue{Kind: commands.PriorSysctl, Key: "w"}))
+
+	taken := state.Take([]string{"a"})
+	require.Len(t, taken, 2)
+	require.Equal(t, "y", taken[0].Prior.Key) // newest first
+	require.Equal(t, "w", taken[1].Prior.Key)
+
+	require.Len(t, state.Entries, 2)
+	require.Equal(t, "x", state.Entries[0].Prior.Key)
+	require.Equal(t, "z", state.Entries[1].Prior.Key)
+
+	state, err := executors.ReadTuneState(afero.NewMemMapFs(), stateFile)
+	require.NoError(t, err)
+	require.Empty(t, state.Entries)
+}
+
+func TestUndoScriptRenderingExecutor(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/a", []byte("original"), 0o644))
+
+	e := executors.NewUndoScriptRenderingExecutor(fs, "/tune.sh", "/undo.sh")
+	require.NoError(t, e.Execute(commands.NewWriteFileCmd(fs, "/etc/a", "tuned")))
+	require.NoError(t, e.Execute(commands.NewWriteFileCmd(fs, "/etc/b", "tuned")))
+	start, err := commands.NewStartSystemdUnitCmd(nil, "foo.service")
+	require.NoError(t, err)
+	require.NoError(t, e.Execute(start))
+
+	undo, err := afero.ReadFile(fs, "/undo.sh")
+	require.NoError(t, err)
+	lines := strings.Split(string(undo), "\n")
+	var body []string
+	for _, line := range lines {
+		if line != "" && !strings.HasPrefix(line, "#!") && !strings.HasPrefix(line, "# ") {
+			body = append(body, line)
+		}
+	}
+	require.Equal(t, "rm -f /etc/b", body[0])
+	require.Contains(t, string(undo), "original")
+	require.Contains(t, string(undo), "# not reverted")
+
+	// Rendering does not change anything.
+	content, err := afero.ReadFile(fs, "/etc/a")
+	require.NoError(t, err)
+	require.Equal(t, "original", string(content))
+}
@@ -96,6 +96,17 @@ func NewScriptRenderingTunersFactory(
 	return newTunersFactory(fs, conf, irqProcFile, proc, irqDeviceInfo, executor, timeout)
 }
 
+// NewTunersFactory returns a factory of tuners that execute their commands
+// with the executor.
+func NewTunersFactory(
+	fs afero.Fs, conf config.Config, executor executors.Executor, timeout time.Duration,
+) TunersFactory {
+	irqProcFile := irq.NewProcFile(fs)
+	proc := os.NewProc()
+	irqDeviceInfo := irq.NewDeviceInfo(fs, irqProcFile)
+	return newTunersFactory(fs, conf, irqProcFile, proc, irqDeviceInfo, executor, timeout)
+}
+
 func newTunersFactory(
 	fs afero.Fs,
 	conf config.Config,
@@ -311,6 +322,9 @@ func MergeTunerParamsConfig(
 	if len(params.Directories) == 0 {
 		params.Directories = []string{conf.Redpanda.Directory}
 	}
+	if params.Mode == "" {
+		params.Mode = conf.Rpk.IrqMode
+	}
 	return params, nil
 }
 
@@ -331,5 +345,6 @@ func FillTunerParamsWithValuesFromConfig(
 	log.Infof("Redpanda uses '%v' NICs", params.Nics)
 	log.Infof("Redpanda data directory '%s'", conf.Redpanda.Directory)
 	params.Directories = []string{conf.Redpanda.Directory}
+	params.Mode = conf.Rpk.IrqMode
 	return nil
 }
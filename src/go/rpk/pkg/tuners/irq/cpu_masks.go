@@ -17,6 +17,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors/commands"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
@@ -35,6 +36,7 @@ type CPUMasks interface {
 	GetNumberOfPUs(mask string) (uint, error)
 	GetAllCpusMask() (string, error)
 	GetLogicalCoreIdsFromPhysCore(core uint) ([]uint, error)
+	NumaLocalCPUMask(syspath string, cpuMask string) (string, error)
 	IsSupported() bool
 }
 
@@ -45,6 +47,7 @@ func NewCPUMasks(
 		fs:       fs,
 		hwloc:    hwloc,
 		executor: executor,
+		topology: numa.NewTopology(fs),
 	}
 }
 
@@ -52,6 +55,7 @@ type cpuMasks struct {
 	hwloc    hwloc.HwLoc
 	fs       afero.Fs
 	executor executors.Executor
+	topology numa.Topology
 }
 
 func (masks *cpuMasks) BaseCPUMask(cpuMask string) (string, error) {
@@ -78,7 +82,7 @@ func (masks *cpuMasks) CPUMaskForComputations(
 	} else if mode == SqSplit {
 		// all but CPU0 and its HT siblings
 		computationsMask, err = masks.hwloc.Calc(cpuMask, "~core:0")
-	} else if mode == Mq {
+	} else if mode == Mq || mode == Numa {
 		// all available cores
 		computationsMask = cpuMask
 	} else {
@@ -100,7 +104,7 @@ func (masks *cpuMasks) CPUMaskForIRQs(
 		mode, cpuMask)
 	var err error
 	var maskForIRQs string
-	if mode != Mq {
+	if mode != Mq && mode != Numa {
 		maskForComputations, err := masks.CPUMaskForComputations(mode, cpuMask)
 		if err != nil {
 			return "", err
@@ -222,6 +226,37 @@ func (masks *cpuMasks) GetAllCpusMask() (string, error) {
 	return masks.hwloc.All()
 }
 
+// NumaLocalCPUMask restricts the given CPU mask to the CPUs of the NUMA node
+// that the device at the given sysfs path is attached to. The mask is returned
+// unchanged if the device is not attached to a node, or if none of the mask's
+// CPUs are on the device's node.
+func (masks *cpuMasks) NumaLocalCPUMask(
+	syspath string, cpuMask string,
+) (string, error) {
+	node, err := masks.topology.DeviceNode(syspath)
+	if err != nil || node == numa.NoNode {
+		return cpuMask, err
+	}
+	nodes, err := masks.topology.Nodes()
+	if err != nil {
+		return "", err
+	}
+	cpus, err := numa.ParseMask(cpuMask)
+	if err != nil {
+		return "", err
+	}
+	local := numa.Intersect(cpus, numa.NodeCPUs(nodes, node))
+	if len(local) == 0 {
+		log.Warnf("CPU mask '%s' has no CPUs on NUMA node %d, which '%s' is"+
+			" attached to; its IRQs will be handled by remote CPUs",
+			cpuMask, node, syspath)
+		return cpuMask, nil
+	}
+	localMask := numa.FormatMask(local)
+	log.Debugf("NUMA node %d local CPU mask '%s'", node, localMask)
+	return localMask, nil
+}
+
 func MasksEqual(a, b string) (bool, error) {
 	aParts := strings.Split(a, ",")
 	bParts := strings.Split(b, ",")
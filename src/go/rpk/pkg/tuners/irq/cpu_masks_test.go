@@ -71,3 +71,50 @@ func Test_masksEqual(t *testing.T) {
 		})
 	}
 }
+
+func Test_cpuMasks_NumaLocalCPUMask(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for path, content := range map[string]string{
+		"/sys/devices/system/node/node0/cpulist":           "0-3,8-11",
+		"/sys/devices/system/node/node1/cpulist":           "4-7,12-15",
+		"/sys/devices/pci0000:80/0000:80:04.0/numa_node":   "1",
+		"/sys/devices/pci0000:00/0000:00:03.0/numa_node":   "-1",
+		"/sys/devices/pci0000:00/0000:00:1f.6/numa_node":   "0",
+		"/sys/devices/pci0000:00/0000:00:1f.6/net/eth0/up": "1",
+	} {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0o644))
+	}
+	cpuMasks := NewCPUMasks(fs, nil, executors.NewDirectExecutor())
+	tests := []struct {
+		name    string
+		syspath string
+		cpuMask string
+		exp     string
+	}{
+		{
+			name:    "restricts the mask to the device node",
+			syspath: "/sys/devices/pci0000:80/0000:80:04.0/nvme/nvme1/nvme1n1",
+			cpuMask: "0x0000ffff",
+			exp:     "0x0000f0f0",
+		},
+		{
+			name:    "keeps the mask of devices without a node",
+			syspath: "/sys/devices/pci0000:00/0000:00:03.0/nvme/nvme0/nvme0n1",
+			cpuMask: "0x0000ffff",
+			exp:     "0x0000ffff",
+		},
+		{
+			name:    "keeps the mask if it has no CPUs on the device node",
+			syspath: "/sys/devices/pci0000:00/0000:00:1f.6/net/eth0",
+			cpuMask: "0x000000f0",
+			exp:     "0x000000f0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := cpuMasks.NumaLocalCPUMask(tt.syspath, tt.cpuMask)
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, mask)
+		})
+	}
+}
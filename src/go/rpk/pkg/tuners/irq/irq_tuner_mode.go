@@ -31,6 +31,12 @@ mq - distribute NIC's IRQs among all CPUs instead of binding
 	them all to CPU0. In this mode RPS is always enabled to
 	spreads NAPIs' handling between all CPUs.
 
+numa - like 'mq', but distribute the IRQs of each device only among the CPUs
+
+	of the NUMA node the device is attached to, so that interrupts are
+	never handled across the socket interconnect. Devices that are not
+	attached to a NUMA node are handled as in 'mq'.
+
 If there isn't any mode given script will use a default mode:
   - If number of physical CPU cores per Rx HW queue is greater than 4 - use the 'sq-split' mode.
   - Otherwise, if number of hyperthreads per Rx HW queue is greater than 4 - use the 'sq' mode.
@@ -42,6 +48,7 @@ const (
 	SqSplit Mode = "sq-split"
 	Sq      Mode = "sq"
 	Mq      Mode = "mq"
+	Numa    Mode = "numa"
 	Default Mode = "def"
 )
 
@@ -52,6 +59,8 @@ func ModeFromString(modeString string) Mode {
 		return Sq
 	} else if modeString == "sq-split" {
 		return SqSplit
+	} else if modeString == "numa" {
+		return Numa
 	}
 
 	return Default
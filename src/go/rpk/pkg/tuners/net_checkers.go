@@ -19,6 +19,7 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/ethtool"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/network"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -36,6 +37,8 @@ type NetCheckersFactory interface {
 	NewNicNTupleChecker(nic network.Nic) Checker
 	NewNicXpsCheckers(interfaces []string) []Checker
 	NewNicXpsChecker(nic network.Nic) Checker
+	NewNicNumaIRQsCheckers(interfaces []string) []Checker
+	NewNicNumaIRQsChecker(nic network.Nic) Checker
 	NewRfsTableSizeChecker() Checker
 	NewListenBacklogChecker() Checker
 	NewSynBacklogChecker() Checker
@@ -48,6 +51,7 @@ type netCheckersFactory struct {
 	ethtool        ethtool.EthtoolWrapper
 	balanceService irq.BalanceService
 	cpuMasks       irq.CPUMasks
+	topology       numa.Topology
 }
 
 func NewNetCheckersFactory(
@@ -65,6 +69,7 @@ func NewNetCheckersFactory(
 		ethtool:        ethtool,
 		balanceService: balanceService,
 		cpuMasks:       cpuMasks,
+		topology:       numa.NewTopology(fs),
 	}
 }
 
@@ -336,6 +341,38 @@ func (f *netCheckersFactory) NewSynBacklogChecker() Checker {
 	)
 }
 
+func (f *netCheckersFactory) NewNicNumaIRQsCheckers(
+	interfaces []string,
+) []Checker {
+	return f.forNonVirtualInterfaces(interfaces, f.NewNicNumaIRQsChecker)
+}
+
+func (f *netCheckersFactory) NewNicNumaIRQsChecker(nic network.Nic) Checker {
+	return NewEqualityChecker(
+		NicIRQsNumaChecker,
+		fmt.Sprintf("NIC %s IRQs NUMA local", nic.Name()),
+		Warning,
+		true,
+		func() (interface{}, error) {
+			nodes, err := f.topology.Nodes()
+			if err != nil || len(nodes) < 2 {
+				return true, err
+			}
+			return isSet(nic, func(currentNic network.Nic) (bool, error) {
+				node, err := f.topology.NicNode(currentNic.Name())
+				if err != nil {
+					return false, err
+				}
+				IRQs, err := currentNic.GetIRQs()
+				if err != nil {
+					return false, err
+				}
+				return areIRQsNumaLocal(IRQs, numa.NodeCPUs(nodes, node), f.cpuMasks)
+			})
+		},
+	)
+}
+
 func isSet(
 	nic network.Nic, hwCheckFunction func(network.Nic) (bool, error),
 ) (bool, error) {
@@ -116,6 +116,13 @@ func GetHwInterfaceIRQsDistribution(
 	if err != nil {
 		return nil, err
 	}
+	if effectiveMode == irq.Numa {
+		irqCPUMask, err = cpuMasks.NumaLocalCPUMask(
+			fmt.Sprintf("/sys/class/net/%s/device", nic.Name()), irqCPUMask)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if maxRxQueues >= len(allIRQs) {
 		log.Debugf("Calculating distribution '%s' IRQs", nic.Name())
@@ -0,0 +1,257 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package numa reads the NUMA topology of the host, and the NUMA node that
+// PCI devices such as NVMe controllers and NICs are attached to, from sysfs.
+//
+// Unlike hwloc, which works with logical indexes, the CPUs here are the
+// kernel's CPU numbers, which are what cpuset(7) lists, /proc/irq/*/smp_affinity
+// masks and redpanda's --cpuset flag use.
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// NoNode is the node of devices that are not attached to any specific
+	// NUMA node, such as virtual devices or devices on single node hosts.
+	NoNode = -1
+
+	nodesDir = "/sys/devices/system/node"
+	netDir   = "/sys/class/net"
+)
+
+var nodeDirRe = regexp.MustCompile(`^node(\d+)$`)
+
+type Node struct {
+	ID   int
+	CPUs []int
+}
+
+type Topology interface {
+	// Nodes returns the NUMA nodes of the host sorted by ID. Hosts without
+	// NUMA support have no nodes.
+	Nodes() ([]Node, error)
+	// DeviceNode returns the NUMA node of the device at the given sysfs
+	// path, or NoNode if the device is not attached to a node.
+	DeviceNode(syspath string) (int, error)
+	// NicNode returns the NUMA node of the given network interface, or
+	// NoNode if the interface is not attached to a node.
+	NicNode(name string) (int, error)
+}
+
+func NewTopology(fs afero.Fs) Topology {
+	return &topology{fs: fs}
+}
+
+type topology struct {
+	fs afero.Fs
+}
+
+func (t *topology) Nodes() ([]Node, error) {
+	if exists, _ := afero.DirExists(t.fs, nodesDir); !exists {
+		log.Debugf("'%s' does not exist, assuming no NUMA support", nodesDir)
+		return nil, nil
+	}
+	files, err := afero.ReadDir(t.fs, nodesDir)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []Node
+	for _, file := range files {
+		matches := nodeDirRe.FindStringSubmatch(file.Name())
+		if matches == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(matches[1])
+		cpulist, err := afero.ReadFile(t.fs, filepath.Join(nodesDir, file.Name(), "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		cpus, err := ParseCPUList(string(cpulist))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the CPUs of NUMA node %d: %v", id, err)
+		}
+		nodes = append(nodes, Node{ID: id, CPUs: cpus})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	log.Debugf("NUMA nodes: %v", nodes)
+	return nodes, nil
+}
+
+func (t *topology) DeviceNode(syspath string) (int, error) {
+	// Block devices and NVMe namespaces do not have a numa_node attribute
+	// themselves, their PCI controller, which is one of their parents, has.
+	for dir := filepath.Clean(syspath); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		node, err := utils.ReadIntFromFile(t.fs, filepath.Join(dir, "numa_node"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return NoNode, err
+		}
+		if node < 0 {
+			node = NoNode
+		}
+		log.Debugf("'%s' is attached to NUMA node %d", syspath, node)
+		return node, nil
+	}
+	return NoNode, nil
+}
+
+func (t *topology) NicNode(name string) (int, error) {
+	device := filepath.Join(netDir, name, "device")
+	if exists, _ := afero.Exists(t.fs, device); !exists {
+		log.Debugf("'%s' is a virtual interface, it has no NUMA node", name)
+		return NoNode, nil
+	}
+	return t.DeviceNode(device)
+}
+
+// NodeCPUs returns the CPUs of the node with the given ID, or nil if there is
+// no such node.
+func NodeCPUs(nodes []Node, id int) []int {
+	for _, node := range nodes {
+		if node.ID == id {
+			return node.CPUs
+		}
+	}
+	return nil
+}
+
+// CPUNodes returns the IDs of the nodes that the given CPUs are on.
+func CPUNodes(nodes []Node, cpus []int) []int {
+	var ids []int
+	for _, node := range nodes {
+		if len(Intersect(node.CPUs, cpus)) > 0 {
+			ids = append(ids, node.ID)
+		}
+	}
+	return ids
+}
+
+// Intersect returns the sorted CPUs that are in both a and b.
+func Intersect(a, b []int) []int {
+	inB := make(map[int]bool, len(b))
+	for _, cpu := range b {
+		inB[cpu] = true
+	}
+	var both []int
+	for _, cpu := range a {
+		if inB[cpu] {
+			both = append(both, cpu)
+			delete(inB, cpu)
+		}
+	}
+	sort.Ints(both)
+	return both
+}
+
+// ParseCPUList parses a list of CPUs in the cpuset(7) list format, such as
+// "0-3,8,10-11".
+func ParseCPUList(list string) ([]int, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q", list)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU list %q", list)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// FormatCPUList formats the given CPUs in the cpuset(7) list format,
+// collapsing consecutive CPUs into ranges.
+func FormatCPUList(cpus []int) string {
+	sorted := append([]int(nil), cpus...)
+	sort.Ints(sorted)
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] <= sorted[j]+1 {
+			j++
+		}
+		if sorted[i] == sorted[j] {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseMask returns the CPUs set in a CPU mask made of comma separated 32 bit
+// hex groups, most significant first, as used by /proc/irq/*/smp_affinity and
+// hwloc, e.g. "0xff0,,0x13". Empty groups are zero.
+func ParseMask(mask string) ([]int, error) {
+	groups := strings.Split(strings.TrimSpace(mask), ",")
+	var cpus []int
+	for i, group := range groups {
+		group = strings.TrimPrefix(group, "0x")
+		if group == "" {
+			continue
+		}
+		bits, err := strconv.ParseUint(group, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU mask %q", mask)
+		}
+		offset := 32 * (len(groups) - 1 - i)
+		for bit := 0; bit < 32; bit++ {
+			if bits&(1<<bit) != 0 {
+				cpus = append(cpus, offset+bit)
+			}
+		}
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// FormatMask formats the given CPUs as a CPU mask in the format hwloc uses,
+// e.g. "0x0000000f,0xffffffff".
+func FormatMask(cpus []int) string {
+	groups := make([]uint32, 1)
+	for _, cpu := range cpus {
+		for len(groups) <= cpu/32 {
+			groups = append(groups, 0)
+		}
+		groups[cpu/32] |= 1 << (cpu % 32)
+	}
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		parts[len(groups)-1-i] = fmt.Sprintf("0x%08x", group)
+	}
+	return strings.Join(parts, ",")
+}
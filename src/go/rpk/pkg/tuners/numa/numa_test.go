@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package numa
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const nvmeSyspath = "/sys/devices/pci0000:80/0000:80:04.0/nvme/nvme1/nvme1n1"
+
+func dualSocketFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/sys/devices/system/node/possible":              "0-1\n",
+		"/sys/devices/system/node/node0/cpulist":         "0-3,8-11\n",
+		"/sys/devices/system/node/node1/cpulist":         "4-7,12-15\n",
+		"/sys/devices/pci0000:80/0000:80:04.0/numa_node": "1\n",
+		"/sys/devices/pci0000:00/0000:00:1f.6/numa_node": "-1\n",
+		"/sys/class/net/eth0/device/numa_node":           "0\n",
+	}
+	for path, content := range files {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0o644))
+	}
+	require.NoError(t, fs.MkdirAll(nvmeSyspath, 0o755))
+	require.NoError(t, fs.MkdirAll("/sys/class/net/lo", 0o755))
+	return fs
+}
+
+func TestNodes(t *testing.T) {
+	nodes, err := NewTopology(dualSocketFs(t)).Nodes()
+	require.NoError(t, err)
+	require.Equal(t, []Node{
+		{ID: 0, CPUs: []int{0, 1, 2, 3, 8, 9, 10, 11}},
+		{ID: 1, CPUs: []int{4, 5, 6, 7, 12, 13, 14, 15}},
+	}, nodes)
+	require.Equal(t, []int{4, 5, 6, 7, 12, 13, 14, 15}, NodeCPUs(nodes, 1))
+	require.Nil(t, NodeCPUs(nodes, NoNode))
+	require.Equal(t, []int{1}, CPUNodes(nodes, []int{5, 12}))
+
+	nodes, err = NewTopology(afero.NewMemMapFs()).Nodes()
+	require.NoError(t, err)
+	require.Empty(t, nodes)
+}
+
+func TestDeviceNode(t *testing.T) {
+	topology := NewTopology(dualSocketFs(t))
+	tests := []struct {
+		name    string
+		syspath string
+		exp     int
+	}{
+		{"numa_node of a parent", nvmeSyspath, 1},
+		{"negative numa_node", "/sys/devices/pci0000:00/0000:00:1f.6/net/eno1", NoNode},
+		{"no numa_node", "/sys/devices/virtual/block/loop0", NoNode},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, err := topology.DeviceNode(test.syspath)
+			require.NoError(t, err)
+			require.Equal(t, test.exp, node)
+		})
+	}
+
+	node, err := topology.NicNode("eth0")
+	require.NoError(t, err)
+	require.Equal(t, 0, node)
+	node, err = topology.NicNode("lo")
+	require.NoError(t, err)
+	require.Equal(t, NoNode, node)
+}
+
+func TestCPUList(t *testing.T) {
+	tests := []struct {
+		list      string
+		cpus      []int
+		formatted string
+		expErr    bool
+	}{
+		{list: "", cpus: nil, formatted: ""},
+		{list: "3", cpus: []int{3}, formatted: "3"},
+		{list: "0-3,8-11\n", cpus: []int{0, 1, 2, 3, 8, 9, 10, 11}, formatted: "0-3,8-11"},
+		{list: "8,0-1,3", cpus: []int{0, 1, 3, 8}, formatted: "0-1,3,8"},
+		{list: "3-1", expErr: true},
+		{list: "a-b", expErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.list, func(t *testing.T) {
+			cpus, err := ParseCPUList(test.list)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.cpus, cpus)
+			require.Equal(t, test.formatted, FormatCPUList(cpus))
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		mask      string
+		cpus      []int
+		formatted string
+		expErr    bool
+	}{
+		{mask: "0x0000000f", cpus: []int{0, 1, 2, 3}, formatted: "0x0000000f"},
+		{mask: "f0\n", cpus: []int{4, 5, 6, 7}, formatted: "0x000000f0"},
+		{mask: "0x00000001,,0x00000003", cpus: []int{0, 1, 64}, formatted: "0x00000001,0x00000000,0x00000003"},
+		{mask: "00000f00,00000000", cpus: []int{40, 41, 42, 43}, formatted: "0x00000f00,0x00000000"},
+		{mask: "0xzz", expErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.mask, func(t *testing.T) {
+			cpus, err := ParseMask(test.mask)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.cpus, cpus)
+			require.Equal(t, test.formatted, FormatMask(cpus))
+		})
+	}
+}
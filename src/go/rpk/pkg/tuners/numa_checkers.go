@@ -0,0 +1,282 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build !windows
+
+package tuners
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/network"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
+	log "github.com/sirupsen/logrus"
+)
+
+// NewDirectoryNumaIRQsChecker checks that the IRQs of the devices that the
+// directory is stored on are only handled by CPUs of the NUMA node that each
+// device is attached to.
+func NewDirectoryNumaIRQsChecker(
+	dir string,
+	blockDevices disk.BlockDevices,
+	cpuMasks irq.CPUMasks,
+	topology numa.Topology,
+) Checker {
+	return NewEqualityChecker(
+		DiskIRQsNumaChecker,
+		fmt.Sprintf("Dir '%s' IRQs NUMA local", dir),
+		Warning,
+		true,
+		func() (interface{}, error) {
+			nodes, err := topology.Nodes()
+			if err != nil || len(nodes) < 2 {
+				return true, err
+			}
+			devices, err := blockDevices.GetDirectoryDevices(dir)
+			if err != nil {
+				return false, err
+			}
+			for _, device := range devices {
+				node, err := diskNode(device, blockDevices, topology)
+				if err != nil {
+					return false, err
+				}
+				diskInfoByType, err := blockDevices.GetDiskInfoByType([]string{device})
+				if err != nil {
+					return false, err
+				}
+				var IRQs []int
+				for _, diskInfo := range diskInfoByType {
+					IRQs = append(IRQs, diskInfo.Irqs...)
+				}
+				local, err := areIRQsNumaLocal(IRQs, numa.NodeCPUs(nodes, node), cpuMasks)
+				if err != nil || !local {
+					return false, err
+				}
+			}
+			return true, nil
+		},
+	)
+}
+
+// NewCPUSetNumaChecker checks that the CPUs that redpanda runs on, which are
+// the given cpuset or all CPUs if it is empty, are on the NUMA nodes that the
+// directory's devices and the given NICs are attached to. The required value
+// is the recommended --cpuset for 'rpk redpanda start'.
+func NewCPUSetNumaChecker(
+	cpuset string,
+	dir string,
+	nics []network.Nic,
+	blockDevices disk.BlockDevices,
+	topology numa.Topology,
+) Checker {
+	return &cpusetNumaChecker{
+		cpuset:       cpuset,
+		dir:          dir,
+		nics:         nics,
+		blockDevices: blockDevices,
+		topology:     topology,
+	}
+}
+
+type cpusetNumaChecker struct {
+	cpuset       string
+	dir          string
+	nics         []network.Nic
+	blockDevices disk.BlockDevices
+	topology     numa.Topology
+}
+
+func (*cpusetNumaChecker) ID() CheckerID {
+	return CPUSetNumaChecker
+}
+
+func (*cpusetNumaChecker) GetDesc() string {
+	return "Redpanda CPU set NUMA local"
+}
+
+func (*cpusetNumaChecker) GetSeverity() Severity {
+	return Warning
+}
+
+func (c *cpusetNumaChecker) GetRequiredAsString() string {
+	recommended, err := c.recommend()
+	if err != nil {
+		log.Debugf("Unable to recommend a NUMA local CPU set: %v", err)
+		return "all"
+	}
+	return recommended
+}
+
+func (c *cpusetNumaChecker) Check() *CheckResult {
+	res := &CheckResult{
+		CheckerID: c.ID(),
+		Desc:      c.GetDesc(),
+		Severity:  c.GetSeverity(),
+		Current:   c.cpuset,
+	}
+	if res.Current == "" {
+		res.Current = "all"
+	}
+	recommended, err := c.recommend()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Required = recommended
+	res.IsOk, res.Err = CPUSetWithin(c.cpuset, recommended)
+	return res
+}
+
+// recommend returns the CPUs of the NUMA nodes that the directory's devices
+// and the NICs are attached to in the cpuset(7) list format, or "all" if the
+// host has a single node or the devices are not attached to any.
+func (c *cpusetNumaChecker) recommend() (string, error) {
+	nodes, err := c.topology.Nodes()
+	if err != nil || len(nodes) < 2 {
+		return "all", err
+	}
+	localNodes := make(map[int]bool)
+	devices, err := c.blockDevices.GetDirectoryDevices(c.dir)
+	if err != nil {
+		return "", err
+	}
+	for _, device := range devices {
+		node, err := diskNode(device, c.blockDevices, c.topology)
+		if err != nil {
+			return "", err
+		}
+		localNodes[node] = true
+	}
+	for _, nic := range c.nics {
+		nicNodes, err := nicNodes(nic, c.topology)
+		if err != nil {
+			return "", err
+		}
+		for _, node := range nicNodes {
+			localNodes[node] = true
+		}
+	}
+	delete(localNodes, numa.NoNode)
+	if len(localNodes) == 0 || len(localNodes) == len(nodes) {
+		return "all", nil
+	}
+	var cpus []int
+	for node := range localNodes {
+		cpus = append(cpus, numa.NodeCPUs(nodes, node)...)
+	}
+	return numa.FormatCPUList(cpus), nil
+}
+
+// CPUSetWithin returns whether all CPUs of the cpuset are in the recommended
+// one. Both are in the cpuset(7) list format, an empty cpuset or "all" being
+// all CPUs.
+func CPUSetWithin(cpuset, recommended string) (bool, error) {
+	if recommended == "all" {
+		return true, nil
+	}
+	if cpuset == "" || cpuset == "all" {
+		return false, nil
+	}
+	cpus, err := numa.ParseCPUList(cpuset)
+	if err != nil {
+		return false, err
+	}
+	recommendedCPUs, err := numa.ParseCPUList(recommended)
+	if err != nil {
+		return false, err
+	}
+	return len(numa.Intersect(cpus, recommendedCPUs)) == len(cpus), nil
+}
+
+// ConfiguredCPUSet returns the value of the --cpuset flag among the given
+// redpanda start flags, or an empty string if it is not set.
+func ConfiguredCPUSet(flags []string) string {
+	for i, flag := range flags {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(flag), "=")
+		if strings.TrimLeft(name, "-") != "cpuset" {
+			continue
+		}
+		if hasValue {
+			return strings.TrimSpace(value)
+		}
+		if i+1 < len(flags) {
+			return strings.TrimSpace(flags[i+1])
+		}
+	}
+	return ""
+}
+
+func diskNode(
+	device string, blockDevices disk.BlockDevices, topology numa.Topology,
+) (int, error) {
+	syspath, err := blockDevices.GetDeviceSystemPath(path.Join("/dev", device))
+	if err != nil {
+		return numa.NoNode, err
+	}
+	return topology.DeviceNode(syspath)
+}
+
+// nicNodes returns the NUMA nodes of the NIC, which are the nodes of its
+// slaves for bond interfaces.
+func nicNodes(nic network.Nic, topology numa.Topology) ([]int, error) {
+	if nic.IsBondIface() {
+		slaves, err := nic.Slaves()
+		if err != nil {
+			return nil, err
+		}
+		var nodes []int
+		for _, slave := range slaves {
+			slaveNodes, err := nicNodes(slave, topology)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, slaveNodes...)
+		}
+		sort.Ints(nodes)
+		return nodes, nil
+	}
+	node, err := topology.NicNode(nic.Name())
+	if err != nil {
+		return nil, err
+	}
+	return []int{node}, nil
+}
+
+// areIRQsNumaLocal returns whether the affinity of all the IRQs is within the
+// given NUMA node CPUs. IRQs of devices that are not attached to a node, for
+// which there are no node CPUs, are always local.
+func areIRQsNumaLocal(
+	IRQs []int, nodeCPUs []int, cpuMasks irq.CPUMasks,
+) (bool, error) {
+	if len(nodeCPUs) == 0 {
+		return true, nil
+	}
+	for _, IRQ := range IRQs {
+		mask, err := cpuMasks.ReadIRQMask(IRQ)
+		if err != nil {
+			return false, err
+		}
+		cpus, err := numa.ParseMask(mask)
+		if err != nil {
+			return false, err
+		}
+		if len(numa.Intersect(cpus, nodeCPUs)) != len(cpus) {
+			log.Debugf("IRQ %d affinity '%s' is not within NUMA node CPUs %s",
+				IRQ, mask, numa.FormatCPUList(nodeCPUs))
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build !windows
+
+package tuners
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/disk"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/network"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// numaFs returns a dual socket host with nvme0n1 attached to node 0, nvme1n1
+// to node 1 and eth0 to node 0.
+func numaFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for path, content := range map[string]string{
+		"/sys/devices/system/node/node0/cpulist":         "0-3,8-11",
+		"/sys/devices/system/node/node1/cpulist":         "4-7,12-15",
+		"/sys/devices/pci0000:00/0000:00:04.0/numa_node": "0",
+		"/sys/devices/pci0000:80/0000:80:04.0/numa_node": "1",
+		"/sys/class/net/eth0/device/numa_node":           "0",
+	} {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0o644))
+	}
+	return fs
+}
+
+func numaBlockDevices(dirDevices ...string) disk.BlockDevices {
+	return &blockDevicesMock{
+		getDirectoryDevices: func(string) ([]string, error) {
+			return dirDevices, nil
+		},
+		getBlockDeviceSystemPath: func(path string) (string, error) {
+			return map[string]string{
+				"/dev/nvme0n1": "/sys/devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1",
+				"/dev/nvme1n1": "/sys/devices/pci0000:80/0000:80:04.0/nvme/nvme1/nvme1n1",
+			}[path], nil
+		},
+		getDiskInfoByType: func(devices []string) (map[disk.DiskType]disk.DevicesIRQs, error) {
+			IRQs := map[string][]int{
+				"nvme0n1": {30, 31},
+				"nvme1n1": {40, 41},
+			}[devices[0]]
+			return map[disk.DiskType]disk.DevicesIRQs{
+				disk.Nvme: {Devices: devices, Irqs: IRQs},
+			}, nil
+		},
+	}
+}
+
+func TestDirectoryNumaIRQsChecker(t *testing.T) {
+	tests := []struct {
+		name   string
+		device string
+		masks  map[int]string
+		exp    bool
+	}{
+		{
+			name:   "IRQs on the device node",
+			device: "nvme1n1",
+			masks:  map[int]string{40: "0x00000010", 41: "0x00001000"},
+			exp:    true,
+		},
+		{
+			name:   "IRQ on a remote node",
+			device: "nvme1n1",
+			masks:  map[int]string{40: "0x00000010", 41: "0x00000001"},
+			exp:    false,
+		},
+		{
+			name:   "IRQ spanning both nodes",
+			device: "nvme0n1",
+			masks:  map[int]string{30: "0x00000001", 31: "0x0000ffff"},
+			exp:    false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpuMasks := &cpuMasksMock{
+				readIRQMask: func(IRQ int) (string, error) {
+					return test.masks[IRQ], nil
+				},
+			}
+			res := NewDirectoryNumaIRQsChecker(
+				"/var/lib/redpanda/data",
+				numaBlockDevices(test.device),
+				cpuMasks,
+				numa.NewTopology(numaFs(t)),
+			).Check()
+			require.NoError(t, res.Err)
+			require.Equal(t, test.exp, res.IsOk)
+		})
+	}
+
+	// Single node hosts are always NUMA local.
+	res := NewDirectoryNumaIRQsChecker(
+		"/var/lib/redpanda/data",
+		numaBlockDevices("nvme1n1"),
+		&cpuMasksMock{},
+		numa.NewTopology(afero.NewMemMapFs()),
+	).Check()
+	require.NoError(t, res.Err)
+	require.True(t, res.IsOk)
+}
+
+// fakeHwLoc distributes elements one CPU each, round robin over the mask.
+type fakeHwLoc struct {
+	hwloc.HwLoc
+	all []int
+}
+
+func (h *fakeHwLoc) All() (string, error) {
+	return numa.FormatMask(h.all), nil
+}
+
+func (*fakeHwLoc) CheckIfMaskIsEmpty(mask string) bool {
+	cpus, _ := numa.ParseMask(mask)
+	return len(cpus) == 0
+}
+
+func (*fakeHwLoc) DistributeRestrict(n uint, mask string) ([]string, error) {
+	cpus, err := numa.ParseMask(mask)
+	if err != nil {
+		return nil, err
+	}
+	masks := make([]string, n)
+	for i := range masks {
+		masks[i] = numa.FormatMask([]int{cpus[i%len(cpus)]})
+	}
+	return masks, nil
+}
+
+func TestDiskIRQsNumaTuneAndCheck(t *testing.T) {
+	fs := numaFs(t)
+	for _, IRQ := range []int{30, 31, 40, 41} {
+		require.NoError(t, afero.WriteFile(fs, fmt.Sprintf("/proc/irq/%d/smp_affinity", IRQ), []byte("0000ffff"), 0o644))
+	}
+	devices := []string{"nvme0n1", "nvme1n1"}
+	topology := numa.NewTopology(fs)
+	blockDevices := numaBlockDevices(devices...)
+	cpuMasks := irq.NewCPUMasks(fs, &fakeHwLoc{all: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}}, executors.NewDirectExecutor())
+
+	// With rpk.irq_mode set to numa, the checkers expect the IRQs to be
+	// spread as the tuners spread them in that mode, so both pass once
+	// tuned.
+	mode := irq.ModeFromString("numa")
+
+	affinity := NewDisksIRQAffinityChecker(devices, "all", mode, blockDevices, cpuMasks)
+	local := NewDirectoryNumaIRQsChecker("/var/lib/redpanda/data", blockDevices, cpuMasks, topology)
+	for _, c := range []Checker{affinity, local} {
+		res := c.Check()
+		require.NoError(t, res.Err)
+		require.False(t, res.IsOk, "%s passed before tuning", res.Desc)
+	}
+
+	res := NewDiskIRQsAffinityTuner(devices, "all", mode, blockDevices, cpuMasks, executors.NewDirectExecutor()).Tune()
+	require.False(t, res.IsFailed())
+
+	for _, c := range []Checker{affinity, local} {
+		res := c.Check()
+		require.NoError(t, res.Err)
+		require.True(t, res.IsOk, "%s failed after tuning", res.Desc)
+	}
+	mask, err := cpuMasks.ReadIRQMask(40)
+	require.NoError(t, err)
+	cpus, err := numa.ParseMask(mask)
+	require.NoError(t, err)
+	require.Subset(t, []int{4, 5, 6, 7, 12, 13, 14, 15}, cpus)
+}
+
+func TestCPUSetNumaChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		cpuset      string
+		devices     []string
+		nics        []string
+		expRequired string
+		expCurrent  string
+		exp         bool
+	}{
+		{
+			name:        "all CPUs with the disk on node 1",
+			devices:     []string{"nvme1n1"},
+			expRequired: "4-7,12-15",
+			expCurrent:  "all",
+			exp:         false,
+		},
+		{
+			name:        "cpuset on the disk node",
+			cpuset:      "4-7",
+			devices:     []string{"nvme1n1"},
+			expRequired: "4-7,12-15",
+			expCurrent:  "4-7",
+			exp:         true,
+		},
+		{
+			name:        "cpuset on the wrong node",
+			cpuset:      "0-7",
+			devices:     []string{"nvme0n1"},
+			nics:        []string{"eth0"},
+			expRequired: "0-3,8-11",
+			expCurrent:  "0-7",
+			exp:         false,
+		},
+		{
+			name:        "disk and NIC on different nodes",
+			devices:     []string{"nvme1n1"},
+			nics:        []string{"eth0"},
+			expRequired: "all",
+			expCurrent:  "all",
+			exp:         true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := numaFs(t)
+			var nics []network.Nic
+			for _, name := range test.nics {
+				nics = append(nics, network.NewNic(fs, nil, nil, nil, name))
+			}
+			res := NewCPUSetNumaChecker(
+				test.cpuset,
+				"/var/lib/redpanda/data",
+				nics,
+				numaBlockDevices(test.devices...),
+				numa.NewTopology(fs),
+			).Check()
+			require.NoError(t, res.Err)
+			require.Equal(t, test.expRequired, res.Required)
+			require.Equal(t, test.expCurrent, res.Current)
+			require.Equal(t, test.exp, res.IsOk)
+		})
+	}
+}
+
+func TestConfiguredCPUSet(t *testing.T) {
+	require.Equal(t, "", ConfiguredCPUSet(nil))
+	require.Equal(t, "0-3", ConfiguredCPUSet([]string{"--smp=4", "--cpuset=0-3"}))
+	require.Equal(t, "4-7", ConfiguredCPUSet([]string{"--cpuset", "4-7", "--overprovisioned"}))
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/hwloc"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/irq"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/network"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/numa"
 	"github.com/spf13/afero"
 )
 
@@ -64,6 +66,9 @@ const (
 	KernelVersion
 	WriteCachePolicyChecker
 	BallastFileChecker
+	DiskIRQsNumaChecker
+	NicIRQsNumaChecker
+	CPUSetNumaChecker
 )
 
 func NewConfigChecker(conf *config.Config) Checker {
@@ -172,7 +177,7 @@ func NewIOConfigFileExistanceChecker(fs afero.Fs, filePath string) Checker {
 func NewBallastFileChecker(fs afero.Fs, conf *config.Config) Checker {
 	return NewFileExistanceChecker(
 		fs,
-		IoConfigFileChecker,
+		BallastFileChecker,
 		"Ballast file present",
 		Warning,
 		conf.Rpk.BallastFilePath,
@@ -211,7 +216,11 @@ func RedpandaCheckers(
 	nomergesChecker := NewDirectoryNomergesChecker(config.Redpanda.Directory, deviceFeatures, blockDevices)
 	balanceService := irq.NewBalanceService(fs, proc, executor, timeout)
 	cpuMasks := irq.NewCPUMasks(fs, hwloc.NewHwLocCmd(proc, timeout), executor)
-	dirIRQAffinityChecker := NewDirectoryIRQAffinityChecker(config.Redpanda.Directory, "all", irq.Default, blockDevices, cpuMasks)
+	topology := numa.NewTopology(fs)
+	// The IRQs are expected to be spread as the tuners spread them with
+	// the configured mode, such that the checkers agree with tuning.
+	irqMode := irq.ModeFromString(config.Rpk.IrqMode)
+	dirIRQAffinityChecker := NewDirectoryIRQAffinityChecker(config.Redpanda.Directory, "all", irqMode, blockDevices, cpuMasks)
 	dirIRQAffinityStaticChecker := NewDirectoryIRQsAffinityStaticChecker(config.Redpanda.Directory, blockDevices, balanceService)
 	if len(config.Redpanda.KafkaAPI) == 0 {
 		return nil, errors.New("'redpanda.kafka_api' is empty")
@@ -225,6 +234,17 @@ func RedpandaCheckers(
 	}
 	netCheckersFactory := NewNetCheckersFactory(
 		fs, irqProcFile, irqDeviceInfo, ethtool, balanceService, cpuMasks)
+	var nics []network.Nic
+	for _, iface := range interfaces {
+		nics = append(nics, network.NewNic(fs, irqProcFile, irqDeviceInfo, ethtool, iface))
+	}
+	cpusetNumaChecker := NewCPUSetNumaChecker(
+		ConfiguredCPUSet(config.Rpk.AdditionalStartFlags),
+		config.Redpanda.Directory,
+		nics,
+		blockDevices,
+		topology,
+	)
 	checkers := map[CheckerID][]Checker{
 		ConfigFileChecker:             {NewConfigChecker(config)},
 		IoConfigFileChecker:           {NewIOConfigFileExistanceChecker(fs, ioConfigFile)},
@@ -244,8 +264,8 @@ func RedpandaCheckers(
 		ListenBacklogChecker:          {netCheckersFactory.NewListenBacklogChecker()},
 		RfsTableEntriesChecker:        {netCheckersFactory.NewRfsTableSizeChecker()},
 		NicIRQsAffinitStaticChecker:   {netCheckersFactory.NewNicIRQAffinityStaticChecker(interfaces)},
-		NicIRQsAffinitChecker:         netCheckersFactory.NewNicIRQAffinityCheckers(interfaces, irq.Default, "all"),
-		NicRpsChecker:                 netCheckersFactory.NewNicRpsSetCheckers(interfaces, irq.Default, "all"),
+		NicIRQsAffinitChecker:         netCheckersFactory.NewNicIRQAffinityCheckers(interfaces, irqMode, "all"),
+		NicRpsChecker:                 netCheckersFactory.NewNicRpsSetCheckers(interfaces, irqMode, "all"),
 		NicRfsChecker:                 netCheckersFactory.NewNicRfsCheckers(interfaces),
 		NicXpsChecker:                 netCheckersFactory.NewNicXpsCheckers(interfaces),
 		MaxAIOEvents:                  {NewMaxAIOEventsChecker(fs)},
@@ -253,6 +273,13 @@ func RedpandaCheckers(
 		Swappiness:                    {NewSwappinessChecker(fs)},
 		KernelVersion:                 {NewKernelVersionChecker(GetKernelVersion)},
 		BallastFileChecker:            {NewBallastFileChecker(fs, config)},
+		CPUSetNumaChecker:             {cpusetNumaChecker},
+	}
+	// Only the numa mode keeps IRQs NUMA local, the other modes spread
+	// them over all CPUs, which the affinity checkers check.
+	if irqMode == irq.Numa {
+		checkers[DiskIRQsNumaChecker] = []Checker{NewDirectoryNumaIRQsChecker(config.Redpanda.Directory, blockDevices, cpuMasks, topology)}
+		checkers[NicIRQsNumaChecker] = netCheckersFactory.NewNicNumaIRQsCheckers(interfaces)
 	}
 
 	v, err := cloud.AvailableVendor()
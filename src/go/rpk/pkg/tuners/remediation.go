@@ -0,0 +1,70 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build !windows
+
+package tuners
+
+import "strings"
+
+// Remediation is how to fix a failed check: either running the tuner that
+// fixes it, with any extra arguments, or a manual step.
+type Remediation struct {
+	Tuner  string   `json:"tuner,omitempty"`
+	Args   []string `json:"args,omitempty"`
+	Manual string   `json:"manual,omitempty"`
+}
+
+// String returns the command that runs the tuner, or the manual step.
+func (r Remediation) String() string {
+	if r.Tuner != "" {
+		return strings.Join(append([]string{"rpk redpanda tune", r.Tuner}, r.Args...), " ")
+	}
+	return r.Manual
+}
+
+var remediations = map[CheckerID]Remediation{
+	ConfigFileChecker:             {Manual: "fix the errors in the redpanda config file"},
+	DataDirAccessChecker:          {Manual: "make the data directory writable by the user running redpanda"},
+	DiskSpaceChecker:              {Manual: "free up space on, or grow, the data directory's partition"},
+	FreeMemChecker:                {Manual: "add memory, or run redpanda on fewer CPUs"},
+	SwapChecker:                   {Manual: "enable swap"},
+	FsTypeChecker:                 {Manual: "place the data directory on an XFS filesystem"},
+	IoConfigFileChecker:           {Manual: "run 'rpk iotune' to generate the I/O config file"},
+	TransparentHugePagesChecker:   {Tuner: "transparent_hugepages"},
+	NtpChecker:                    {Manual: "enable time synchronization, such as with chronyd or ntpd"},
+	SchedulerChecker:              {Tuner: "disk_scheduler"},
+	NomergesChecker:               {Tuner: "disk_nomerges"},
+	DiskIRQsAffinityStaticChecker: {Tuner: "disk_irq"},
+	DiskIRQsAffinityChecker:       {Tuner: "disk_irq"},
+	FstrimChecker:                 {Tuner: "fstrim"},
+	NicIRQsAffinitChecker:         {Tuner: "net"},
+	NicIRQsAffinitStaticChecker:   {Tuner: "net"},
+	NicRfsChecker:                 {Tuner: "net"},
+	NicXpsChecker:                 {Tuner: "net"},
+	NicRpsChecker:                 {Tuner: "net"},
+	NicNTupleChecker:              {Tuner: "net"},
+	RfsTableEntriesChecker:        {Tuner: "net"},
+	ListenBacklogChecker:          {Tuner: "net"},
+	SynBacklogChecker:             {Tuner: "net"},
+	MaxAIOEvents:                  {Tuner: "aio_events"},
+	ClockSource:                   {Tuner: "clocksource"},
+	Swappiness:                    {Tuner: "swappiness"},
+	KernelVersion:                 {Manual: "upgrade the kernel to 4.19 or later"},
+	WriteCachePolicyChecker:       {Tuner: "disk_write_cache"},
+	BallastFileChecker:            {Tuner: "ballast_file"},
+	DiskIRQsNumaChecker:           {Tuner: "disk_irq", Args: []string{"--mode", "numa"}},
+	NicIRQsNumaChecker:            {Tuner: "net", Args: []string{"--mode", "numa"}},
+	CPUSetNumaChecker:             {Manual: "pin redpanda to the required CPUs with 'rpk redpanda start --cpuset', or with --cpuset in rpk.additional_start_flags"},
+}
+
+// RemediationFor returns how to fix a failed check of the checker.
+func RemediationFor(id CheckerID) Remediation {
+	return remediations[id]
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+//go:build linux
+
+package tuners_test
+
+import (
+	"testing"
+
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/tuners/factory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemediationFor(t *testing.T) {
+	for id := tuners.CheckerID(tuners.ConfigFileChecker); id <= tuners.CPUSetNumaChecker; id++ {
+		r := tuners.RemediationFor(id)
+		require.NotEmpty(t, r.String(), "checker %d has no remediation", id)
+		if r.Tuner != "" {
+			require.True(t, factory.IsTunerAvailable(r.Tuner), "checker %d remediation tuner %q is unknown", id, r.Tuner)
+		}
+	}
+	require.Equal(t, "rpk redpanda tune disk_irq", tuners.RemediationFor(tuners.DiskIRQsAffinityChecker).String())
+	require.Equal(t, "rpk redpanda tune disk_irq --mode numa", tuners.RemediationFor(tuners.DiskIRQsNumaChecker).String())
+}